@@ -129,6 +129,11 @@ func bindProxy() {
 	if err != nil {
 		exitIfError(err)
 	}
+	err = viper.BindEnv(params.NoProxyKey, params.CxNoProxyEnv, params.NoProxyEnv, params.NoProxyLowerCaseEnv)
+	if err != nil {
+		exitIfError(err)
+	}
+	viper.SetDefault(params.NoProxyKey, "")
 }
 
 func exitListener() {
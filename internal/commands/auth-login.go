@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/checkmarx/ast-cli/internal/wrappers/configuration"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	failedLogin                 = "Failed to login"
+	deviceCodeGrantType         = "urn:ietf:params:oauth:grant-type:device_code"
+	authorizationPendingError   = "authorization_pending"
+	slowDownError               = "slow_down"
+	defaultDevicePollSeconds    = 5
+	slowDownSeconds             = 5
+	browserLoginTimeout         = 5 * time.Minute
+	loginCallbackPath           = "/callback"
+	pkceVerifierSize            = 32
+	loginStateSize              = 16
+	loginCallbackReadTimeout    = 10 * time.Second
+	loginCallbackSuccessMessage = "Login successful, you can close this window and return to the CLI."
+	authMethodAPIKey            = "API key"
+	authMethodClientCredentials = "Client credentials"
+	authMethodLogin             = "User login"
+	notLoggedIn                 = "Not logged in - please run cx auth login, or provide client-id and client-secret or apikey"
+)
+
+// loginPollUnit Duration of a second of the device flow intervals, shortened by the tests
+var loginPollUnit = time.Second
+
+type authStatusView struct {
+	Method    string
+	Profile   string
+	Tenant    string
+	User      string
+	ExpiresAt string `format:"name:Login expires at"`
+}
+
+func authLoginSubCommands(authWrapper wrappers.AuthWrapper) []*cobra.Command {
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Login to Checkmarx One as a user",
+		Long: "The login command authenticates a user with the OAuth 2.0 device authorization flow, or with the browser " +
+			"and a localhost callback. The refresh token is stored in the configuration directory and used when no client credentials " +
+			"or API key are provided. It is encrypted with a key kept in the OS credential store (Keychain, Credential Manager or " +
+			"Secret Service). Without one, or with CX_LOGIN_KEY_STORE=file, the key is stored next to the refresh token, readable " +
+			"by the user only, and doesn't protect it from whoever can read the user's files: use cx auth logout on shared machines.",
+		Example: heredoc.Doc(
+			`
+			$ cx auth login --tenant <tenant> --base-auth-uri <IAM URI>
+			$ cx auth login --browser
+		`,
+		),
+		RunE: runLogin(authWrapper),
+	}
+	loginCmd.PersistentFlags().Bool(params.BrowserFlag, false, "Login in the browser with PKCE and a localhost callback, instead of with a device code")
+	loginCmd.PersistentFlags().Int(params.CallbackPortFlag, 0, "Port of the localhost callback of the browser login, a free port by default")
+	loginCmd.PersistentFlags().String(params.LoginClientIDFlag, wrappers.LoginClientID, "OAuth client used to login")
+
+	logoutCmd := &cobra.Command{
+		Use:     "logout",
+		Short:   "Logout the user logged in with cx auth login",
+		Long:    "The logout command revokes the session of the user and removes the stored refresh token",
+		Example: "$ cx auth logout",
+		RunE:    runLogout(authWrapper),
+	}
+
+	statusCmd := &cobra.Command{
+		Use:     "status",
+		Short:   "Show the authentication in use",
+		Long:    "The status command shows which credentials the CLI uses: client credentials, an API key or the login of a user",
+		Example: "$ cx auth status --format json",
+		RunE:    runAuthStatus(),
+	}
+	addFormatFlag(statusCmd, printer.FormatList, printer.FormatJSON)
+
+	return []*cobra.Command{loginCmd, logoutCmd, statusCmd}
+}
+
+func runLogin(authWrapper wrappers.AuthWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		clientID, _ := cmd.Flags().GetString(params.LoginClientIDFlag)
+		browser, _ := cmd.Flags().GetBool(params.BrowserFlag)
+		var credentialsInfo *wrappers.ClientCredentialsInfo
+		var err error
+		if browser {
+			port, _ := cmd.Flags().GetInt(params.CallbackPortFlag)
+			credentialsInfo, err = loginWithBrowser(cmd, authWrapper, clientID, port)
+		} else {
+			credentialsInfo, err = loginWithDeviceCode(cmd, authWrapper, clientID)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedLogin)
+		}
+		if credentialsInfo.RefreshToken == "" {
+			return errors.Errorf("%s: The auth server didn't return a refresh token", failedLogin)
+		}
+		login := wrappers.NewLogin(clientID, credentialsInfo)
+		if err = wrappers.SaveLogin(login); err != nil {
+			return errors.Wrapf(err, "%s", failedLogin)
+		}
+		if login.Username != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Successfully logged in as %s\n", login.Username)
+		} else {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Successfully logged in")
+		}
+		return nil
+	}
+}
+
+// loginWithDeviceCode Show the user code, then poll the token endpoint until the user approves it
+func loginWithDeviceCode(cmd *cobra.Command, authWrapper wrappers.AuthWrapper, clientID string) (*wrappers.ClientCredentialsInfo, error) {
+	deviceAuthorization, err := authWrapper.StartDeviceAuthorization(clientID)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		"Open %s and enter the code %s\n",
+		deviceAuthorization.VerificationURI,
+		deviceAuthorization.UserCode,
+	)
+	if deviceAuthorization.VerificationURIComplete != "" {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Or open %s\n", deviceAuthorization.VerificationURIComplete)
+	}
+
+	interval := deviceAuthorization.Interval
+	if interval <= 0 {
+		interval = defaultDevicePollSeconds
+	}
+	deadline := time.Now().Add(time.Duration(deviceAuthorization.ExpiresIn) * loginPollUnit)
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceAuthorization.DeviceCode},
+		"client_id":   {clientID},
+	}
+	for {
+		time.Sleep(time.Duration(interval) * loginPollUnit)
+		credentialsInfo, tokenErr := authWrapper.RequestLoginToken(form)
+		if tokenErr == nil {
+			return credentialsInfo, nil
+		}
+		oauthErr, ok := tokenErr.(*wrappers.OAuthError)
+		if !ok || (oauthErr.Code != authorizationPendingError && oauthErr.Code != slowDownError) {
+			return nil, tokenErr
+		}
+		if oauthErr.Code == slowDownError {
+			interval += slowDownSeconds
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("The device code expired before the login was approved")
+		}
+	}
+}
+
+// loginWithBrowser Run the authorization code flow with PKCE, receiving the code on a localhost callback
+func loginWithBrowser(cmd *cobra.Command, authWrapper wrappers.AuthWrapper, clientID string, port int) (*wrappers.ClientCredentialsInfo, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to listen for the login callback")
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), loginCallbackPath)
+	verifier, err := randomURLString(pkceVerifierSize)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomURLString(loginStateSize)
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+	authorizationURL, err := authWrapper.GetAuthorizationURL(url.Values{
+		"client_id":             {clientID},
+		"response_type":         {"code"},
+		"redirect_uri":          {redirectURI},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Opening %s\nIf the browser doesn't open, open the link to login\n", authorizationURL)
+	if browserErr := openBrowser(authorizationURL); browserErr != nil {
+		log.Printf("Failed to open the browser: %v", browserErr)
+	}
+
+	code, err := waitForAuthorizationCode(listener, state, browserLoginTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return authWrapper.RequestLoginToken(url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {clientID},
+		"code_verifier": {verifier},
+	})
+}
+
+// waitForAuthorizationCode Serve the callback until it receives the code of the login state
+func waitForAuthorizationCode(listener net.Listener, state string, timeout time.Duration) (string, error) {
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(loginCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		result := callbackResult{code: query.Get("code")}
+		if query.Get("state") != state {
+			result = callbackResult{err: errors.New("The login callback state doesn't match the login request")}
+		} else if query.Get("error") != "" {
+			result = callbackResult{err: &wrappers.OAuthError{Code: query.Get("error"), Description: query.Get("error_description")}}
+		} else if result.code == "" {
+			result = callbackResult{err: errors.New("The login callback has no authorization code")}
+		}
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = fmt.Fprintln(w, loginCallbackSuccessMessage)
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: loginCallbackReadTimeout}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		_ = server.Shutdown(context.Background())
+	}()
+
+	select {
+	case result := <-results:
+		return result.code, result.err
+	case <-time.After(timeout):
+		return "", errors.New("Timed out waiting for the login in the browser")
+	}
+}
+
+func randomURLString(size int) (string, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return "", errors.Wrap(err, "Failed to generate the login secrets")
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+func openBrowser(link string) error {
+	switch runtime.GOOS {
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", link).Start()
+	case "darwin":
+		return exec.Command("open", link).Start()
+	default:
+		return exec.Command("xdg-open", link).Start()
+	}
+}
+
+func runLogout(authWrapper wrappers.AuthWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		login, err := wrappers.LoadLogin()
+		if err != nil {
+			return errors.Wrapf(err, "%s", "Failed to logout")
+		}
+		if login == nil {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Not logged in")
+			return nil
+		}
+		if err = authWrapper.RevokeLogin(login.ClientID, login.RefreshToken); err != nil {
+			log.Printf("Failed to revoke the session on the auth server: %v", err)
+		}
+		if err = wrappers.DeleteLogin(); err != nil {
+			return errors.Wrapf(err, "%s", "Failed to logout")
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Successfully logged out")
+		return nil
+	}
+}
+
+func runAuthStatus() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		view := authStatusView{
+			Profile: configuration.GetProfile(),
+			Tenant:  viper.GetString(params.TenantKey),
+		}
+		if viper.GetString(params.AstAPIKey) != "" {
+			view.Method = authMethodAPIKey
+		} else if viper.GetString(params.AccessKeyIDConfigKey) != "" {
+			view.Method = authMethodClientCredentials
+			view.User = viper.GetString(params.AccessKeyIDConfigKey)
+		} else {
+			login, err := wrappers.LoadLogin()
+			if err != nil {
+				return errors.Wrapf(err, "%s", "Failed reading the login")
+			}
+			if login == nil {
+				return errors.New(notLoggedIn)
+			}
+			view.Method = authMethodLogin
+			view.User = login.Username
+			view.ExpiresAt = "Never"
+			if !login.ExpiresAt.Equal(wrappers.LoginNeverExpires) {
+				view.ExpiresAt = login.ExpiresAt.Format(time.RFC3339)
+			}
+		}
+		return printByFormat(cmd, view)
+	}
+}
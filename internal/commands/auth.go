@@ -47,6 +47,8 @@ func NewAuthCommand(authWrapper wrappers.AuthWrapper) *cobra.Command {
 			`
 			$ cx auth validate
 			Successfully authenticated to Checkmarx One server!
+			$ cx auth login
+			Open https://<Keycloak server URI>/device and enter the code XXXX-XXXX
 			$ cx auth register -u <Username> -p <Password> --base-uri https://<Keycloak server URI>
 			CX_CLIENT_ID=XX
 			CX_CLIENT_SECRET=XX
@@ -113,6 +115,7 @@ func NewAuthCommand(authWrapper wrappers.AuthWrapper) *cobra.Command {
 		RunE: validLogin(),
 	}
 	authCmd.AddCommand(createClientCmd, validLoginCmd)
+	authCmd.AddCommand(authLoginSubCommands(authWrapper)...)
 	return authCmd
 }
 
@@ -121,7 +124,8 @@ func validLogin() func(cmd *cobra.Command, args []string) error {
 		clientID := viper.GetString(params.AccessKeyIDConfigKey)
 		clientSecret := viper.GetString(params.AccessKeySecretConfigKey)
 		apiKey := viper.GetString(params.AstAPIKey)
-		if (clientID != "" && clientSecret != "") || apiKey != "" {
+		login, _ := wrappers.LoadLogin()
+		if (clientID != "" && clientSecret != "") || apiKey != "" || (clientID == "" && login != nil) {
 			authWrapper := wrappers.NewAuthHTTPWrapper()
 			authWrapper.SetPath(viper.GetString(params.ScansPathKey))
 			err := authWrapper.ValidateLogin()
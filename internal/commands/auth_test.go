@@ -3,11 +3,18 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/checkmarx/ast-cli/internal/params"
 	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/spf13/viper"
 	"gotest.tools/assert"
 )
 
@@ -69,3 +76,81 @@ func TestRunCreateOath2ClientCommandNoRoles(t *testing.T) {
 	)
 	assert.Equal(t, err.Error(), "required flag(s) \"roles\" not set")
 }
+
+// useTestLoginStore Store the login of the test in a temporary directory
+func useTestLoginStore(t *testing.T) {
+	viper.Set(params.TokenCachePathKey, filepath.Join(t.TempDir(), "tokens.cache"))
+	viper.Set(params.LoginKeyStoreKey, params.LoginKeyStoreFile)
+	pollUnit := loginPollUnit
+	loginPollUnit = 0
+	t.Cleanup(func() {
+		viper.Set(params.TokenCachePathKey, "")
+		viper.Set(params.LoginKeyStoreKey, params.LoginKeyStoreKeychain)
+		loginPollUnit = pollUnit
+	})
+}
+
+func TestAuthLoginStatusLogout(t *testing.T) {
+	useTestLoginStore(t)
+	err := execCmdNotNilAssertion(t, "auth", "status")
+	assert.Equal(t, err.Error(), notLoggedIn)
+
+	buffer := bytes.NewBufferString("")
+	cmd := createASTTestCommand()
+	cmd.SetOut(buffer)
+	assert.NilError(t, executeTestCommand(cmd, "auth", "login"))
+	assert.Assert(t, strings.Contains(buffer.String(), "Open https://iam.mock/device and enter the code MOCK-CODE"), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "Successfully logged in"), buffer.String())
+
+	login, err := wrappers.LoadLogin()
+	assert.NilError(t, err)
+	assert.Equal(t, login.RefreshToken, "MOCK")
+	assert.Equal(t, login.ClientID, wrappers.LoginClientID)
+
+	buffer.Reset()
+	cmd = createASTTestCommand()
+	cmd.SetOut(buffer)
+	assert.NilError(t, executeTestCommand(cmd, "auth", "status", "--format", "json"))
+	assert.Assert(t, strings.Contains(buffer.String(), "\"Method\":\"User login\""), buffer.String())
+
+	execCmdNilAssertion(t, "auth", "logout")
+	login, err = wrappers.LoadLogin()
+	assert.NilError(t, err)
+	assert.Assert(t, login == nil)
+	execCmdNilAssertion(t, "auth", "logout")
+}
+
+func TestAuthStatusAPIKey(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	cmd := createASTTestCommand()
+	cmd.SetOut(buffer)
+	assert.NilError(t, executeTestCommand(cmd, "auth", "status", "--apikey", "MOCK", "--format", "json"))
+	assert.Assert(t, strings.Contains(buffer.String(), "\"Method\":\"API key\""), buffer.String())
+}
+
+func TestWaitForAuthorizationCode(t *testing.T) {
+	callback := func(query string) (string, error) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NilError(t, err)
+		defer func() {
+			_ = listener.Close()
+		}()
+		go func() {
+			res, getErr := http.Get(fmt.Sprintf("http://%s%s?%s", listener.Addr().String(), loginCallbackPath, query))
+			if getErr == nil {
+				_ = res.Body.Close()
+			}
+		}()
+		return waitForAuthorizationCode(listener, "STATE", time.Minute)
+	}
+
+	code, err := callback("state=STATE&code=CODE")
+	assert.NilError(t, err)
+	assert.Equal(t, code, "CODE")
+
+	_, err = callback("state=OTHER&code=CODE")
+	assert.ErrorContains(t, err, "state doesn't match")
+
+	_, err = callback("state=STATE&error=access_denied&error_description=denied")
+	assert.Equal(t, err.Error(), "access_denied: denied")
+}
@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+)
+
+const (
+	failedReadingCodeOwners = "Failed reading the CODEOWNERS file"
+	unownedLabel            = "Unowned"
+)
+
+var codeOwnersSectionRegexp = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?(.*)$`)
+
+type codeOwnersRule struct {
+	pattern string
+	regexp  *regexp.Regexp
+	owners  []string
+	exclude bool
+	section int
+	line    int
+}
+
+type codeOwnersSection struct {
+	name   string
+	owners []string
+}
+
+// codeOwners The rules of a CODEOWNERS file in GitHub, GitLab or Bitbucket syntax.
+// The last matching rule of every section wins and the owners of all sections are combined.
+type codeOwners struct {
+	rules    []codeOwnersRule
+	sections []codeOwnersSection
+}
+
+func readCodeOwners(path string) (*codeOwners, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", failedReadingCodeOwners)
+	}
+	owners, err := parseCodeOwners(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s", failedReadingCodeOwners, path)
+	}
+	return owners, nil
+}
+
+func parseCodeOwners(content string) (*codeOwners, error) {
+	owners := &codeOwners{sections: []codeOwnersSection{{}}}
+	// Bitbucket groups, defined as @@@name followed by their members and referenced as @@name
+	groups := make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if index := strings.Index(line, " #"); index >= 0 {
+			line = strings.TrimSpace(line[:index])
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "CODEOWNERS.") {
+			continue
+		}
+		fields := splitCodeOwnersFields(line)
+		switch {
+		case strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^["):
+			matches := codeOwnersSectionRegexp.FindStringSubmatch(line)
+			if matches == nil {
+				return nil, errors.Errorf("Invalid section %s at line %d", line, lineNumber)
+			}
+			owners.sections = append(owners.sections, codeOwnersSection{
+				name:   matches[1],
+				owners: strings.Fields(matches[2]),
+			})
+		case strings.HasPrefix(line, "@@@"):
+			groups[strings.TrimPrefix(fields[0], "@@@")] = fields[1:]
+		default:
+			pattern := strings.TrimPrefix(fields[0], "\\")
+			rule := codeOwnersRule{pattern: pattern, section: len(owners.sections) - 1, line: lineNumber}
+			if strings.HasPrefix(pattern, "!") {
+				// Bitbucket exclusions leave the matching paths without owners
+				rule.pattern = strings.TrimPrefix(pattern, "!")
+				rule.exclude = true
+			} else {
+				rule.owners = fields[1:]
+			}
+			rule.regexp = codeOwnersPatternToRegexp(rule.pattern)
+			owners.rules = append(owners.rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range owners.rules {
+		resolved, err := resolveCodeOwners(owners.rules[i].owners, groups)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", owners.rules[i].line)
+		}
+		owners.rules[i].owners = resolved
+	}
+	return owners, nil
+}
+
+// splitCodeOwnersFields Split a line by spaces, keeping the spaces escaped with a backslash
+func splitCodeOwnersFields(line string) []string {
+	fields := strings.Fields(strings.ReplaceAll(line, "\\ ", "\x00"))
+	for i := range fields {
+		fields[i] = strings.ReplaceAll(fields[i], "\x00", " ")
+	}
+	return fields
+}
+
+// resolveCodeOwners Expand the Bitbucket groups and drop the Bitbucket approval checks such as Check(2)
+func resolveCodeOwners(owners []string, groups map[string][]string) ([]string, error) {
+	var resolved []string
+	for _, owner := range owners {
+		switch {
+		case strings.HasPrefix(owner, "Check("):
+			continue
+		case strings.HasPrefix(owner, "@@"):
+			members, ok := groups[strings.TrimPrefix(owner, "@@")]
+			if !ok {
+				return nil, errors.Errorf("Undefined group %s", owner)
+			}
+			resolved = append(resolved, members...)
+		default:
+			resolved = append(resolved, owner)
+		}
+	}
+	return resolved, nil
+}
+
+// codeOwnersPatternToRegexp Convert a gitignore style pattern. A pattern naming a directory, with a trailing
+// slash or a last segment without wildcard, also matches anything under it, while a wildcard in the last
+// segment only matches the entries of its directory: docs/* doesn't match docs/guide/intro.md
+func codeOwnersPatternToRegexp(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	lastSegment := pattern[strings.LastIndex(pattern, "/")+1:]
+	nested := !strings.ContainsAny(lastSegment, "*?")
+
+	var expression strings.Builder
+	if anchored {
+		expression.WriteString("^")
+	} else {
+		expression.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case directory:
+		expression.WriteString("/.*$")
+	case nested:
+		expression.WriteString("(?:/.*)?$")
+	default:
+		expression.WriteString("$")
+	}
+	return regexp.MustCompile(expression.String())
+}
+
+// ownersOf Get the owners of the path, none when the path is unowned.
+// A matching rule without owners inherits the default owners of its section.
+func (c *codeOwners) ownersOf(path string) []string {
+	if c == nil || path == "" {
+		return nil
+	}
+	path = strings.TrimPrefix(strings.TrimPrefix(strings.ReplaceAll(path, "\\", "/"), "./"), "/")
+	matched := make([]*codeOwnersRule, len(c.sections))
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		rule := &c.rules[i]
+		if matched[rule.section] == nil && rule.regexp.MatchString(path) {
+			matched[rule.section] = rule
+		}
+	}
+	var owners []string
+	seen := make(map[string]bool)
+	for section, rule := range matched {
+		if rule == nil {
+			continue
+		}
+		ruleOwners := rule.owners
+		if len(ruleOwners) == 0 && !rule.exclude {
+			ruleOwners = c.sections[section].owners
+		}
+		for _, owner := range ruleOwners {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// getResultLocation Get the file of a result: the first SAST node, the KICS filename or the SCA manifest
+func getResultLocation(result *wrappers.ScanResult) string {
+	switch result.Type {
+	case commonParams.SastType:
+		if len(result.ScanResultData.Nodes) > 0 {
+			return result.ScanResultData.Nodes[0].FileName
+		}
+	case commonParams.KicsType, commonParams.IacType:
+		return result.ScanResultData.Filename
+	case commonParams.ScaType:
+		packages := result.ScanResultData.ScaPackageCollection
+		if packages != nil {
+			for _, location := range packages.Locations {
+				if location != nil && *location != "" {
+					return *location
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// setResultsOwners Annotate every result with the owners of its location
+func setResultsOwners(results *wrappers.ScanResultsCollection, owners *codeOwners) {
+	if results == nil {
+		return
+	}
+	for _, result := range results.Results {
+		result.Owners = owners.ownersOf(getResultLocation(result))
+	}
+}
+
+// toOwnersSummary Count the exploitable results of every owner, the results without owners being counted as unowned
+func toOwnersSummary(summary *wrappers.ResultSummary, results *wrappers.ScanResultsCollection) []wrappers.OwnerSummary {
+	var ownersSummary []wrappers.OwnerSummary
+	if results == nil {
+		return ownersSummary
+	}
+	indexes := make(map[string]int)
+	for _, result := range results.Results {
+		if !contains(summary.EnginesEnabled, strings.TrimSpace(result.Type)) || !isExploitable(result.State) {
+			continue
+		}
+		owners := result.Owners
+		if len(owners) == 0 {
+			owners = []string{unownedLabel}
+		}
+		for _, owner := range owners {
+			index, ok := indexes[owner]
+			if !ok {
+				index = len(ownersSummary)
+				indexes[owner] = index
+				ownersSummary = append(ownersSummary, wrappers.OwnerSummary{Owner: owner})
+			}
+			ownerSummary := &ownersSummary[index]
+			ownerSummary.TotalIssues++
+			switch strings.ToLower(result.Severity) {
+			case highLabel:
+				ownerSummary.HighIssues++
+			case mediumLabel:
+				ownerSummary.MediumIssues++
+			case lowLabel:
+				ownerSummary.LowIssues++
+			case infoLabel:
+				ownerSummary.InfoIssues++
+			}
+		}
+	}
+	sort.SliceStable(ownersSummary, func(i, j int) bool {
+		if ownersSummary[i].TotalIssues != ownersSummary[j].TotalIssues {
+			return ownersSummary[i].TotalIssues > ownersSummary[j].TotalIssues
+		}
+		return ownersSummary[i].Owner < ownersSummary[j].Owner
+	})
+	return ownersSummary
+}
+
+func formatOwners(owners []string) string {
+	if len(owners) == 0 {
+		return unownedLabel
+	}
+	return strings.Join(owners, " ")
+}
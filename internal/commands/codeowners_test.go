@@ -0,0 +1,138 @@
+//go:build !integration
+
+package commands
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"gotest.tools/assert"
+)
+
+func TestCodeOwners(t *testing.T) {
+	owners, err := parseCodeOwners(`
+# Default owners
+*                   @org/everyone
+*.go                @org/gophers # Go files
+/docs/              @org/writers
+src/**/payments     @org/payments @alice
+**/build            @org/build
+/vendor
+`)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, owners.ownersOf("README.md"), []string{"@org/everyone"})
+	assert.DeepEqual(t, owners.ownersOf("/cmd/main.go"), []string{"@org/gophers"})
+	assert.DeepEqual(t, owners.ownersOf("docs/guide/intro.md"), []string{"@org/writers"})
+	assert.DeepEqual(t, owners.ownersOf("other/docs/intro.md"), []string{"@org/everyone"})
+	assert.DeepEqual(t, owners.ownersOf("src/payments/Card.java"), []string{"@org/payments", "@alice"})
+	assert.DeepEqual(t, owners.ownersOf("src/a/b/payments/Card.java"), []string{"@org/payments", "@alice"})
+	assert.DeepEqual(t, owners.ownersOf(".\\tools\\build\\Makefile"), []string{"@org/build"})
+	assert.Equal(t, len(owners.ownersOf("vendor/lib/lib.go")), 0)
+	assert.Equal(t, formatOwners(owners.ownersOf("vendor/lib/lib.go")), unownedLabel)
+	assert.Assert(t, (*codeOwners)(nil).ownersOf("README.md") == nil)
+}
+
+func TestCodeOwnersWildcardMatchesDirectoryEntriesOnly(t *testing.T) {
+	owners, err := parseCodeOwners(`
+docs/*      @org/writers
+apps/       @org/apps
+**/logs     @org/ops
+`)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, owners.ownersOf("docs/intro.md"), []string{"@org/writers"})
+	assert.Equal(t, len(owners.ownersOf("docs/guide/intro.md")), 0)
+	assert.DeepEqual(t, owners.ownersOf("src/apps/web/index.js"), []string{"@org/apps"})
+	assert.DeepEqual(t, owners.ownersOf("var/logs/2024/app.log"), []string{"@org/ops"})
+}
+
+func TestCodeOwnersGitLabSections(t *testing.T) {
+	owners, err := parseCodeOwners(`
+*.md @docs
+
+[Backend][2] @org/backend
+/api/
+/api/admin/ @org/admins
+my\ docs/ @writers
+
+^[Security] @org/security
+*.java
+`)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, owners.ownersOf("api/Users.java"), []string{"@org/backend", "@org/security"})
+	assert.DeepEqual(t, owners.ownersOf("api/admin/README.md"), []string{"@docs", "@org/admins"})
+	assert.DeepEqual(t, owners.ownersOf("my docs/guide.txt"), []string{"@writers"})
+	assert.Equal(t, len(owners.ownersOf("web/index.html")), 0)
+}
+
+func TestCodeOwnersBitbucket(t *testing.T) {
+	owners, err := parseCodeOwners(`
+CODEOWNERS.destination_branch_pattern main
+@@@Frontend @alice @bob
+*           @carol
+src/web/    Check(2) @@Frontend
+!src/web/generated/
+`)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, owners.ownersOf("src/web/app.js"), []string{"@alice", "@bob"})
+	assert.Equal(t, len(owners.ownersOf("src/web/generated/api.js")), 0)
+	assert.DeepEqual(t, owners.ownersOf("src/Main.java"), []string{"@carol"})
+}
+
+func TestCodeOwnersInvalid(t *testing.T) {
+	_, err := parseCodeOwners("src/ @@Missing")
+	assert.ErrorContains(t, err, "line 1: Undefined group @@Missing")
+	_, err = parseCodeOwners("\n[Section @org/team")
+	assert.ErrorContains(t, err, "Invalid section [Section @org/team at line 2")
+}
+
+func TestToOwnersSummary(t *testing.T) {
+	summary := &wrappers.ResultSummary{EnginesEnabled: []string{"sast", "kics"}}
+	results := &wrappers.ScanResultsCollection{Results: []*wrappers.ScanResult{
+		{Type: "sast", Severity: "HIGH", Owners: []string{"@a", "@b"}},
+		{Type: "sast", Severity: "LOW", Owners: []string{"@b"}},
+		{Type: "kics", Severity: "MEDIUM"},
+		{Type: "sast", Severity: "HIGH", Owners: []string{"@a"}, State: notExploitable},
+		{Type: "sca", Severity: "HIGH", Owners: []string{"@a"}},
+	}}
+
+	assert.DeepEqual(t, toOwnersSummary(summary, results), []wrappers.OwnerSummary{
+		{Owner: "@b", TotalIssues: 2, HighIssues: 1, LowIssues: 1},
+		{Owner: "@a", TotalIssues: 1, HighIssues: 1},
+		{Owner: unownedLabel, TotalIssues: 1, MediumIssues: 1},
+	})
+}
+
+func TestGetResultLocation(t *testing.T) {
+	manifest := "package.json"
+	assert.Equal(t, getResultLocation(&wrappers.ScanResult{
+		Type:           "sast",
+		ScanResultData: wrappers.ScanResultData{Nodes: []*wrappers.ScanResultNode{{FileName: "/src/Main.java"}}},
+	}), "/src/Main.java")
+	assert.Equal(t, getResultLocation(&wrappers.ScanResult{
+		Type:           "kics",
+		ScanResultData: wrappers.ScanResultData{Filename: "/deploy/Dockerfile"},
+	}), "/deploy/Dockerfile")
+	assert.Equal(t, getResultLocation(&wrappers.ScanResult{
+		Type: "sca",
+		ScanResultData: wrappers.ScanResultData{
+			ScaPackageCollection: &wrappers.ScaPackageCollection{Locations: []*string{&manifest}},
+		},
+	}), manifest)
+	assert.Equal(t, getResultLocation(&wrappers.ScanResult{Type: "sast"}), "")
+}
+
+func TestSummaryHTMLEscapesOwners(t *testing.T) {
+	summary := &wrappers.ResultSummary{Owners: []wrappers.OwnerSummary{{Owner: "@org/<team>", TotalIssues: 1}}}
+	tmpl, err := template.New("summaryTemplate").Parse(wrappers.SummaryTemplate(false))
+	assert.NilError(t, err)
+	content := strings.Builder{}
+	assert.NilError(t, tmpl.ExecuteTemplate(&content, "SummaryTemplate", summary))
+	assert.Assert(t, strings.Contains(content.String(), "<td>@org/&lt;team&gt;</td>"))
+	assert.Assert(t, !strings.Contains(content.String(), "<team>"))
+}
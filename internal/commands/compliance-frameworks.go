@@ -0,0 +1,110 @@
+package commands
+
+const (
+	owaspTop10Framework = "owasp-top-10-2021"
+	pciDssFramework     = "pci-dss"
+	cweTop25Framework   = "cwe-top-25"
+	uncategorizedID     = "Uncategorized"
+)
+
+type complianceCategory struct {
+	ID   string
+	Name string
+	cwes []int
+}
+
+// complianceFramework A framework whose results are the ones whose compliances name it, in the category
+// they name or else in the category of their CWE, uncategorized otherwise. The results without compliances,
+// such as those of the engines not reporting them, are categorized by their CWE only.
+type complianceFramework struct {
+	name       string
+	compliance string
+	categories []complianceCategory
+	// scaCategory Category of the SCA results, which are identified by CVE
+	scaCategory string
+}
+
+var complianceFrameworks = map[string]*complianceFramework{
+	owaspTop10Framework: {
+		name:       "OWASP Top 10 2021",
+		compliance: "owasp top 10 2021",
+		categories: []complianceCategory{
+			{ID: "A01", Name: "Broken Access Control", cwes: []int{
+				22, 23, 35, 59, 200, 201, 219, 264, 275, 276, 284, 285, 352, 359, 377, 402, 425, 441, 497, 538,
+				540, 548, 552, 566, 601, 639, 651, 668, 706, 862, 863, 913, 922, 1275,
+			}},
+			{ID: "A02", Name: "Cryptographic Failures", cwes: []int{
+				261, 296, 310, 319, 321, 322, 323, 324, 325, 326, 327, 328, 329, 330, 331, 335, 336, 337, 338, 340,
+				347, 523, 720, 757, 759, 760, 780, 818, 916,
+			}},
+			{ID: "A03", Name: "Injection", cwes: []int{
+				20, 74, 75, 77, 78, 79, 80, 83, 87, 88, 89, 90, 91, 93, 94, 95, 96, 97, 98, 99,
+				113, 116, 138, 184, 470, 471, 564, 610, 643, 644, 652, 917,
+			}},
+			{ID: "A04", Name: "Insecure Design", cwes: []int{
+				73, 183, 209, 213, 235, 256, 257, 266, 269, 280, 311, 312, 313, 316, 419, 430, 434, 444, 451, 472,
+				501, 522, 525, 539, 579, 598, 602, 642, 646, 650, 653, 656, 657, 799, 807, 840, 841, 927, 1021, 1173,
+			}},
+			{ID: "A05", Name: "Security Misconfiguration", cwes: []int{
+				2, 11, 13, 15, 16, 260, 315, 520, 526, 537, 541, 547, 611, 614, 756, 776, 942, 1004, 1032, 1174,
+			}},
+			{ID: "A06", Name: "Vulnerable and Outdated Components", cwes: []int{937, 1035, 1104}},
+			{ID: "A07", Name: "Identification and Authentication Failures", cwes: []int{
+				255, 259, 287, 288, 290, 294, 295, 297, 300, 302, 304, 306, 307, 346, 384, 521, 613, 620, 640, 798,
+				940, 1216,
+			}},
+			{ID: "A08", Name: "Software and Data Integrity Failures", cwes: []int{345, 353, 426, 494, 502, 565, 784, 829, 830, 915}},
+			{ID: "A09", Name: "Security Logging and Monitoring Failures", cwes: []int{117, 223, 532, 778}},
+			{ID: "A10", Name: "Server-Side Request Forgery", cwes: []int{918}},
+		},
+		scaCategory: "A06",
+	},
+	pciDssFramework: {
+		name:       "PCI DSS v3.2.1",
+		compliance: "pci dss",
+		categories: []complianceCategory{
+			{ID: "6.5.1", Name: "Injection flaws", cwes: []int{74, 77, 78, 88, 89, 90, 91, 94, 95, 643, 917}},
+			{ID: "6.5.2", Name: "Buffer overflows", cwes: []int{119, 120, 121, 122, 125, 131, 787}},
+			{ID: "6.5.3", Name: "Insecure cryptographic storage", cwes: []int{311, 312, 321, 326, 327, 328, 916}},
+			{ID: "6.5.4", Name: "Insecure communications", cwes: []int{5, 295, 297, 319}},
+			{ID: "6.5.5", Name: "Improper error handling", cwes: []int{209, 210, 391, 396, 397, 754, 755}},
+			{ID: "6.5.6", Name: "High-risk vulnerabilities"},
+			{ID: "6.5.7", Name: "Cross-site scripting", cwes: []int{79, 80, 83, 87}},
+			{ID: "6.5.8", Name: "Improper access control", cwes: []int{22, 23, 284, 285, 425, 601, 639, 862, 863}},
+			{ID: "6.5.9", Name: "Cross-site request forgery", cwes: []int{352}},
+			{ID: "6.5.10", Name: "Broken authentication and session management", cwes: []int{259, 287, 306, 384, 521, 613, 614, 798}},
+		},
+		scaCategory: "6.5.6",
+	},
+	cweTop25Framework: {
+		name:       "CWE Top 25 2023",
+		compliance: "cwe top 25",
+		categories: []complianceCategory{
+			{ID: "CWE-787", Name: "Out-of-bounds Write", cwes: []int{787}},
+			{ID: "CWE-79", Name: "Cross-site Scripting", cwes: []int{79}},
+			{ID: "CWE-89", Name: "SQL Injection", cwes: []int{89}},
+			{ID: "CWE-416", Name: "Use After Free", cwes: []int{416}},
+			{ID: "CWE-78", Name: "OS Command Injection", cwes: []int{78}},
+			{ID: "CWE-20", Name: "Improper Input Validation", cwes: []int{20}},
+			{ID: "CWE-125", Name: "Out-of-bounds Read", cwes: []int{125}},
+			{ID: "CWE-22", Name: "Path Traversal", cwes: []int{22}},
+			{ID: "CWE-352", Name: "Cross-Site Request Forgery", cwes: []int{352}},
+			{ID: "CWE-434", Name: "Unrestricted Upload of File with Dangerous Type", cwes: []int{434}},
+			{ID: "CWE-862", Name: "Missing Authorization", cwes: []int{862}},
+			{ID: "CWE-476", Name: "NULL Pointer Dereference", cwes: []int{476}},
+			{ID: "CWE-287", Name: "Improper Authentication", cwes: []int{287}},
+			{ID: "CWE-190", Name: "Integer Overflow or Wraparound", cwes: []int{190}},
+			{ID: "CWE-502", Name: "Deserialization of Untrusted Data", cwes: []int{502}},
+			{ID: "CWE-77", Name: "Command Injection", cwes: []int{77}},
+			{ID: "CWE-119", Name: "Improper Restriction of Operations within the Bounds of a Memory Buffer", cwes: []int{119}},
+			{ID: "CWE-798", Name: "Use of Hard-coded Credentials", cwes: []int{798}},
+			{ID: "CWE-918", Name: "Server-Side Request Forgery", cwes: []int{918}},
+			{ID: "CWE-306", Name: "Missing Authentication for Critical Function", cwes: []int{306}},
+			{ID: "CWE-362", Name: "Race Condition", cwes: []int{362}},
+			{ID: "CWE-269", Name: "Improper Privilege Management", cwes: []int{269}},
+			{ID: "CWE-94", Name: "Code Injection", cwes: []int{94}},
+			{ID: "CWE-863", Name: "Incorrect Authorization", cwes: []int{863}},
+			{ID: "CWE-276", Name: "Incorrect Default Permissions", cwes: []int{276}},
+		},
+	},
+}
@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// pageConcurrency Number of pages fetched at the same time when the total count is known
+	pageConcurrency = 4
+	// unknownTotal Returned by a pageFetcher when the API doesn't report the total count
+	unknownTotal = -1
+)
+
+// pageFetcher Fetch a page with the given limit and offset params, returning its items and the filtered total count
+type pageFetcher func(params map[string]string) (items []interface{}, total int, err error)
+
+type fetchedPage struct {
+	items []interface{}
+	err   error
+}
+
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(commonParams.AllFlag, false, commonParams.AllFlagUsage)
+	cmd.PersistentFlags().Int(commonParams.PageSizeFlag, commonParams.PageSizeDefault, commonParams.PageSizeFlagUsage)
+	cmd.PersistentFlags().Int(commonParams.MaxItemsFlag, 0, commonParams.MaxItemsFlagUsage)
+}
+
+func isAllPages(cmd *cobra.Command) bool {
+	all, _ := cmd.Flags().GetBool(commonParams.AllFlag)
+	return all
+}
+
+// streamAllPages Fetch every page, starting at the offset filter, and print each item as a JSON line
+func streamAllPages(cmd *cobra.Command, params map[string]string, itemName string, fetch pageFetcher) error {
+	pageSize, _ := cmd.Flags().GetInt(commonParams.PageSizeFlag)
+	if pageSize <= 0 {
+		return errors.Errorf("--%s must be greater than zero", commonParams.PageSizeFlag)
+	}
+	maxItems, _ := cmd.Flags().GetInt(commonParams.MaxItemsFlag)
+	if maxItems < 0 {
+		return errors.Errorf("--%s can't be negative", commonParams.MaxItemsFlag)
+	}
+
+	stream := &pageStream{
+		cmd:      cmd,
+		encoder:  json.NewEncoder(cmd.OutOrStdout()),
+		itemName: itemName,
+		maxItems: maxItems,
+	}
+	defer stream.finish()
+	return readAllPages(params, pageSize, maxItems, fetch, stream.emit)
+}
+
+// readAllPages Fetch every page, starting at the offset filter, passing the items of each page to emit in order.
+// Pages are fetched concurrently when the total count is known.
+func readAllPages(
+	params map[string]string,
+	pageSize, maxItems int,
+	fetch pageFetcher,
+	emit func(items []interface{}, total int) (done bool, err error),
+) error {
+	offset := 0
+	if offsetFilter, ok := params[commonParams.OffsetQueryParam]; ok {
+		var err error
+		offset, err = strconv.Atoi(offsetFilter)
+		if err != nil || offset < 0 {
+			return errors.Errorf("Invalid %s filter %s", commonParams.OffsetQueryParam, offsetFilter)
+		}
+	}
+
+	items, total, err := fetch(pageParams(params, offset, pageSize))
+	if err != nil {
+		return err
+	}
+	done, err := emit(items, total)
+	if err != nil || done {
+		return err
+	}
+
+	if total == unknownTotal {
+		// Without a total count, keep reading until a page comes back short
+		for len(items) == pageSize && !done {
+			offset += pageSize
+			items, _, err = fetch(pageParams(params, offset, pageSize))
+			if err != nil {
+				return err
+			}
+			if done, err = emit(items, total); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var offsets []int
+	for next := offset + pageSize; next < total; next += pageSize {
+		if maxItems > 0 && next >= offset+maxItems {
+			break
+		}
+		offsets = append(offsets, next)
+	}
+	for start := 0; start < len(offsets) && !done; start += pageConcurrency {
+		end := start + pageConcurrency
+		if end > len(offsets) {
+			end = len(offsets)
+		}
+		pages := fetchPagesConcurrently(params, offsets[start:end], pageSize, fetch)
+		for _, page := range pages {
+			if page.err != nil {
+				return page.err
+			}
+			if done, err = emit(page.items, total); err != nil || done {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func fetchPagesConcurrently(params map[string]string, offsets []int, pageSize int, fetch pageFetcher) []fetchedPage {
+	pages := make([]fetchedPage, len(offsets))
+	var wg sync.WaitGroup
+	for i, offset := range offsets {
+		wg.Add(1)
+		go func(i int, pageParams map[string]string) {
+			defer wg.Done()
+			pages[i].items, _, pages[i].err = fetch(pageParams)
+		}(i, pageParams(params, offset, pageSize))
+	}
+	wg.Wait()
+	return pages
+}
+
+// pageParams Copy the filters, since wrappers may change them, and set the page limits
+func pageParams(params map[string]string, offset, pageSize int) map[string]string {
+	copied := make(map[string]string, len(params)+2)
+	for key, value := range params {
+		copied[key] = value
+	}
+	copied[commonParams.OffsetQueryParam] = strconv.Itoa(offset)
+	copied[commonParams.LimitQueryParam] = strconv.Itoa(pageSize)
+	return copied
+}
+
+type pageStream struct {
+	cmd      *cobra.Command
+	encoder  *json.Encoder
+	itemName string
+	maxItems int
+	emitted  int
+}
+
+func (s *pageStream) emit(items []interface{}, total int) (bool, error) {
+	for _, item := range items {
+		if s.done() {
+			break
+		}
+		if err := s.encoder.Encode(item); err != nil {
+			return true, err
+		}
+		s.emitted++
+	}
+	if total == unknownTotal {
+		_, _ = fmt.Fprintf(s.cmd.ErrOrStderr(), "\rFetched %d %s", s.emitted, s.itemName)
+	} else {
+		_, _ = fmt.Fprintf(s.cmd.ErrOrStderr(), "\rFetched %d of %d %s", s.emitted, total, s.itemName)
+	}
+	return s.done(), nil
+}
+
+func (s *pageStream) done() bool {
+	return s.maxItems > 0 && s.emitted >= s.maxItems
+}
+
+func (s *pageStream) finish() {
+	_, _ = fmt.Fprintln(s.cmd.ErrOrStderr())
+}
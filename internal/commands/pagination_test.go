@@ -0,0 +1,89 @@
+//go:build !integration
+
+package commands
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+)
+
+func newPaginationTestCommand(args ...string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	addPaginationFlags(cmd)
+	_ = cmd.ParseFlags(args)
+	buffer := bytes.NewBufferString("")
+	cmd.SetOut(buffer)
+	cmd.SetErr(bytes.NewBufferString(""))
+	return cmd, buffer
+}
+
+// fakePages Serve the numbers from 0 to total-1, reporting the total count when known is true
+func fakePages(total int, known bool, calls *int32) pageFetcher {
+	return func(params map[string]string) ([]interface{}, int, error) {
+		atomic.AddInt32(calls, 1)
+		offset, _ := strconv.Atoi(params["offset"])
+		limit, _ := strconv.Atoi(params["limit"])
+		var items []interface{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, i)
+		}
+		if !known {
+			return items, unknownTotal, nil
+		}
+		return items, total, nil
+	}
+}
+
+func TestStreamAllPagesKnownTotal(t *testing.T) {
+	var calls int32
+	cmd, buffer := newPaginationTestCommand("--page-size", "3")
+	err := streamAllPages(cmd, map[string]string{}, "items", fakePages(10, true, &calls))
+	assert.NilError(t, err)
+	assert.Equal(t, buffer.String(), "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n")
+	assert.Equal(t, calls, int32(4))
+}
+
+func TestStreamAllPagesUnknownTotal(t *testing.T) {
+	var calls int32
+	cmd, buffer := newPaginationTestCommand("--page-size", "2")
+	err := streamAllPages(cmd, map[string]string{"offset": "1"}, "items", fakePages(6, false, &calls))
+	assert.NilError(t, err)
+	assert.Equal(t, buffer.String(), "1\n2\n3\n4\n5\n")
+	assert.Equal(t, calls, int32(3))
+}
+
+func TestStreamAllPagesMaxItems(t *testing.T) {
+	var calls int32
+	cmd, buffer := newPaginationTestCommand("--page-size", "2", "--max-items", "5")
+	err := streamAllPages(cmd, map[string]string{}, "items", fakePages(100, true, &calls))
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Count(buffer.String(), "\n"), 5)
+	assert.Equal(t, calls, int32(3))
+}
+
+func TestStreamAllPagesErrors(t *testing.T) {
+	cmd, _ := newPaginationTestCommand("--page-size", "0")
+	err := streamAllPages(cmd, map[string]string{}, "items", nil)
+	assert.Error(t, err, "--page-size must be greater than zero")
+
+	cmd, _ = newPaginationTestCommand()
+	err = streamAllPages(cmd, map[string]string{"offset": "x"}, "items", nil)
+	assert.Error(t, err, "Invalid offset filter x")
+
+	cmd, _ = newPaginationTestCommand("--page-size", "1")
+	failing := func(params map[string]string) ([]interface{}, int, error) {
+		if params["offset"] == "2" {
+			return nil, 0, errors.New("page failed")
+		}
+		return []interface{}{params["offset"]}, 4, nil
+	}
+	err = streamAllPages(cmd, map[string]string{}, "items", failing)
+	assert.Error(t, err, "page failed")
+}
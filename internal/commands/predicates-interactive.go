@@ -0,0 +1,420 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util"
+	"github.com/checkmarx/ast-cli/internal/logger"
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	escapeKey           = 0x1b
+	interactiveKeysHelp = "[n]ext [p]revious | state: [1] TO_VERIFY [2] NOT_EXPLOITABLE [3] PROPOSED_NOT_EXPLOITABLE " +
+		"[4] CONFIRMED [5] URGENT | severity: [h]igh [m]edium [l]ow [i]nfo | [c]omment [u]ndo [q]uit and save e[x]it"
+)
+
+var triageStateKeys = map[byte]string{
+	'1': toVerifyState,
+	'2': notExploitable,
+	'3': "PROPOSED_NOT_EXPLOITABLE",
+	'4': "CONFIRMED",
+	'5': "URGENT",
+}
+
+var triageSeverityKeys = map[byte]string{
+	'h': highCx,
+	'm': mediumCx,
+	'l': lowCx,
+	'i': infoCx,
+}
+
+func triageInteractiveSubCommand(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) *cobra.Command {
+	triageInteractiveCmd := &cobra.Command{
+		Use:   "interactive",
+		Short: "Triage the results of a scan from the terminal",
+		Long: "The interactive command steps through the SAST and IaC Security results of a scan. " +
+			"Single keystrokes set the state and severity or add a comment, the changes are saved when quitting.",
+		Example: heredoc.Doc(
+			`
+			$ cx triage interactive --scan-id <ScanID> --source-root <Directory(Optional)> --query <QueryName|QueryID(Optional)>
+		`,
+		),
+		RunE: runTriageInteractive(resultsPredicatesWrapper, resultsWrapper, scansWrapper),
+	}
+
+	addScanIDFlag(triageInteractiveCmd, "Scan ID to triage.")
+	addProjectIDFlag(triageInteractiveCmd, "Project ID. Defaults to the project of the scan.")
+	triageInteractiveCmd.PersistentFlags().String(params.SourceRootFlag, "", "Local checkout of the scanned sources, used to show code snippets")
+	triageInteractiveCmd.PersistentFlags().String(params.QueryFlag, "", "Only triage the results of this query name or query ID")
+
+	markFlagAsRequired(triageInteractiveCmd, params.ScanIDFlag)
+
+	return triageInteractiveCmd
+}
+
+func runTriageInteractive(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		scanID, _ := cmd.Flags().GetString(params.ScanIDFlag)
+		projectID, _ := cmd.Flags().GetString(params.ProjectIDFlag)
+		sourceRoot, _ := cmd.Flags().GetString(params.SourceRootFlag)
+		query, _ := cmd.Flags().GetString(params.QueryFlag)
+
+		scan, errorModel, err := scansWrapper.GetByID(scanID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGetting)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedGettingScan, errorModel.Code, errorModel.Message)
+		}
+		if projectID == "" {
+			projectID = scan.ProjectID
+		}
+		results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+		if err != nil {
+			return err
+		}
+		session := newTriageSession(getTriageableResults(results, query))
+		if len(session.results) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No results to triage in scan %s.\n", scanID)
+			return nil
+		}
+
+		terminal := newTriageTerminal(cmd.InOrStdin())
+		defer terminal.restore()
+		findings := make(map[string]string)
+		save, err := session.run(cmd.OutOrStdout(), terminal, func(result *wrappers.ScanResult) string {
+			if _, found := findings[result.SimilarityID]; !found {
+				findings[result.SimilarityID] = formatTriageFinding(result, sourceRoot, resultsPredicatesWrapper, projectID)
+			}
+			return findings[result.SimilarityID]
+		})
+		if err != nil {
+			return err
+		}
+		terminal.restore()
+		if !save {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Exited without saving the changes.")
+			return nil
+		}
+		return session.save(cmd.OutOrStdout(), resultsPredicatesWrapper, projectID)
+	}
+}
+
+// getTriageableResults Return the SAST and IaC Security results, one per similarity ID
+func getTriageableResults(results *wrappers.ScanResultsCollection, query string) []*wrappers.ScanResult {
+	if query != "" {
+		return filterResultsByQuery(results, query, nil)
+	}
+	triageableResults := make([]*wrappers.ScanResult, 0)
+	if results == nil {
+		return triageableResults
+	}
+	similarityIDs := make(map[string]bool)
+	for _, result := range results.Results {
+		if result.Type != params.SastType && result.Type != params.KicsType {
+			continue
+		}
+		if result.SimilarityID == "" || similarityIDs[result.SimilarityID] {
+			continue
+		}
+		similarityIDs[result.SimilarityID] = true
+		triageableResults = append(triageableResults, result)
+	}
+	return triageableResults
+}
+
+type triageChange struct {
+	State    string
+	Severity string
+	Comment  string
+}
+
+type triageUndo struct {
+	index    int
+	previous *triageChange
+}
+
+type triageSession struct {
+	results []*wrappers.ScanResult
+	changes map[int]*triageChange
+	history []triageUndo
+}
+
+func newTriageSession(results []*wrappers.ScanResult) *triageSession {
+	return &triageSession{
+		results: results,
+		changes: make(map[int]*triageChange),
+	}
+}
+
+// update Change the pending triage of a result, keeping the previous one to undo it
+func (s *triageSession) update(index int, apply func(change *triageChange)) {
+	change, found := s.changes[index]
+	var previous *triageChange
+	if found {
+		changeCopy := *change
+		previous = &changeCopy
+	} else {
+		change = &triageChange{
+			State:    s.results[index].State,
+			Severity: s.results[index].Severity,
+		}
+		s.changes[index] = change
+	}
+	s.history = append(s.history, triageUndo{index: index, previous: previous})
+	apply(change)
+}
+
+// undo Revert the last change and return the index of the result it belongs to
+func (s *triageSession) undo() (int, bool) {
+	if len(s.history) == 0 {
+		return 0, false
+	}
+	last := s.history[len(s.history)-1]
+	s.history = s.history[:len(s.history)-1]
+	if last.previous == nil {
+		delete(s.changes, last.index)
+	} else {
+		s.changes[last.index] = last.previous
+	}
+	return last.index, true
+}
+
+// run Step through the results until the user quits, returns true when the changes should be saved
+func (s *triageSession) run(w io.Writer, terminal *triageTerminal, describe func(*wrappers.ScanResult) string) (bool, error) {
+	index := 0
+	for {
+		_, _ = fmt.Fprintf(w, "\n[%d/%d] %s", index+1, len(s.results), describe(s.results[index]))
+		if change, found := s.changes[index]; found {
+			_, _ = fmt.Fprintf(w, "Pending change: state %s, severity %s", change.State, change.Severity)
+			if change.Comment != "" {
+				_, _ = fmt.Fprintf(w, ", comment %q", change.Comment)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, interactiveKeysHelp)
+
+		key, err := terminal.readKey()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case key == 'n':
+			if index+1 < len(s.results) {
+				index++
+			}
+		case key == 'p':
+			if index > 0 {
+				index--
+			}
+		case triageStateKeys[key] != "":
+			s.update(index, func(change *triageChange) { change.State = triageStateKeys[key] })
+		case triageSeverityKeys[key] != "":
+			s.update(index, func(change *triageChange) { change.Severity = triageSeverityKeys[key] })
+		case key == 'c':
+			_, _ = fmt.Fprint(w, "Comment: ")
+			comment, err := terminal.readLine()
+			if err != nil && err != io.EOF {
+				return false, err
+			}
+			if comment != "" {
+				s.update(index, func(change *triageChange) { change.Comment = comment })
+			}
+		case key == 'u':
+			if undoneIndex, undone := s.undo(); undone {
+				index = undoneIndex
+			}
+		case key == 'q':
+			return true, nil
+		case key == 'x':
+			return false, nil
+		}
+	}
+}
+
+// save Send the pending changes, in the order of the results
+func (s *triageSession) save(w io.Writer, resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper, projectID string) error {
+	if len(s.changes) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes to save.")
+		return nil
+	}
+	var failedUpdates []string
+	for index, result := range s.results {
+		change, found := s.changes[index]
+		if !found {
+			continue
+		}
+		predicate := &wrappers.PredicateRequest{
+			SimilarityID: result.SimilarityID,
+			ProjectID:    projectID,
+			Severity:     change.Severity,
+			State:        change.State,
+			Comment:      change.Comment,
+			ScannerType:  result.Type,
+		}
+		_, err := resultsPredicatesWrapper.PredicateSeverityAndState(predicate)
+		if err != nil {
+			failedUpdates = append(failedUpdates, fmt.Sprintf("%s (%s)", result.SimilarityID, err.Error()))
+		}
+	}
+	if len(failedUpdates) > 0 {
+		return errors.Errorf(
+			"%s for %d of %d result(s): %s",
+			failedUpdatingPredicate, len(failedUpdates), len(s.changes), strings.Join(failedUpdates, ", "),
+		)
+	}
+	_, _ = fmt.Fprintf(w, "Saved the triage of %d result(s).\n", len(s.changes))
+	return nil
+}
+
+func formatTriageFinding(
+	result *wrappers.ScanResult,
+	sourceRoot string,
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	projectID string,
+) string {
+	var builder strings.Builder
+	builder.WriteString(
+		fmt.Sprintf(
+			"%s %s (%s) - state %s\n",
+			result.Severity, strings.ReplaceAll(result.ScanResultData.QueryName, "_", " "), result.Type, result.State,
+		),
+	)
+	description := result.Description
+	if description == "" {
+		description = result.ScanResultData.Description
+	}
+	if description != "" {
+		builder.WriteString(fmt.Sprintf("%s\n", description))
+	}
+
+	if len(result.ScanResultData.Nodes) > 0 {
+		builder.WriteString("Attack vector:\n")
+		for i, node := range result.ScanResultData.Nodes {
+			builder.WriteString(fmt.Sprintf("  %d. %s %s:%d:%d %s\n", i+1, node.Method, node.FileName, node.Line, node.Column, node.Name))
+			for _, line := range readSourceLines(sourceRoot, node.FileName, node.Line, 0) {
+				builder.WriteString(fmt.Sprintf("     %d | %s\n", line.Number, strings.TrimSpace(line.Text)))
+			}
+		}
+	} else if result.Type == params.KicsType {
+		builder.WriteString(fmt.Sprintf("File: %s:%d\n", result.ScanResultData.Filename, result.ScanResultData.Line))
+		for _, line := range readSourceLines(sourceRoot, result.ScanResultData.Filename, result.ScanResultData.Line, 0) {
+			builder.WriteString(fmt.Sprintf("     %d | %s\n", line.Number, strings.TrimSpace(line.Text)))
+		}
+		builder.WriteString(fmt.Sprintf("Expected: %s\nActual: %s\n", result.ScanResultData.ExpectedValue, result.ScanResultData.Value))
+	}
+
+	predicatesCollection, errorModel, err := resultsPredicatesWrapper.GetAllPredicatesForSimilarityID(
+		result.SimilarityID,
+		projectID,
+		result.Type,
+	)
+	if err != nil || errorModel != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Unable to get the predicates of %s", result.SimilarityID))
+		return builder.String()
+	}
+	predicates := toPredicatesView(*predicatesCollection)
+	if len(predicates) > 0 {
+		builder.WriteString("History:\n")
+		for i := range predicates {
+			builder.WriteString(
+				fmt.Sprintf(
+					"  %s %s %s %s %s\n",
+					predicates[i].CreatedAt.Format("2006-01-02 15:04"), predicates[i].CreatedBy,
+					predicates[i].State, predicates[i].Severity, predicates[i].Comment,
+				),
+			)
+		}
+	}
+	return builder.String()
+}
+
+// triageTerminal Read single keystrokes from a terminal, or characters followed by enter from any other input
+type triageTerminal struct {
+	reader       *bufio.Reader
+	file         *os.File
+	restoreRawFn func()
+}
+
+func newTriageTerminal(input io.Reader) *triageTerminal {
+	terminal := &triageTerminal{reader: bufio.NewReader(input)}
+	if file, isFile := input.(*os.File); isFile {
+		terminal.file = file
+		terminal.makeRaw()
+	}
+	return terminal
+}
+
+func (t *triageTerminal) makeRaw() {
+	restore, err := util.MakeRawTerminal(t.file)
+	if err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Reading keys in line mode: %v", err))
+		return
+	}
+	t.restoreRawFn = restore
+}
+
+func (t *triageTerminal) restore() {
+	if t.restoreRawFn != nil {
+		t.restoreRawFn()
+		t.restoreRawFn = nil
+	}
+}
+
+func (t *triageTerminal) readKey() (byte, error) {
+	for {
+		key, err := t.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch key {
+		case '\n', '\r', ' ':
+			continue
+		case escapeKey:
+			// Arrow keys are sent as ESC [ C and ESC [ D
+			sequence := make([]byte, 2)
+			if _, err = io.ReadFull(t.reader, sequence); err != nil {
+				return 0, err
+			}
+			if sequence[1] == 'C' {
+				return 'n', nil
+			}
+			if sequence[1] == 'D' {
+				return 'p', nil
+			}
+			continue
+		}
+		return key, nil
+	}
+}
+
+// readLine Read a whole line with echo, even when reading single keystrokes
+func (t *triageTerminal) readLine() (string, error) {
+	raw := t.restoreRawFn != nil
+	t.restore()
+	line, err := t.reader.ReadString('\n')
+	if raw {
+		t.makeRaw()
+	}
+	return strings.TrimSpace(line), err
+}
@@ -1,10 +1,13 @@
 package commands
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util"
 	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
 	"github.com/checkmarx/ast-cli/internal/params"
 	"github.com/checkmarx/ast-cli/internal/wrappers"
@@ -12,21 +15,39 @@ import (
 	"github.com/spf13/cobra"
 )
 
-func NewResultsPredicatesCommand(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper) *cobra.Command {
+const (
+	failedUpdatingPredicate = "Failed updating the predicate"
+	failedTriageReport      = "Failed creating the triage report"
+	missingTriageSelection  = "Please provide --similarity-id, --project-id and --scan-type, or --scan-id and --query"
+	toVerifyState           = "TO_VERIFY"
+	hoursPerDay             = 24
+)
+
+func NewResultsPredicatesCommand(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) *cobra.Command {
 	triageCmd := &cobra.Command{
 		Use:   "triage",
 		Short: "Manage results",
 		Long:  "The 'triage' command enables the ability to manage results in Checkmarx One.",
 	}
 	triageShowCmd := triageShowSubCommand(resultsPredicatesWrapper)
-	triageUpdateCmd := triageUpdateSubCommand(resultsPredicatesWrapper)
+	triageUpdateCmd := triageUpdateSubCommand(resultsPredicatesWrapper, resultsWrapper, scansWrapper)
+	triageReportCmd := triageReportSubCommand(resultsPredicatesWrapper, resultsWrapper, scansWrapper)
+	triageInteractiveCmd := triageInteractiveSubCommand(resultsPredicatesWrapper, resultsWrapper, scansWrapper)
 
 	addFormatFlagToMultipleCommands(
 		[]*cobra.Command{triageShowCmd},
 		printer.FormatList, printer.FormatTable, printer.FormatJSON,
 	)
+	addFormatFlag(
+		triageReportCmd,
+		printer.FormatTable, printer.FormatJSON, printer.FormatCSV, printer.FormatSummaryMarkdown,
+	)
 
-	triageCmd.AddCommand(triageShowCmd, triageUpdateCmd)
+	triageCmd.AddCommand(triageShowCmd, triageUpdateCmd, triageReportCmd, triageInteractiveCmd)
 	return triageCmd
 }
 
@@ -55,7 +76,11 @@ func triageShowSubCommand(resultsPredicatesWrapper wrappers.ResultsPredicatesWra
 	return triageShowCmd
 }
 
-func triageUpdateSubCommand(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper) *cobra.Command {
+func triageUpdateSubCommand(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) *cobra.Command {
 	triageUpdateCmd := &cobra.Command{
 		Use:   "update",
 		Short: "Update the state, severity or comment for the given issue",
@@ -69,9 +94,17 @@ func triageUpdateSubCommand(resultsPredicatesWrapper wrappers.ResultsPredicatesW
 				--severity <HIGH|MEDIUM|LOW|INFO> 
 				--comment <Comment(Optional)> 
 				--scan-type <SAST|IAC-SECURITY>
+
+				$ cx triage update
+				--scan-id <ScanID>
+				--query <QueryName|QueryID>
+				--path-glob <Glob(Optional)>
+				--state <TO_VERIFY|NOT_EXPLOITABLE|PROPOSED_NOT_EXPLOITABLE|CONFIRMED|URGENT>
+				--severity <HIGH|MEDIUM|LOW|INFO>
+				--comment <Comment(Optional)>
 		`,
 		),
-		RunE: runTriageUpdate(resultsPredicatesWrapper),
+		RunE: runTriageUpdate(resultsPredicatesWrapper, resultsWrapper, scansWrapper),
 	}
 
 	triageUpdateCmd.PersistentFlags().String(params.SimilarityIDFlag, "", "Similarity ID")
@@ -80,16 +113,50 @@ func triageUpdateSubCommand(resultsPredicatesWrapper wrappers.ResultsPredicatesW
 	triageUpdateCmd.PersistentFlags().String(params.StateFlag, "", "State")
 	triageUpdateCmd.PersistentFlags().String(params.CommentFlag, "", "Optional comment.")
 	triageUpdateCmd.PersistentFlags().String(params.ScanTypeFlag, "", "Scan Type")
+	addScanIDFlag(triageUpdateCmd, "Scan ID. Update every result of --query in the scan.")
+	triageUpdateCmd.PersistentFlags().String(params.QueryFlag, "", "Query name or query ID to update, use with --scan-id")
+	triageUpdateCmd.PersistentFlags().StringSlice(
+		params.PathGlobFlag,
+		[]string{},
+		"Only update results whose file matches one of the globs, use with --scan-id. Example: **/test/**",
+	)
+	addYesFlag(triageUpdateCmd)
 
-	markFlagAsRequired(triageUpdateCmd, params.SimilarityIDFlag)
 	markFlagAsRequired(triageUpdateCmd, params.SeverityFlag)
-	markFlagAsRequired(triageUpdateCmd, params.ProjectIDFlag)
 	markFlagAsRequired(triageUpdateCmd, params.StateFlag)
-	markFlagAsRequired(triageUpdateCmd, params.ScanTypeFlag)
 
 	return triageUpdateCmd
 }
 
+func triageReportSubCommand(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) *cobra.Command {
+	triageReportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Audit the triage of the results of a project",
+		Long: "The report command lists every state change of the project results that are no longer in TO_VERIFY, " +
+			"with the user, date, comment and the days spent in each state.",
+		Example: heredoc.Doc(
+			`
+			$ cx triage report --project-id <ProjectID> --user <Username> --from-date <YYYY-MM-DD> --to-date <YYYY-MM-DD> --format csv
+		`,
+		),
+		RunE: runTriageReport(resultsPredicatesWrapper, resultsWrapper, scansWrapper),
+	}
+
+	addProjectIDFlag(triageReportCmd, "Project ID.")
+	addScanIDFlag(triageReportCmd, "Scan to read the results from. Defaults to the latest completed scan of the project.")
+	triageReportCmd.PersistentFlags().String(params.UserFlag, "", "Only include changes made by this user")
+	triageReportCmd.PersistentFlags().String(params.FromDateFlag, "", "Only include changes made on or after this date (YYYY-MM-DD)")
+	triageReportCmd.PersistentFlags().String(params.ToDateFlag, "", "Only include changes made on or before this date (YYYY-MM-DD)")
+
+	markFlagAsRequired(triageReportCmd, params.ProjectIDFlag)
+
+	return triageReportCmd
+}
+
 func runTriageShow(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		var predicatesCollection *wrappers.PredicatesCollectionResponseModel
@@ -134,7 +201,11 @@ func runTriageShow(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper) f
 	}
 }
 
-func runTriageUpdate(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper) func(*cobra.Command, []string) error {
+func runTriageUpdate(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		similarityID, _ := cmd.Flags().GetString(params.SimilarityIDFlag)
 		projectID, _ := cmd.Flags().GetString(params.ProjectIDFlag)
@@ -142,6 +213,8 @@ func runTriageUpdate(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper)
 		state, _ := cmd.Flags().GetString(params.StateFlag)
 		comment, _ := cmd.Flags().GetString(params.CommentFlag)
 		scanType, _ := cmd.Flags().GetString(params.ScanTypeFlag)
+		scanID, _ := cmd.Flags().GetString(params.ScanIDFlag)
+		query, _ := cmd.Flags().GetString(params.QueryFlag)
 
 		predicate := &wrappers.PredicateRequest{
 			SimilarityID: similarityID,
@@ -152,15 +225,307 @@ func runTriageUpdate(resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper)
 			ScannerType:  scanType,
 		}
 
+		if scanID != "" && query != "" {
+			return runTriageUpdateByQuery(cmd, resultsPredicatesWrapper, resultsWrapper, scansWrapper, scanID, query, predicate)
+		}
+		if similarityID == "" || projectID == "" || scanType == "" {
+			return errors.Errorf("%s: %s", failedUpdatingPredicate, missingTriageSelection)
+		}
+
 		_, err := resultsPredicatesWrapper.PredicateSeverityAndState(predicate)
 		if err != nil {
-			return errors.Wrapf(err, "%s", "Failed updating the predicate")
+			return errors.Wrapf(err, "%s", failedUpdatingPredicate)
 		}
 
 		return nil
 	}
 }
 
+func runTriageUpdateByQuery(
+	cmd *cobra.Command,
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+	scanID, query string,
+	predicate *wrappers.PredicateRequest,
+) error {
+	scan, errorModel, err := scansWrapper.GetByID(scanID)
+	if err != nil {
+		return errors.Wrapf(err, "%s", failedGetting)
+	}
+	if errorModel != nil {
+		return errors.Errorf(ErrorCodeFormat, failedGettingScan, errorModel.Code, errorModel.Message)
+	}
+	if predicate.ProjectID == "" {
+		predicate.ProjectID = scan.ProjectID
+	}
+
+	results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+	if err != nil {
+		return err
+	}
+	pathGlobs, _ := cmd.Flags().GetStringSlice(params.PathGlobFlag)
+	matchedResults := filterResultsByQuery(results, query, pathGlobs)
+	if len(matchedResults) == 0 {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No results of query %s found in scan %s.\n", query, scanID)
+		return nil
+	}
+
+	confirmMessage := fmt.Sprintf(
+		"%d result(s) of query %s will be updated to state %s and severity %s.",
+		len(matchedResults), query, predicate.State, predicate.Severity,
+	)
+	if !confirmAction(cmd, confirmMessage) {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Triage update canceled.")
+		return nil
+	}
+
+	var failedUpdates []string
+	for _, result := range matchedResults {
+		resultPredicate := *predicate
+		resultPredicate.SimilarityID = result.SimilarityID
+		resultPredicate.ScannerType = result.Type
+		_, err = resultsPredicatesWrapper.PredicateSeverityAndState(&resultPredicate)
+		if err != nil {
+			failedUpdates = append(failedUpdates, fmt.Sprintf("%s (%s)", result.SimilarityID, err.Error()))
+		}
+	}
+	if len(failedUpdates) > 0 {
+		return errors.Errorf(
+			"%s for %d of %d result(s): %s",
+			failedUpdatingPredicate, len(failedUpdates), len(matchedResults), strings.Join(failedUpdates, ", "),
+		)
+	}
+	return nil
+}
+
+// filterResultsByQuery Select the triageable results of a query, one per similarity ID
+func filterResultsByQuery(results *wrappers.ScanResultsCollection, query string, pathGlobs []string) []*wrappers.ScanResult {
+	matchedResults := make([]*wrappers.ScanResult, 0)
+	if results == nil {
+		return matchedResults
+	}
+	similarityIDs := make(map[string]bool)
+	for _, result := range results.Results {
+		if result.Type != params.SastType && result.Type != params.KicsType {
+			continue
+		}
+		if result.SimilarityID == "" || similarityIDs[result.SimilarityID] {
+			continue
+		}
+		if !isResultOfQuery(result, query) || !isResultInPaths(result, pathGlobs) {
+			continue
+		}
+		similarityIDs[result.SimilarityID] = true
+		matchedResults = append(matchedResults, result)
+	}
+	return matchedResults
+}
+
+func isResultOfQuery(result *wrappers.ScanResult, query string) bool {
+	query = strings.TrimSpace(query)
+	queryName := result.ScanResultData.QueryName
+	if strings.EqualFold(queryName, query) || strings.EqualFold(queryName, strings.ReplaceAll(query, " ", "_")) {
+		return true
+	}
+	// The results decode the SAST query IDs as json.Number, compared as their exact text
+	queryID := formatQueryID(result.ScanResultData.QueryID)
+	return queryID != "" && strings.EqualFold(queryID, query)
+}
+
+func isResultInPaths(result *wrappers.ScanResult, pathGlobs []string) bool {
+	if len(pathGlobs) == 0 {
+		return true
+	}
+	filePath := getResultFileName(result)
+	for _, pathGlob := range pathGlobs {
+		if util.MatchGlob(strings.TrimLeft(pathGlob, "/"), filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// getResultFileName Return the file of the first SAST node or the KICS file name, without the leading slash
+func getResultFileName(result *wrappers.ScanResult) string {
+	if len(result.ScanResultData.Nodes) > 0 {
+		return strings.TrimLeft(result.ScanResultData.Nodes[0].FileName, "/")
+	}
+	return strings.TrimLeft(result.ScanResultData.Filename, "/")
+}
+
+func runTriageReport(
+	resultsPredicatesWrapper wrappers.ResultsPredicatesWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		projectID, _ := cmd.Flags().GetString(params.ProjectIDFlag)
+		scanID, _ := cmd.Flags().GetString(params.ScanIDFlag)
+		user, _ := cmd.Flags().GetString(params.UserFlag)
+		fromDate, toDate, err := getDateRange(cmd)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedTriageReport)
+		}
+
+		scan, err := getTriageReportScan(scansWrapper, projectID, scanID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedTriageReport)
+		}
+		results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		views := make([]triageAuditView, 0)
+		for _, result := range results.Results {
+			if result.Type != params.SastType && result.Type != params.KicsType {
+				continue
+			}
+			if result.SimilarityID == "" || strings.EqualFold(result.State, toVerifyState) {
+				continue
+			}
+			predicatesCollection, errorModel, err := resultsPredicatesWrapper.GetAllPredicatesForSimilarityID(
+				result.SimilarityID,
+				projectID,
+				result.Type,
+			)
+			if err != nil {
+				return errors.Wrapf(err, "%s", failedTriageReport)
+			}
+			if errorModel != nil {
+				return errors.Errorf(ErrorCodeFormat, failedTriageReport, errorModel.Code, errorModel.Message)
+			}
+			for _, view := range toTriageAuditViews(result, predicatesCollection, now) {
+				if isTriageAuditViewSelected(&view, user, fromDate, toDate) {
+					views = append(views, view)
+				}
+			}
+		}
+
+		return printByFormat(cmd, views)
+	}
+}
+
+func getTriageReportScan(scansWrapper wrappers.ScansWrapper, projectID, scanID string) (*wrappers.ScanResponseModel, error) {
+	if scanID != "" {
+		scan, errorModel, err := scansWrapper.GetByID(scanID)
+		if err != nil {
+			return nil, err
+		}
+		if errorModel != nil {
+			return nil, errors.Errorf(ErrorCodeFormat, failedGettingScan, errorModel.Code, errorModel.Message)
+		}
+		return scan, nil
+	}
+
+	scans, errorModel, err := scansWrapper.Get(
+		map[string]string{
+			params.ProjectIDQueryParam: projectID,
+			params.StatusesQueryParam:  wrappers.ScanCompleted,
+			params.SortQueryParam:      "-created_at",
+			params.LimitQueryParam:     "1",
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if errorModel != nil {
+		return nil, errors.Errorf(ErrorCodeFormat, failedGettingAll, errorModel.Code, errorModel.Message)
+	}
+	if scans == nil || len(scans.Scans) == 0 {
+		return nil, errors.Errorf("No completed scans found for project %s", projectID)
+	}
+	return &scans.Scans[0], nil
+}
+
+// getDateRange Parse the --from-date and --to-date flags, the to date is inclusive
+func getDateRange(cmd *cobra.Command) (fromDate, toDate time.Time, err error) {
+	fromDateValue, _ := cmd.Flags().GetString(params.FromDateFlag)
+	toDateValue, _ := cmd.Flags().GetString(params.ToDateFlag)
+	if fromDateValue != "" {
+		fromDate, err = time.ParseInLocation(params.DateLayout, fromDateValue, time.Local)
+		if err != nil {
+			return fromDate, toDate, errors.Errorf("Invalid --%s, expected format YYYY-MM-DD", params.FromDateFlag)
+		}
+	}
+	if toDateValue != "" {
+		toDate, err = time.ParseInLocation(params.DateLayout, toDateValue, time.Local)
+		if err != nil {
+			return fromDate, toDate, errors.Errorf("Invalid --%s, expected format YYYY-MM-DD", params.ToDateFlag)
+		}
+		toDate = toDate.Add(hoursPerDay * time.Hour)
+	}
+	return fromDate, toDate, nil
+}
+
+type triageAuditView struct {
+	SimilarityID string `format:"name:Similarity ID"`
+	ScanType     string `format:"name:Scan Type"`
+	QueryName    string `format:"name:Query"`
+	FileName     string `format:"name:File"`
+	State        string
+	Severity     string
+	Comment      string
+	CreatedBy    string    `format:"name:Changed by"`
+	CreatedAt    time.Time `format:"name:Changed at;time:01-02-06 15:04:05"`
+	DaysInState  float64   `format:"name:Days in state"`
+}
+
+// toTriageAuditViews Build one view per state change of the result, the time in a state lasts until the next change
+func toTriageAuditViews(
+	result *wrappers.ScanResult,
+	predicatesCollection *wrappers.PredicatesCollectionResponseModel,
+	now time.Time,
+) []triageAuditView {
+	views := make([]triageAuditView, 0)
+	if predicatesCollection == nil || len(predicatesCollection.PredicateHistoryPerProject) == 0 {
+		return views
+	}
+	predicates := append([]wrappers.Predicate{}, predicatesCollection.PredicateHistoryPerProject[0].Predicates...)
+	sort.SliceStable(
+		predicates, func(i, j int) bool {
+			return predicates[i].CreatedAt.Before(predicates[j].CreatedAt)
+		},
+	)
+
+	for i := range predicates {
+		until := now
+		if i+1 < len(predicates) {
+			until = predicates[i+1].CreatedAt
+		}
+		views = append(
+			views, triageAuditView{
+				SimilarityID: result.SimilarityID,
+				ScanType:     result.Type,
+				QueryName:    result.ScanResultData.QueryName,
+				FileName:     getResultFileName(result),
+				State:        predicates[i].State,
+				Severity:     predicates[i].Severity,
+				Comment:      predicates[i].Comment,
+				CreatedBy:    predicates[i].CreatedBy,
+				CreatedAt:    predicates[i].CreatedAt,
+				DaysInState:  util.RoundFloat(until.Sub(predicates[i].CreatedAt).Hours()/hoursPerDay, 1),
+			},
+		)
+	}
+	return views
+}
+
+func isTriageAuditViewSelected(view *triageAuditView, user string, fromDate, toDate time.Time) bool {
+	if user != "" && !strings.EqualFold(view.CreatedBy, user) {
+		return false
+	}
+	if !fromDate.IsZero() && view.CreatedAt.Before(fromDate) {
+		return false
+	}
+	if !toDate.IsZero() && !view.CreatedAt.Before(toDate) {
+		return false
+	}
+	return true
+}
+
 type predicateView struct {
 	ID           string `format:"name:ID"`
 	ProjectID    string `format:"name:Project ID"`
@@ -3,9 +3,13 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/checkmarx/ast-cli/internal/wrappers"
 	"gotest.tools/assert"
 )
 
@@ -46,5 +50,156 @@ func TestRunUpdateTriageCommandWithNoInput(t *testing.T) {
 	fmt.Println(err)
 	assert.Assert(
 		t,
-		err.Error() == "required flag(s) \"project-id\", \"scan-type\", \"severity\", \"similarity-id\", \"state\" not set")
+		err.Error() == "required flag(s) \"severity\", \"state\" not set")
+}
+
+func TestRunUpdateTriageCommandWithoutSelection(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "triage", "update", "--state", "confirmed", "--severity", "low")
+	assertError(t, err, missingTriageSelection)
+}
+
+func TestRunUpdateTriageCommandByQuery(t *testing.T) {
+	execCmdNilAssertion(
+		t,
+		"triage",
+		"update",
+		"--scan-id",
+		"MOCK",
+		"--query",
+		"Mock Query",
+		"--state",
+		"not_exploitable",
+		"--severity",
+		"low",
+		"--yes")
+}
+
+func TestRunUpdateTriageCommandByQueryID(t *testing.T) {
+	execCmdNilAssertion(
+		t,
+		"triage",
+		"update",
+		"--scan-id",
+		"MOCK",
+		"--query",
+		"1234",
+		"--path-glob",
+		"**/dummy-*",
+		"--state",
+		"not_exploitable",
+		"--severity",
+		"low",
+		"--yes")
+}
+
+func TestFilterResultsByQuery(t *testing.T) {
+	// Decoded as the results API responses are, the SAST query IDs being json.Number
+	results := &wrappers.ScanResultsCollection{}
+	err := json.Unmarshal([]byte(`{"results": [
+		{"type": "sast", "similarityId": "1", "data": {"queryId": 5157925289005576664, "queryName": "Reflected_XSS",
+			"nodes": [{"fileName": "/src/test/App.java"}]}},
+		{"type": "sast", "similarityId": "2", "data": {"queryId": 5157925289005576664, "queryName": "Reflected_XSS",
+			"nodes": [{"fileName": "/src/main/App.java"}]}},
+		{"type": "infrastructure", "similarityId": "3", "data": {"queryId": "b03a748a-542d-44f4-bb86-9199ab4fd2d5",
+			"queryName": "Healthcheck Not Set", "filename": "/Dockerfile"}}
+	]}`), results)
+	assert.NilError(t, err)
+	assert.Equal(t, len(filterResultsByQuery(results, "Reflected XSS", nil)), 2)
+	assert.Equal(t, len(filterResultsByQuery(results, "5157925289005576664", nil)), 2)
+	assert.Equal(t, len(filterResultsByQuery(results, "Reflected_XSS", []string{"**/test/**"})), 1)
+	assert.Equal(t, len(filterResultsByQuery(results, "b03a748a-542d-44f4-bb86-9199ab4fd2d5", []string{"Dockerfile"})), 1)
+	assert.Equal(t, len(filterResultsByQuery(results, "Unknown", nil)), 0)
+}
+
+func TestRunTriageReportCommand(t *testing.T) {
+	execCmdNilAssertion(t, "triage", "report", "--project-id", "MOCK")
+}
+
+func TestRunTriageReportCommandWithFilters(t *testing.T) {
+	execCmdNilAssertion(
+		t,
+		"triage",
+		"report",
+		"--project-id",
+		"MOCK",
+		"--scan-id",
+		"MOCK",
+		"--user",
+		"MOCK",
+		"--from-date",
+		"2023-01-01",
+		"--format",
+		"csv")
+}
+
+func TestRunTriageReportCommandWithInvalidDate(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "triage", "report", "--project-id", "MOCK", "--to-date", "01/02/2023")
+	assertError(t, err, "Invalid --to-date")
+}
+
+func TestToTriageAuditViews(t *testing.T) {
+	firstChange := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondChange := firstChange.Add(72 * time.Hour)
+	now := secondChange.Add(12 * time.Hour)
+	result := &wrappers.ScanResult{Type: "sast", SimilarityID: "1"}
+	predicates := &wrappers.PredicatesCollectionResponseModel{
+		PredicateHistoryPerProject: []wrappers.PredicateHistory{
+			{
+				Predicates: []wrappers.Predicate{
+					{BasePredicate: wrappers.BasePredicate{State: "NOT_EXPLOITABLE"}, CreatedBy: "auditor", CreatedAt: secondChange},
+					{BasePredicate: wrappers.BasePredicate{State: "CONFIRMED"}, CreatedBy: "developer", CreatedAt: firstChange},
+				},
+			},
+		},
+	}
+
+	views := toTriageAuditViews(result, predicates, now)
+	assert.Equal(t, len(views), 2)
+	assert.Equal(t, views[0].State, "CONFIRMED")
+	assert.Equal(t, views[0].DaysInState, 3.0)
+	assert.Equal(t, views[1].State, "NOT_EXPLOITABLE")
+	assert.Equal(t, views[1].DaysInState, 0.5)
+
+	assert.Assert(t, isTriageAuditViewSelected(&views[1], "Auditor", time.Time{}, time.Time{}))
+	assert.Assert(t, !isTriageAuditViewSelected(&views[0], "auditor", time.Time{}, time.Time{}))
+	assert.Assert(t, !isTriageAuditViewSelected(&views[0], "", firstChange.Add(time.Hour), time.Time{}))
+	assert.Assert(t, !isTriageAuditViewSelected(&views[1], "", time.Time{}, secondChange))
+}
+
+func TestRunTriageInteractiveCommand(t *testing.T) {
+	cmd := createASTTestCommand()
+	cmd.SetIn(bytes.NewBufferString("4\nl\nc\nNot reachable from user input\nn\np\nq\n"))
+	err := executeTestCommand(cmd, "triage", "interactive", "--scan-id", "MOCK", "--source-root", ".")
+	assert.NilError(t, err)
+}
+
+func TestRunTriageInteractiveCommandWithoutSaving(t *testing.T) {
+	cmd := createASTTestCommand()
+	cmd.SetIn(bytes.NewBufferString("2x"))
+	err := executeTestCommand(cmd, "triage", "interactive", "--scan-id", "MOCK")
+	assert.NilError(t, err)
+}
+
+func TestTriageSessionUndo(t *testing.T) {
+	session := newTriageSession(
+		[]*wrappers.ScanResult{
+			{SimilarityID: "1", State: "TO_VERIFY", Severity: "HIGH"},
+			{SimilarityID: "2", State: "TO_VERIFY", Severity: "LOW"},
+		},
+	)
+	session.update(0, func(change *triageChange) { change.State = "CONFIRMED" })
+	session.update(1, func(change *triageChange) { change.State = "URGENT" })
+	session.update(1, func(change *triageChange) { change.Severity = "MEDIUM" })
+	assert.Equal(t, *session.changes[1], triageChange{State: "URGENT", Severity: "MEDIUM"})
+
+	index, undone := session.undo()
+	assert.Assert(t, undone)
+	assert.Equal(t, index, 1)
+	assert.Equal(t, *session.changes[1], triageChange{State: "URGENT", Severity: "LOW"})
+
+	_, _ = session.undo()
+	_, _ = session.undo()
+	assert.Equal(t, len(session.changes), 0)
+	_, undone = session.undo()
+	assert.Assert(t, !undone)
 }
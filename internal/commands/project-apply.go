@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	failedApplyingProjects = "Failed applying projects"
+	projectActionCreate    = "create"
+	projectActionUpdate    = "update"
+	projectActionNone      = "unchanged"
+)
+
+// projectsManifest The declarative description of the projects read by project apply
+type projectsManifest struct {
+	Projects []projectSpec `yaml:"projects"`
+}
+
+// projectSpec The desired state of a project. Fields left out of the manifest are not managed
+type projectSpec struct {
+	Name       string            `yaml:"name"`
+	MainBranch string            `yaml:"mainBranch"`
+	Groups     []string          `yaml:"groups"`
+	Tags       map[string]string `yaml:"tags"`
+	RepoURL    string            `yaml:"repoUrl"`
+	SSHKey     string            `yaml:"sshKey"`
+}
+
+type projectPlan struct {
+	spec          projectSpec
+	action        string
+	projectID     string
+	model         wrappers.Project
+	changes       []string
+	repoConfigure bool
+}
+
+type projectPlanView struct {
+	Name    string `format:"name:Project name"`
+	Action  string
+	Changes string
+}
+
+func newApplyProjectsCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper wrappers.GroupsWrapper) *cobra.Command {
+	applyProjCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Creates or updates projects from a file",
+		Long: heredoc.Doc(
+			`
+			The project apply command creates or updates the projects described in a YAML file.
+			Projects are matched by name. Only the fields present in the file are managed, and a plan
+			of the changes is shown before applying them. The ssh key is sent when the project is
+			created or its repository URL changes.
+		`,
+		),
+		Example: heredoc.Doc(
+			`
+			$ cx project apply -f projects.yaml
+
+			projects:
+			  - name: my-project
+			    mainBranch: main
+			    groups: [PowerUsers]
+			    tags:
+			      team: backend
+			    repoUrl: git@github.com:org/my-project.git
+			    sshKey: ~/.ssh/id_rsa
+		`,
+		),
+		Annotations: map[string]string{
+			"command:doc": heredoc.Doc(
+				`
+				https://checkmarx.com/resource/documents/en/34965-68634-project.html
+			`,
+			),
+		},
+		RunE: runApplyProjectsCommand(projectsWrapper, groupsWrapper),
+	}
+	applyProjCmd.PersistentFlags().StringP(
+		commonParams.ProjectsFileFlag,
+		commonParams.ProjectsFileFlagSh,
+		"",
+		"YAML file describing the projects",
+	)
+	_ = applyProjCmd.MarkPersistentFlagRequired(commonParams.ProjectsFileFlag)
+	addYesFlag(applyProjCmd)
+	addFormatFlag(applyProjCmd, printer.FormatTable, printer.FormatJSON, printer.FormatList)
+	return applyProjCmd
+}
+
+func runApplyProjectsCommand(
+	projectsWrapper wrappers.ProjectsWrapper,
+	groupsWrapper wrappers.GroupsWrapper,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		filePath, _ := cmd.Flags().GetString(commonParams.ProjectsFileFlag)
+		specs, err := readProjectsManifest(filePath)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedApplyingProjects)
+		}
+
+		plans := make([]*projectPlan, 0, len(specs))
+		for _, spec := range specs {
+			plan, planErr := planProject(spec, projectsWrapper, groupsWrapper)
+			if planErr != nil {
+				return errors.Wrapf(planErr, "%s: %s", failedApplyingProjects, spec.Name)
+			}
+			plans = append(plans, plan)
+		}
+
+		err = printByFormat(cmd, toProjectPlanViews(plans))
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedApplyingProjects)
+		}
+
+		created, updated := countProjectPlans(plans)
+		if created+updated == 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No changes to apply.")
+			return nil
+		}
+		confirmMessage := fmt.Sprintf("%d project(s) will be created and %d updated.", created, updated)
+		if !confirmAction(cmd, confirmMessage) {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Project apply canceled.")
+			return nil
+		}
+
+		var failedProjects []string
+		for _, plan := range plans {
+			err = applyProjectPlan(plan, projectsWrapper)
+			if err != nil {
+				failedProjects = append(failedProjects, fmt.Sprintf("%s (%s)", plan.spec.Name, err.Error()))
+			}
+		}
+		if len(failedProjects) > 0 {
+			return errors.Errorf(
+				"%s for %d of %d project(s): %s",
+				failedApplyingProjects, len(failedProjects), created+updated, strings.Join(failedProjects, ", "),
+			)
+		}
+		return nil
+	}
+}
+
+// readProjectsManifest Read and validate the projects described in a YAML file
+func readProjectsManifest(filePath string) ([]projectSpec, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest projectsManifest
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid projects file %s", filePath)
+	}
+	if len(manifest.Projects) == 0 {
+		return nil, errors.Errorf("no projects defined in %s", filePath)
+	}
+
+	names := make(map[string]bool)
+	for i := range manifest.Projects {
+		spec := &manifest.Projects[i]
+		if strings.TrimSpace(spec.Name) == "" {
+			return nil, errors.New("project name is required")
+		}
+		if names[spec.Name] {
+			return nil, errors.Errorf("project %s is defined more than once", spec.Name)
+		}
+		names[spec.Name] = true
+		if spec.SSHKey != "" {
+			if spec.RepoURL == "" {
+				return nil, errors.Errorf("project %s: repoUrl is mandatory when sshKey is provided", spec.Name)
+			}
+			if !util.IsSSHURL(spec.RepoURL) {
+				return nil, errors.Errorf("project %s: %s", spec.Name, invalidRepoURL)
+			}
+			if spec.SSHKey, err = util.ExpandHomeDir(spec.SSHKey); err != nil {
+				return nil, errors.Wrapf(err, "project %s: failed expanding sshKey", spec.Name)
+			}
+		}
+	}
+	return manifest.Projects, nil
+}
+
+// planProject Compare the desired state of a project with the existing one
+func planProject(
+	spec projectSpec,
+	projectsWrapper wrappers.ProjectsWrapper,
+	groupsWrapper wrappers.GroupsWrapper,
+) (*projectPlan, error) {
+	var groups []string
+	if spec.Groups != nil {
+		var err error
+		groups, err = createGroupsMap(strings.Join(spec.Groups, ","), groupsWrapper)
+		if err != nil {
+			return nil, err
+		}
+		if groups == nil {
+			groups = []string{}
+		}
+	}
+
+	existing, err := findProjectByName(spec.Name, projectsWrapper)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &projectPlan{spec: spec, action: projectActionNone}
+	if existing == nil {
+		plan.action = projectActionCreate
+		plan.model = wrappers.Project{Name: spec.Name}
+	} else {
+		plan.projectID = existing.ID
+		plan.model = toProjectModel(existing)
+	}
+
+	if spec.MainBranch != "" && spec.MainBranch != plan.model.MainBranch {
+		plan.changes = append(plan.changes, fmt.Sprintf("mainBranch: %q -> %q", plan.model.MainBranch, spec.MainBranch))
+		plan.model.MainBranch = spec.MainBranch
+	}
+	if spec.RepoURL != "" && spec.RepoURL != plan.model.RepoURL {
+		plan.changes = append(plan.changes, fmt.Sprintf("repoUrl: %q -> %q", plan.model.RepoURL, spec.RepoURL))
+		plan.model.RepoURL = spec.RepoURL
+		plan.repoConfigure = true
+	}
+	// The key of the project can't be read back, so it is set on every apply
+	if spec.SSHKey != "" {
+		plan.changes = append(plan.changes, "sshKey: set")
+		plan.repoConfigure = true
+	}
+	if groups != nil && !sameStrings(groups, plan.model.Groups) {
+		plan.changes = append(plan.changes, fmt.Sprintf("groups: -> [%s]", strings.Join(spec.Groups, ",")))
+		plan.model.Groups = groups
+	}
+	if spec.Tags != nil && !sameTags(spec.Tags, plan.model.Tags) {
+		plan.changes = append(plan.changes, fmt.Sprintf("tags: [%s] -> [%s]", formatTags(plan.model.Tags), formatTags(spec.Tags)))
+		plan.model.Tags = spec.Tags
+	}
+
+	if plan.action == projectActionNone && len(plan.changes) > 0 {
+		plan.action = projectActionUpdate
+	}
+	return plan, nil
+}
+
+func findProjectByName(name string, projectsWrapper wrappers.ProjectsWrapper) (*wrappers.ProjectResponseModel, error) {
+	params := map[string]string{"name": name}
+	resp, errorModel, err := projectsWrapper.Get(params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", failedGettingProj)
+	}
+	if errorModel != nil {
+		return nil, errors.Errorf(ErrorCodeFormat, failedGettingProj, errorModel.Code, errorModel.Message)
+	}
+	for i := range resp.Projects {
+		if resp.Projects[i].Name == name {
+			return &resp.Projects[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func applyProjectPlan(plan *projectPlan, projectsWrapper wrappers.ProjectsWrapper) error {
+	switch plan.action {
+	case projectActionCreate:
+		projResponseModel, errorModel, err := projectsWrapper.Create(&plan.model)
+		if err != nil {
+			return err
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedCreatingProj, errorModel.Code, errorModel.Message)
+		}
+		plan.projectID = projResponseModel.ID
+	case projectActionUpdate:
+		err := projectsWrapper.Update(plan.projectID, &plan.model)
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if plan.repoConfigure {
+		return updateRepositoryConfiguration(projectsWrapper, plan.projectID, plan.spec.RepoURL, plan.spec.SSHKey)
+	}
+	return nil
+}
+
+func countProjectPlans(plans []*projectPlan) (created, updated int) {
+	for _, plan := range plans {
+		switch plan.action {
+		case projectActionCreate:
+			created++
+		case projectActionUpdate:
+			updated++
+		}
+	}
+	return created, updated
+}
+
+func toProjectPlanViews(plans []*projectPlan) []projectPlanView {
+	views := make([]projectPlanView, len(plans))
+	for i, plan := range plans {
+		views[i] = projectPlanView{
+			Name:    plan.spec.Name,
+			Action:  plan.action,
+			Changes: strings.Join(plan.changes, "; "),
+		}
+	}
+	return views
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTags(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if otherValue, ok := b[key]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTags(tags map[string]string) string {
+	formatted := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if value == "" {
+			formatted = append(formatted, key)
+		} else {
+			formatted = append(formatted, key+":"+value)
+		}
+	}
+	sort.Strings(formatted)
+	return strings.Join(formatted, ",")
+}
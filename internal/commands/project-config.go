@@ -0,0 +1,263 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	failedGettingProjConfig   = "Failed getting the project configuration"
+	failedSettingProjConfig   = "Failed setting the project configuration"
+	failedUnsettingProjConfig = "Failed unsetting the project configuration"
+	secretValueType           = "Secret"
+	secretValueMask           = "********"
+)
+
+func newProjectConfigCommand(projectsWrapper wrappers.ProjectsWrapper) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration of a project",
+		Long:  "The project config command enables the ability to show and manage the configuration of a project in Checkmarx One.",
+		Annotations: map[string]string{
+			"command:doc": heredoc.Doc(
+				`
+				https://checkmarx.com/resource/documents/en/34965-68634-project.html
+			`,
+			),
+		},
+	}
+
+	showConfigCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the configuration of a project",
+		Example: heredoc.Doc(
+			`
+			$ cx project config show --project-id <project_id>
+		`,
+		),
+		RunE: runShowProjectConfigCommand(projectsWrapper),
+	}
+	addProjectIDFlag(showConfigCmd, "Project ID to show the configuration.")
+	addFormatFlag(showConfigCmd, printer.FormatTable, printer.FormatJSON, printer.FormatList)
+
+	setConfigCmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set a configuration key of a project",
+		Example: heredoc.Doc(
+			`
+			$ cx project config set --project-id <project_id> --key scan.config.sast.presetName --value "Checkmarx Default"
+		`,
+		),
+		RunE: runSetProjectConfigCommand(projectsWrapper),
+	}
+	addProjectIDFlag(setConfigCmd, "Project ID to update the configuration.")
+	setConfigCmd.PersistentFlags().String(commonParams.ConfigKeyFlag, "", "Configuration key to set")
+	setConfigCmd.PersistentFlags().String(commonParams.ConfigValueFlag, "", "Value of the configuration key")
+	_ = setConfigCmd.MarkPersistentFlagRequired(commonParams.ConfigKeyFlag)
+	_ = setConfigCmd.MarkPersistentFlagRequired(commonParams.ConfigValueFlag)
+
+	unsetConfigCmd := &cobra.Command{
+		Use:   "unset",
+		Short: "Unset a configuration key of a project, inheriting the tenant value",
+		Example: heredoc.Doc(
+			`
+			$ cx project config unset --project-id <project_id> --key scan.config.sast.presetName
+		`,
+		),
+		RunE: runUnsetProjectConfigCommand(projectsWrapper),
+	}
+	addProjectIDFlag(unsetConfigCmd, "Project ID to update the configuration.")
+	unsetConfigCmd.PersistentFlags().String(commonParams.ConfigKeyFlag, "", "Configuration key to unset")
+	_ = unsetConfigCmd.MarkPersistentFlagRequired(commonParams.ConfigKeyFlag)
+
+	configCmd.AddCommand(showConfigCmd, setConfigCmd, unsetConfigCmd)
+	return configCmd
+}
+
+func runShowProjectConfigCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		configuration, err := getProjectConfigurationByID(cmd, projectsWrapper, failedGettingProjConfig)
+		if err != nil {
+			return err
+		}
+		return printByFormat(cmd, toProjectConfigurationViews(configuration))
+	}
+}
+
+func runSetProjectConfigCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetString(commonParams.ProjectIDFlag)
+		key, _ := cmd.Flags().GetString(commonParams.ConfigKeyFlag)
+		value, _ := cmd.Flags().GetString(commonParams.ConfigValueFlag)
+		configuration, err := getProjectConfigurationByID(cmd, projectsWrapper, failedSettingProjConfig)
+		if err != nil {
+			return err
+		}
+		config, err := findProjectConfiguration(configuration, key)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedSettingProjConfig)
+		}
+		if !config.AllowOverride && config.OriginLevel != projOriginLevel {
+			return errors.Errorf("%s: %s can't be overridden at project level", failedSettingProjConfig, key)
+		}
+		err = validateConfigurationValue(config, value)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedSettingProjConfig)
+		}
+
+		config.OriginLevel = projOriginLevel
+		config.Value = value
+		errorModel, err := projectsWrapper.UpdateConfiguration(projectID, []wrappers.ProjectConfiguration{*config})
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedSettingProjConfig)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedSettingProjConfig, errorModel.Code, errorModel.Message)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Configuration %s set for project %s\n", key, projectID)
+		return nil
+	}
+}
+
+func runUnsetProjectConfigCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetString(commonParams.ProjectIDFlag)
+		key, _ := cmd.Flags().GetString(commonParams.ConfigKeyFlag)
+		configuration, err := getProjectConfigurationByID(cmd, projectsWrapper, failedUnsettingProjConfig)
+		if err != nil {
+			return err
+		}
+		config, err := findProjectConfiguration(configuration, key)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedUnsettingProjConfig)
+		}
+		if config.OriginLevel != projOriginLevel {
+			return errors.Errorf("%s: %s is not set at project level", failedUnsettingProjConfig, key)
+		}
+
+		errorModel, err := projectsWrapper.DeleteConfiguration(projectID, []string{key})
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedUnsettingProjConfig)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedUnsettingProjConfig, errorModel.Code, errorModel.Message)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Configuration %s unset for project %s\n", key, projectID)
+		return nil
+	}
+}
+
+func getProjectConfigurationByID(
+	cmd *cobra.Command,
+	projectsWrapper wrappers.ProjectsWrapper,
+	failureMessage string,
+) ([]wrappers.ProjectConfiguration, error) {
+	projectID, _ := cmd.Flags().GetString(commonParams.ProjectIDFlag)
+	if projectID == "" {
+		return nil, errors.Errorf("%s: Please provide a project ID", failureMessage)
+	}
+	configuration, errorModel, err := projectsWrapper.GetConfiguration(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", failureMessage)
+	}
+	if errorModel != nil {
+		return nil, errors.Errorf(ErrorCodeFormat, failureMessage, errorModel.Code, errorModel.Message)
+	}
+	return configuration, nil
+}
+
+func findProjectConfiguration(configuration []wrappers.ProjectConfiguration, key string) (*wrappers.ProjectConfiguration, error) {
+	for i := range configuration {
+		if configuration[i].Key == key {
+			config := configuration[i]
+			return &config, nil
+		}
+	}
+	return nil, errors.Errorf("unknown configuration key %s", key)
+}
+
+// validateConfigurationValue Check the value against the type of the configuration key
+func validateConfigurationValue(config *wrappers.ProjectConfiguration, value string) error {
+	var err error
+	switch strings.ToLower(config.ValueType) {
+	case "bool", "boolean":
+		_, err = strconv.ParseBool(value)
+	case "integer", "int":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "json":
+		if !json.Valid([]byte(value)) {
+			err = errors.New("invalid json")
+		}
+	case "list":
+		err = validateConfigurationListValues(config, []string{value})
+	case "multilist":
+		err = validateConfigurationListValues(config, strings.Split(value, ","))
+	}
+	if err != nil {
+		return errors.Errorf("invalid value %q for %s of type %s", value, config.Key, config.ValueType)
+	}
+	return nil
+}
+
+func validateConfigurationListValues(config *wrappers.ProjectConfiguration, values []string) error {
+	if config.ValueTypeParams == "" {
+		return nil
+	}
+	allowedValues := strings.Split(config.ValueTypeParams, ",")
+	for _, value := range values {
+		if !containsTrimmed(allowedValues, value) {
+			return errors.Errorf("%s is not one of %s", value, config.ValueTypeParams)
+		}
+	}
+	return nil
+}
+
+func containsTrimmed(values []string, value string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) == strings.TrimSpace(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func toProjectConfigurationViews(configuration []wrappers.ProjectConfiguration) []projectConfigurationView {
+	views := make([]projectConfigurationView, len(configuration))
+	for i := range configuration {
+		value := configuration[i].Value
+		if configuration[i].ValueType == secretValueType && value != "" {
+			value = secretValueMask
+		}
+		views[i] = projectConfigurationView{
+			Key:           configuration[i].Key,
+			Name:          configuration[i].Name,
+			Category:      configuration[i].Category,
+			OriginLevel:   configuration[i].OriginLevel,
+			Value:         value,
+			ValueType:     configuration[i].ValueType,
+			AllowOverride: configuration[i].AllowOverride,
+		}
+	}
+	return views
+}
+
+type projectConfigurationView struct {
+	Key           string
+	Name          string
+	Category      string
+	OriginLevel   string `format:"name:Origin level"`
+	Value         string
+	ValueType     string `format:"name:Value type"`
+	AllowOverride bool   `format:"name:Allow override"`
+}
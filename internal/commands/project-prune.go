@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	failedPruningProjects = "Failed pruning projects"
+	pruneDeleteProject    = "Delete project"
+	pruneDeleteScan       = "Delete scan"
+	defaultKeepTag        = "keep"
+)
+
+type projectPruneView struct {
+	ProjectName string `format:"name:Project name"`
+	ProjectID   string `format:"name:Project ID"`
+	Branch      string `format:"omitempty"`
+	ScanID      string `format:"name:Scan ID;omitempty"`
+	Action      string
+	Reason      string
+	Result      string `format:"omitempty"`
+	Error       string `format:"omitempty"`
+}
+
+type pruneProjectsPolicy struct {
+	keepScans     int
+	inactiveSince time.Time
+	keepTags      []string
+}
+
+func newPruneProjectsCommand(projectsWrapper wrappers.ProjectsWrapper, scansWrapper wrappers.ScansWrapper) *cobra.Command {
+	pruneProjCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Deletes old scans and inactive projects",
+		Long: heredoc.Doc(
+			`
+			The project prune command applies retention policies to the projects in Checkmarx One.
+			It keeps the last scans of every branch and deletes the projects without recent scans.
+			Projects tagged with one of the keep tags are never touched. A plan is shown unless --apply is provided.
+		`,
+		),
+		Example: heredoc.Doc(
+			`
+			$ cx project prune --keep-scans 10 --inactive-days 120
+			$ cx project prune --keep-scans 10 --keep-tag keep,env:prod --apply
+		`,
+		),
+		Annotations: map[string]string{
+			"command:doc": heredoc.Doc(
+				`
+				https://checkmarx.com/resource/documents/en/34965-68634-project.html
+			`,
+			),
+		},
+		RunE: runPruneProjectsCommand(projectsWrapper, scansWrapper),
+	}
+	pruneProjCmd.PersistentFlags().Int(commonParams.KeepScansFlag, 0, "Number of scans to keep for every branch")
+	pruneProjCmd.PersistentFlags().Int(
+		commonParams.InactiveDaysFlag,
+		0,
+		"Delete the projects without scans in this number of days",
+	)
+	pruneProjCmd.PersistentFlags().StringSlice(
+		commonParams.KeepTagFlag,
+		[]string{defaultKeepTag},
+		"Never touch the projects with one of these tags, ex: keep,env:prod",
+	)
+	pruneProjCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterProjectsListFlagUsage)
+	pruneProjCmd.PersistentFlags().Bool(commonParams.ApplyFlag, false, "Apply the plan instead of only showing it")
+	addFormatFlag(pruneProjCmd, printer.FormatTable, printer.FormatJSON, printer.FormatList)
+	return pruneProjCmd
+}
+
+func runPruneProjectsCommand(
+	projectsWrapper wrappers.ProjectsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		policy, err := getPruneProjectsPolicy(cmd)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedPruningProjects)
+		}
+		params, err := getFilters(cmd)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedPruningProjects)
+		}
+
+		projects, err := getAllProjects(projectsWrapper, params)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedPruningProjects)
+		}
+		var plan []projectPruneView
+		for i := range projects {
+			projectPlan, planErr := planProjectPrune(&projects[i], policy, projectsWrapper, scansWrapper)
+			if planErr != nil {
+				return errors.Wrapf(planErr, "%s: %s", failedPruningProjects, projects[i].Name)
+			}
+			plan = append(plan, projectPlan...)
+		}
+		if len(plan) == 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Nothing to prune.")
+			return nil
+		}
+
+		if apply, _ := cmd.Flags().GetBool(commonParams.ApplyFlag); !apply {
+			err = printByFormat(cmd, plan)
+			if err != nil {
+				return errors.Wrapf(err, "%s", failedPruningProjects)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Dry run: use --%s to prune %d item(s).\n", commonParams.ApplyFlag, len(plan))
+			return nil
+		}
+
+		failed := applyProjectPrune(plan, projectsWrapper, scansWrapper)
+		err = printByFormat(cmd, plan)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedPruningProjects)
+		}
+		if failed > 0 {
+			return errors.Errorf("%s: %d of %d item(s) failed", failedPruningProjects, failed, len(plan))
+		}
+		return nil
+	}
+}
+
+func getPruneProjectsPolicy(cmd *cobra.Command) (*pruneProjectsPolicy, error) {
+	keepScans, _ := cmd.Flags().GetInt(commonParams.KeepScansFlag)
+	inactiveDays, _ := cmd.Flags().GetInt(commonParams.InactiveDaysFlag)
+	keepTags, _ := cmd.Flags().GetStringSlice(commonParams.KeepTagFlag)
+	if keepScans < 0 || inactiveDays < 0 {
+		return nil, errors.Errorf("--%s and --%s can't be negative", commonParams.KeepScansFlag, commonParams.InactiveDaysFlag)
+	}
+	if keepScans == 0 && inactiveDays == 0 {
+		return nil, errors.Errorf(
+			"Please provide at least one retention policy: --%s or --%s",
+			commonParams.KeepScansFlag, commonParams.InactiveDaysFlag,
+		)
+	}
+	policy := &pruneProjectsPolicy{keepScans: keepScans, keepTags: keepTags}
+	if inactiveDays > 0 {
+		policy.inactiveSince = time.Now().Add(-time.Duration(inactiveDays) * hoursPerDay * time.Hour)
+	}
+	return policy, nil
+}
+
+// planProjectPrune List the deletions the policy requires for a project
+func planProjectPrune(
+	project *wrappers.ProjectResponseModel,
+	policy *pruneProjectsPolicy,
+	projectsWrapper wrappers.ProjectsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) ([]projectPruneView, error) {
+	if hasKeepTag(project.Tags, policy.keepTags) {
+		return nil, nil
+	}
+
+	if !policy.inactiveSince.IsZero() {
+		lastScans, _, err := getScansPage(scansWrapper, map[string]string{
+			commonParams.ProjectIDQueryParam: project.ID,
+			commonParams.SortQueryParam:      "-created_at",
+			commonParams.LimitQueryParam:     "1",
+		})
+		if err != nil {
+			return nil, err
+		}
+		lastActivity := project.CreatedAt
+		reason := "No scans"
+		if len(lastScans) > 0 {
+			lastActivity = lastScans[0].CreatedAt
+			reason = fmt.Sprintf("Last scan at %s", lastActivity.Format(commonParams.DateLayout))
+		}
+		if lastActivity.Before(policy.inactiveSince) {
+			return []projectPruneView{
+				{ProjectName: project.Name, ProjectID: project.ID, Action: pruneDeleteProject, Reason: reason},
+			}, nil
+		}
+	}
+
+	if policy.keepScans == 0 {
+		return nil, nil
+	}
+	branches, errorModel, err := projectsWrapper.GetBranchesByID(project.ID, map[string]string{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", failedGettingBranches)
+	}
+	if errorModel != nil {
+		return nil, errors.Errorf(ErrorCodeFormat, failedGettingBranches, errorModel.Code, errorModel.Message)
+	}
+	var plan []projectPruneView
+	for _, branch := range branches {
+		// The branch filter of the scans isn't an exact match: the newest scans of the branch itself are counted
+		// locally, those of the other branches neither being kept nor deleted
+		params := map[string]string{
+			commonParams.ProjectIDQueryParam: project.ID,
+			commonParams.BranchQueryParam:    branch,
+			commonParams.SortQueryParam:      "-created_at",
+		}
+		kept := 0
+		err = readAllPages(params, bulkPageSize, 0, fetchScanModelsPage(scansWrapper), func(items []interface{}, _ int) (bool, error) {
+			for _, item := range items {
+				scan := item.(wrappers.ScanResponseModel)
+				if scan.Branch != branch || scan.Status == wrappers.ScanQueued || scan.Status == wrappers.ScanRunning {
+					continue
+				}
+				if kept < policy.keepScans {
+					kept++
+					continue
+				}
+				plan = append(plan, projectPruneView{
+					ProjectName: project.Name,
+					ProjectID:   project.ID,
+					Branch:      branch,
+					ScanID:      scan.ID,
+					Action:      pruneDeleteScan,
+					Reason:      fmt.Sprintf("Older than the last %d scans of the branch", policy.keepScans),
+				})
+			}
+			return false, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// applyProjectPrune Run the deletions of the plan, recording the outcome of each one, and return the failures count
+func applyProjectPrune(plan []projectPruneView, projectsWrapper wrappers.ProjectsWrapper, scansWrapper wrappers.ScansWrapper) int {
+	var scanIDs, projectIDs []string
+	for i := range plan {
+		if plan[i].Action == pruneDeleteScan {
+			scanIDs = append(scanIDs, plan[i].ScanID)
+		} else {
+			projectIDs = append(projectIDs, plan[i].ProjectID)
+		}
+	}
+	outcomes := make(map[string]bulkOutcomeView)
+	for _, outcome := range applyBulkAction(scanIDs, scansWrapper.Delete) {
+		outcomes[outcome.ID] = outcome
+	}
+	for _, outcome := range applyBulkAction(projectIDs, projectsWrapper.Delete) {
+		outcomes[outcome.ID] = outcome
+	}
+
+	failed := 0
+	for i := range plan {
+		id := plan[i].ProjectID
+		if plan[i].Action == pruneDeleteScan {
+			id = plan[i].ScanID
+		}
+		plan[i].Result = outcomes[id].Result
+		plan[i].Error = outcomes[id].Error
+		if plan[i].Result == bulkResultError {
+			failed++
+		}
+	}
+	return failed
+}
+
+func getAllProjects(projectsWrapper wrappers.ProjectsWrapper, params map[string]string) ([]wrappers.ProjectResponseModel, error) {
+	var projects []wrappers.ProjectResponseModel
+	fetch := func(pageParams map[string]string) ([]interface{}, int, error) {
+		page, total, err := getProjectsPage(projectsWrapper, pageParams)
+		items := make([]interface{}, len(page))
+		for i := range page {
+			items[i] = page[i]
+		}
+		return items, total, err
+	}
+	err := readAllPages(params, bulkPageSize, 0, fetch, func(items []interface{}, _ int) (bool, error) {
+		for _, item := range items {
+			projects = append(projects, item.(wrappers.ProjectResponseModel))
+		}
+		return false, nil
+	})
+	return projects, err
+}
+
+// hasKeepTag Check the project tags against keep tags given as key or key:value
+func hasKeepTag(tags map[string]string, keepTags []string) bool {
+	for _, keepTag := range keepTags {
+		keyValuePair := strings.SplitN(keepTag, ":", commonParams.KeyValuePairSize)
+		value, ok := tags[keyValuePair[0]]
+		if ok && (len(keyValuePair) == 1 || keyValuePair[1] == value) {
+			return true
+		}
+	}
+	return false
+}
@@ -61,7 +61,11 @@ var (
 	)
 )
 
-func NewProjectCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper wrappers.GroupsWrapper) *cobra.Command {
+func NewProjectCommand(
+	projectsWrapper wrappers.ProjectsWrapper,
+	groupsWrapper wrappers.GroupsWrapper,
+	scansWrapper wrappers.ScansWrapper,
+) *cobra.Command {
 	projCmd := &cobra.Command{
 		Use:   "project",
 		Short: "Manage projects",
@@ -100,6 +104,32 @@ func NewProjectCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper w
 	createProjCmd.PersistentFlags().String(commonParams.SSHKeyFlag, "", "Path to ssh private key")
 	createProjCmd.PersistentFlags().String(commonParams.RepoURLFlag, "", "Repository URL")
 
+	updateProjCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Updates an existing project",
+		Long:  "The project update command enables the ability to update the name, main branch, groups, tags and repository of a project in Checkmarx One.",
+		Example: heredoc.Doc(
+			`
+			$ cx project update --project-id <project_id> --project-name <Project Name> --branch <branch>
+		`,
+		),
+		Annotations: map[string]string{
+			"command:doc": heredoc.Doc(
+				`
+				https://checkmarx.com/resource/documents/en/34965-68634-project.html
+			`,
+			),
+		},
+		RunE: runUpdateProjectCommand(projectsWrapper, groupsWrapper),
+	}
+	addProjectIDFlag(updateProjCmd, "Project ID to update.")
+	updateProjCmd.PersistentFlags().String(commonParams.TagList, "", "List of tags to replace the project tags, ex: (tagA,tagB:val,etc)")
+	updateProjCmd.PersistentFlags().String(commonParams.GroupList, "", "List of groups to replace the project groups, ex: (PowerUsers,etc)")
+	updateProjCmd.PersistentFlags().StringP(commonParams.ProjectName, "", "", "New name of the project")
+	updateProjCmd.PersistentFlags().StringP(commonParams.MainBranchFlag, "", "", "Main branch")
+	updateProjCmd.PersistentFlags().String(commonParams.SSHKeyFlag, "", "Path to ssh private key")
+	updateProjCmd.PersistentFlags().String(commonParams.RepoURLFlag, "", "Repository URL")
+
 	listProjectsCmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all projects in the system",
@@ -118,6 +148,7 @@ func NewProjectCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper w
 		RunE: runListProjectsCommand(projectsWrapper),
 	}
 	listProjectsCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterProjectsListFlagUsage)
+	addPaginationFlags(listProjectsCmd)
 
 	showProjectCmd := &cobra.Command{
 		Use:   "show",
@@ -157,6 +188,7 @@ func NewProjectCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper w
 	}
 	addProjectIDFlag(projectBranchesCmd, "Project ID to get branches.")
 	projectBranchesCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterBranchesFlagUsage)
+	addPaginationFlags(projectBranchesCmd)
 
 	deleteProjCmd := &cobra.Command{
 		Use:   "delete",
@@ -196,12 +228,23 @@ func NewProjectCommand(projectsWrapper wrappers.ProjectsWrapper, groupsWrapper w
 	}
 
 	addFormatFlagToMultipleCommands(
-		[]*cobra.Command{showProjectCmd, listProjectsCmd, createProjCmd},
+		[]*cobra.Command{showProjectCmd, listProjectsCmd, createProjCmd, updateProjCmd},
 		printer.FormatTable,
 		printer.FormatJSON,
 		printer.FormatList,
 	)
-	projCmd.AddCommand(createProjCmd, projectBranchesCmd, showProjectCmd, listProjectsCmd, deleteProjCmd, tagsCmd)
+	projCmd.AddCommand(
+		createProjCmd,
+		updateProjCmd,
+		newApplyProjectsCommand(projectsWrapper, groupsWrapper),
+		newProjectConfigCommand(projectsWrapper),
+		newPruneProjectsCommand(projectsWrapper, scansWrapper),
+		projectBranchesCmd,
+		showProjectCmd,
+		listProjectsCmd,
+		deleteProjCmd,
+		tagsCmd,
+	)
 	return projCmd
 }
 
@@ -334,36 +377,125 @@ func runCreateProjectCommand(
 	}
 }
 
+func runUpdateProjectCommand(
+	projectsWrapper wrappers.ProjectsWrapper,
+	groupsWrapper wrappers.GroupsWrapper,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetString(commonParams.ProjectIDFlag)
+		if projectID == "" {
+			return errors.Errorf("%s: Please provide a project ID", failedUpdatingProj)
+		}
+		err := validateConfiguration(cmd)
+		if err != nil {
+			return err
+		}
+		projectResponseModel, errorModel, err := projectsWrapper.GetByID(projectID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedUpdatingProj)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedUpdatingProj, errorModel.Code, errorModel.Message)
+		}
+
+		// The update replaces the whole project, so start from the current values
+		projModel := toProjectModel(projectResponseModel)
+		if cmd.Flags().Changed(commonParams.ProjectName) {
+			projModel.Name, _ = cmd.Flags().GetString(commonParams.ProjectName)
+			if strings.TrimSpace(projModel.Name) == "" {
+				return errors.Errorf("%s: Project name can't be empty", failedUpdatingProj)
+			}
+		}
+		if cmd.Flags().Changed(commonParams.MainBranchFlag) {
+			projModel.MainBranch, _ = cmd.Flags().GetString(commonParams.MainBranchFlag)
+		}
+		if cmd.Flags().Changed(commonParams.RepoURLFlag) {
+			projModel.RepoURL, _ = cmd.Flags().GetString(commonParams.RepoURLFlag)
+		}
+		if cmd.Flags().Changed(commonParams.TagList) {
+			tagListStr, _ := cmd.Flags().GetString(commonParams.TagList)
+			projModel.Tags = createTagMap(tagListStr)
+		}
+		if cmd.Flags().Changed(commonParams.GroupList) {
+			groupListStr, _ := cmd.Flags().GetString(commonParams.GroupList)
+			projModel.Groups, err = createGroupsMap(groupListStr, groupsWrapper)
+			if err != nil {
+				return err
+			}
+		}
+
+		payload, _ := json.Marshal(projModel)
+		logger.PrintIfVerbose(fmt.Sprintf("Payload to projects service: %s\n", string(payload)))
+		err = projectsWrapper.Update(projectID, &projModel)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedUpdatingProj)
+		}
+
+		err = updateProjectConfigurationIfNeeded(cmd, projectsWrapper, projectID)
+		if err != nil {
+			return err
+		}
+
+		projectResponseModel, errorModel, err = projectsWrapper.GetByID(projectID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingProj)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedGettingProj, errorModel.Code, errorModel.Message)
+		}
+		return printByFormat(cmd, toProjectView(*projectResponseModel))
+	}
+}
+
+func toProjectModel(model *wrappers.ProjectResponseModel) wrappers.Project {
+	return wrappers.Project{
+		Name:       model.Name,
+		RepoURL:    model.RepoURL,
+		MainBranch: model.MainBranch,
+		Origin:     model.Origin,
+		ScmRepoID:  model.ScmRepoID,
+		Tags:       model.Tags,
+		Groups:     model.Groups,
+	}
+}
+
 func updateProjectConfigurationIfNeeded(cmd *cobra.Command, projectsWrapper wrappers.ProjectsWrapper, projectID string) error {
 	// Just update project configuration id a repository url is defined
 	if cmd.Flags().Changed(commonParams.RepoURLFlag) {
-		var projectConfigurations []wrappers.ProjectConfiguration
-
 		repoURL, _ := cmd.Flags().GetString(commonParams.RepoURLFlag)
+		sshKeyPath := ""
+		if cmd.Flags().Changed(commonParams.SSHKeyFlag) {
+			sshKeyPath, _ = cmd.Flags().GetString(commonParams.SSHKeyFlag)
+		}
+		return updateRepositoryConfiguration(projectsWrapper, projectID, repoURL, sshKeyPath)
+	}
 
-		urlConf := getProjectConfiguration(repoConfKey, "repository", git, projOriginLevel, repoURL, "String", true)
+	return nil
+}
 
-		projectConfigurations = append(projectConfigurations, urlConf)
+func updateRepositoryConfiguration(projectsWrapper wrappers.ProjectsWrapper, projectID, repoURL, sshKeyPath string) error {
+	var projectConfigurations []wrappers.ProjectConfiguration
 
-		if cmd.Flags().Changed(commonParams.SSHKeyFlag) {
-			sshKeyPath, _ := cmd.Flags().GetString(commonParams.SSHKeyFlag)
+	urlConf := getProjectConfiguration(repoConfKey, "repository", git, projOriginLevel, repoURL, "String", true)
 
-			sshKey, sshErr := util.ReadFileAsString(sshKeyPath)
-			if sshErr != nil {
-				return sshErr
-			}
+	projectConfigurations = append(projectConfigurations, urlConf)
 
-			viper.Set(commonParams.SSHValue, sshKey)
+	if sshKeyPath != "" {
+		sshKey, sshErr := util.ReadFileAsString(sshKeyPath)
+		if sshErr != nil {
+			return sshErr
+		}
 
-			sshKeyConf := getProjectConfiguration(sshConfKey, "sshKey", git, projOriginLevel, sshKey, "Secret", true)
+		viper.Set(commonParams.SSHValue, sshKey)
 
-			projectConfigurations = append(projectConfigurations, sshKeyConf)
-		}
+		sshKeyConf := getProjectConfiguration(sshConfKey, "sshKey", git, projOriginLevel, sshKey, "Secret", true)
 
-		_, configErr := projectsWrapper.UpdateConfiguration(projectID, projectConfigurations)
-		if configErr != nil {
-			return configErr
-		}
+		projectConfigurations = append(projectConfigurations, sshKeyConf)
+	}
+
+	_, configErr := projectsWrapper.UpdateConfiguration(projectID, projectConfigurations)
+	if configErr != nil {
+		return configErr
 	}
 
 	return nil
@@ -436,6 +568,10 @@ func runListProjectsCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *
 			return errors.Wrapf(err, "%s", failedGettingAll)
 		}
 
+		if isAllPages(cmd) {
+			return streamAllPages(cmd, params, "projects", fetchProjectsPage(projectsWrapper))
+		}
+
 		allProjectsModel, errorModel, err = projectsWrapper.Get(params)
 		if err != nil {
 			return errors.Wrapf(err, "%s\n", failedGettingAll)
@@ -454,6 +590,31 @@ func runListProjectsCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *
 	}
 }
 
+func fetchProjectsPage(projectsWrapper wrappers.ProjectsWrapper) pageFetcher {
+	return func(params map[string]string) ([]interface{}, int, error) {
+		projects, total, err := getProjectsPage(projectsWrapper, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]interface{}, len(projects))
+		for i := range projects {
+			items[i] = toProjectView(projects[i])
+		}
+		return items, total, nil
+	}
+}
+
+func getProjectsPage(projectsWrapper wrappers.ProjectsWrapper, params map[string]string) ([]wrappers.ProjectResponseModel, int, error) {
+	allProjectsModel, errorModel, err := projectsWrapper.Get(params)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "%s", failedGettingAll)
+	}
+	if errorModel != nil {
+		return nil, 0, errors.Errorf(ErrorCodeFormat, failedGettingAll, errorModel.Code, errorModel.Message)
+	}
+	return allProjectsModel.Projects, int(allProjectsModel.FilteredTotalCount), nil
+}
+
 func runGetProjectByIDCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		var projectResponseModel *wrappers.ProjectResponseModel
@@ -495,6 +656,12 @@ func runGetBranchesByIDCommand(projectsWrapper wrappers.ProjectsWrapper) func(cm
 			return errors.Wrapf(err, "%s", failedGettingAll)
 		}
 
+		if isAllPages(cmd) {
+			return streamAllPages(cmd, params, "branches", fetchBranchesPage(projectsWrapper, projectID))
+		}
+
+		// Only the pages of --all have their size, a single request gets the default limit of the branches
+		delete(params, commonParams.LimitQueryParam)
 		branches, errorModel, err = projectsWrapper.GetBranchesByID(projectID, params)
 
 		if err != nil {
@@ -520,6 +687,24 @@ func runGetBranchesByIDCommand(projectsWrapper wrappers.ProjectsWrapper) func(cm
 	}
 }
 
+// fetchBranchesPage The branches API doesn't report a total count, so pages are read one after the other
+func fetchBranchesPage(projectsWrapper wrappers.ProjectsWrapper, projectID string) pageFetcher {
+	return func(params map[string]string) ([]interface{}, int, error) {
+		branches, errorModel, err := projectsWrapper.GetBranchesByID(projectID, params)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "%s", failedGettingBranches)
+		}
+		if errorModel != nil {
+			return nil, 0, errors.Errorf("%s: CODE: %d, %s", failedGettingBranches, errorModel.Code, errorModel.Message)
+		}
+		items := make([]interface{}, len(branches))
+		for i := range branches {
+			items[i] = branches[i]
+		}
+		return items, unknownTotal, nil
+	}
+}
+
 func runDeleteProjectCommand(projectsWrapper wrappers.ProjectsWrapper) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		var errorModel *wrappers.ErrorModel
@@ -3,11 +3,20 @@
 package commands
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 
 	"github.com/checkmarx/ast-cli/internal/commands/util"
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/checkmarx/ast-cli/internal/wrappers/mock"
 )
 
 func TestProjectHelp(t *testing.T) {
@@ -164,3 +173,314 @@ func TestCreateProjectWithSSHKey(t *testing.T) {
 
 	execCmdNilAssertion(t, append(baseArgs, "--ssh-key", "data/Dockerfile", "--repo-url", "git@github.com:dummyRepo/dummyProject.git")...)
 }
+
+func TestRunUpdateProjectCommand(t *testing.T) {
+	execCmdNilAssertion(t, "project", "update", "--project-id", "MOCK", "--project-name", "renamed", "--branch", "main", "--tags", "a:b")
+}
+
+func TestRunUpdateProjectCommandNoProjectID(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "project", "update", "--project-name", "renamed")
+	assert.Equal(t, err.Error(), "Failed updating a project: Please provide a project ID")
+}
+
+func TestRunUpdateProjectCommandEmptyName(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "project", "update", "--project-id", "MOCK", "--project-name", " ")
+	assert.Equal(t, err.Error(), "Failed updating a project: Project name can't be empty")
+}
+
+func TestRunUpdateProjectCommandWithSSHKey(t *testing.T) {
+	execCmdNilAssertion(t, "project", "update", "--project-id", "MOCK",
+		"--ssh-key", "data/Dockerfile", "--repo-url", "git@github.com:dummyRepo/dummyProject.git")
+}
+
+// branchesParamsRecorder Record the params of the branches requests
+type branchesParamsRecorder struct {
+	mock.ProjectsMockWrapper
+	params []map[string]string
+}
+
+func (r *branchesParamsRecorder) GetBranchesByID(projectID string, params map[string]string) ([]string, *wrappers.ErrorModel, error) {
+	recorded := make(map[string]string, len(params))
+	for key, value := range params {
+		recorded[key] = value
+	}
+	r.params = append(r.params, recorded)
+	return r.ProjectsMockWrapper.GetBranchesByID(projectID, params)
+}
+
+func TestRunGetBranchesByIDLimitOnlyWithAll(t *testing.T) {
+	recorder := &branchesParamsRecorder{}
+	cmd := NewProjectCommand(recorder, &mock.GroupsMockWrapper{}, &mock.ScansMockWrapper{})
+	cmd.SetArgs([]string{"branches", "--project-id", "MOCK", "--filter", "limit=5"})
+	assert.NilError(t, cmd.Execute())
+	_, limited := recorder.params[0][params.LimitQueryParam]
+	assert.Assert(t, !limited, "the wrapper sets the default limit")
+
+	recorder.params = nil
+	cmd = NewProjectCommand(recorder, &mock.GroupsMockWrapper{}, &mock.ScansMockWrapper{})
+	cmd.SetArgs([]string{"branches", "--project-id", "MOCK", "--all", "--page-size", "5", "--max-items", "1"})
+	assert.NilError(t, cmd.Execute())
+	assert.Equal(t, recorder.params[0][params.LimitQueryParam], "5")
+}
+
+func writeProjectsFile(t *testing.T, content string) string {
+	filePath := filepath.Join(t.TempDir(), "projects.yaml")
+	err := os.WriteFile(filePath, []byte(content), 0600)
+	assert.NilError(t, err)
+	return filePath
+}
+
+func TestRunApplyProjectsCommand(t *testing.T) {
+	filePath := writeProjectsFile(t, `
+projects:
+  - name: MOCK
+    mainBranch: main
+    tags:
+      team: backend
+  - name: new-project
+    repoUrl: git@github.com:dummyRepo/dummyProject.git
+    sshKey: data/Dockerfile
+`)
+	execCmdNilAssertion(t, "project", "apply", "-f", filePath, "--yes")
+}
+
+func TestRunApplyProjectsCommandHomeSSHKey(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	assert.NilError(t, os.Mkdir(filepath.Join(homeDir, ".ssh"), 0700))
+	assert.NilError(t, os.WriteFile(filepath.Join(homeDir, ".ssh", "id_rsa"), []byte("key"), 0600))
+	filePath := writeProjectsFile(t, `
+projects:
+  - name: new-project
+    repoUrl: git@github.com:dummyRepo/dummyProject.git
+    sshKey: ~/.ssh/id_rsa
+`)
+	execCmdNilAssertion(t, "project", "apply", "-f", filePath, "--yes")
+}
+
+func TestRunApplyProjectsCommandCanceled(t *testing.T) {
+	filePath := writeProjectsFile(t, "projects:\n  - name: new-project\n")
+	cmd := createASTTestCommand()
+	cmd.SetIn(bytes.NewBufferString("n\n"))
+	err := executeTestCommand(cmd, "project", "apply", "-f", filePath)
+	assert.NilError(t, err)
+}
+
+func TestRunApplyProjectsCommandInvalidFile(t *testing.T) {
+	filePath := writeProjectsFile(t, "projects:\n  - name: MOCK\n    branch: main\n")
+	err := execCmdNotNilAssertion(t, "project", "apply", "-f", filePath)
+	assert.Assert(t, strings.Contains(err.Error(), "field branch not found"), err.Error())
+
+	filePath = writeProjectsFile(t, "projects:\n  - name: MOCK\n  - name: MOCK\n")
+	err = execCmdNotNilAssertion(t, "project", "apply", "-f", filePath)
+	assert.Equal(t, err.Error(), "Failed applying projects: project MOCK is defined more than once")
+
+	filePath = writeProjectsFile(t, "projects:\n  - name: MOCK\n    sshKey: data/Dockerfile\n")
+	err = execCmdNotNilAssertion(t, "project", "apply", "-f", filePath)
+	assert.Equal(t, err.Error(), "Failed applying projects: project MOCK: repoUrl is mandatory when sshKey is provided")
+}
+
+func TestRunApplyProjectsCommandInvalidGroup(t *testing.T) {
+	filePath := writeProjectsFile(t, "projects:\n  - name: MOCK\n    groups: [invalidgroup]\n")
+	err := execCmdNotNilAssertion(t, "project", "apply", "-f", filePath, "--yes")
+	assert.Equal(t, err.Error(), "Failed applying projects: MOCK: Failed finding groups: [invalidgroup]")
+}
+
+func TestPlanProject(t *testing.T) {
+	projectsWrapper := &mock.ProjectsMockWrapper{}
+	groupsWrapper := &mock.GroupsMockWrapper{}
+
+	plan, err := planProject(projectSpec{Name: "MOCK"}, projectsWrapper, groupsWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.action, projectActionNone)
+	assert.Equal(t, plan.projectID, "MOCK")
+
+	plan, err = planProject(projectSpec{Name: "MOCK", MainBranch: "main", Tags: map[string]string{"a": ""}}, projectsWrapper, groupsWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.action, projectActionUpdate)
+	assert.DeepEqual(t, plan.changes, []string{`mainBranch: "" -> "main"`, "tags: [] -> [a]"})
+
+	plan, err = planProject(projectSpec{Name: "other"}, projectsWrapper, groupsWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, plan.action, projectActionCreate)
+}
+
+// repoProjectsWrapper A project with a repository, recording its configuration updates
+type repoProjectsWrapper struct {
+	mock.ProjectsMockWrapper
+	configurations [][]wrappers.ProjectConfiguration
+}
+
+func (w *repoProjectsWrapper) Get(map[string]string) (*wrappers.ProjectsCollectionResponseModel, *wrappers.ErrorModel, error) {
+	return &wrappers.ProjectsCollectionResponseModel{
+		FilteredTotalCount: 1,
+		Projects:           []wrappers.ProjectResponseModel{{ID: "MOCK", Name: "MOCK", RepoURL: "https://github.com/checkmarx/ast-cli.git"}},
+	}, nil, nil
+}
+
+func (w *repoProjectsWrapper) UpdateConfiguration(_ string, configuration []wrappers.ProjectConfiguration) (*wrappers.ErrorModel, error) {
+	w.configurations = append(w.configurations, configuration)
+	return nil, nil
+}
+
+func TestPlanProjectSSHKeyWithSameRepoURL(t *testing.T) {
+	projectsWrapper := &repoProjectsWrapper{}
+	spec := projectSpec{Name: "MOCK", RepoURL: "https://github.com/checkmarx/ast-cli.git"}
+
+	plan, err := planProject(spec, projectsWrapper, &mock.GroupsMockWrapper{})
+	assert.NilError(t, err)
+	assert.Equal(t, plan.action, projectActionNone)
+
+	spec.SSHKey = "data/Dockerfile"
+	plan, err = planProject(spec, projectsWrapper, &mock.GroupsMockWrapper{})
+	assert.NilError(t, err)
+	assert.Equal(t, plan.action, projectActionUpdate)
+	assert.DeepEqual(t, plan.changes, []string{"sshKey: set"})
+
+	assert.NilError(t, applyProjectPlan(plan, projectsWrapper))
+	assert.Equal(t, len(projectsWrapper.configurations), 1)
+	assert.Equal(t, len(projectsWrapper.configurations[0]), 2)
+	assert.Equal(t, projectsWrapper.configurations[0][1].Name, "sshKey")
+}
+
+func TestRunShowProjectConfigCommand(t *testing.T) {
+	execCmdNilAssertion(t, "project", "config", "show", "--project-id", "MOCK", "--format", "json")
+}
+
+func TestRunShowProjectConfigCommandNoProjectID(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "project", "config", "show")
+	assert.Equal(t, err.Error(), "Failed getting the project configuration: Please provide a project ID")
+}
+
+func TestRunSetProjectConfigCommand(t *testing.T) {
+	execCmdNilAssertion(t, "project", "config", "set", "--project-id", "MOCK",
+		"--key", "scan.config.sast.presetName", "--value", "ASA Premium")
+	execCmdNilAssertion(t, "project", "config", "set", "--project-id", "MOCK",
+		"--key", "scan.config.sast.incremental", "--value", "true")
+}
+
+func TestRunSetProjectConfigCommandInvalid(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "project", "config", "set", "--project-id", "MOCK",
+		"--key", "scan.config.sast.incremental", "--value", "maybe")
+	assert.Equal(t, err.Error(),
+		`Failed setting the project configuration: invalid value "maybe" for scan.config.sast.incremental of type Bool`)
+
+	err = execCmdNotNilAssertion(t, "project", "config", "set", "--project-id", "MOCK",
+		"--key", "scan.config.sast.presetName", "--value", "unknown")
+	assert.Equal(t, err.Error(),
+		`Failed setting the project configuration: invalid value "unknown" for scan.config.sast.presetName of type List`)
+
+	err = execCmdNotNilAssertion(t, "project", "config", "set", "--project-id", "MOCK",
+		"--key", "scan.config.sast.languages", "--value", "Java")
+	assert.Equal(t, err.Error(),
+		"Failed setting the project configuration: scan.config.sast.languages can't be overridden at project level")
+
+	err = execCmdNotNilAssertion(t, "project", "config", "set", "--project-id", "MOCK", "--key", "unknown", "--value", "a")
+	assert.Equal(t, err.Error(), "Failed setting the project configuration: unknown configuration key unknown")
+}
+
+func TestRunUnsetProjectConfigCommand(t *testing.T) {
+	execCmdNilAssertion(t, "project", "config", "unset", "--project-id", "MOCK", "--key", "scan.config.sast.incremental")
+
+	err := execCmdNotNilAssertion(t, "project", "config", "unset", "--project-id", "MOCK", "--key", "scan.config.sast.presetName")
+	assert.Equal(t, err.Error(),
+		"Failed unsetting the project configuration: scan.config.sast.presetName is not set at project level")
+}
+
+func TestToProjectConfigurationViewsMasksSecrets(t *testing.T) {
+	configuration, _, _ := (&mock.ProjectsMockWrapper{}).GetConfiguration("MOCK")
+	views := toProjectConfigurationViews(configuration)
+	assert.Equal(t, views[3].Value, secretValueMask)
+	assert.Equal(t, views[0].Value, "Checkmarx Default")
+}
+
+func TestRunGetAllProjectsCommandAllPages(t *testing.T) {
+	execCmdNilAssertion(t, "project", "list", "--all", "--page-size", "10")
+}
+
+func TestRunGetProjectBranchesCommandAllPages(t *testing.T) {
+	execCmdNilAssertion(t, "project", "branches", "--project-id", "MOCK", "--all", "--max-items", "1")
+}
+
+func TestRunPruneProjectsCommand(t *testing.T) {
+	execCmdNilAssertion(t, "project", "prune", "--keep-scans", "1")
+	execCmdNilAssertion(t, "project", "prune", "--inactive-days", "120", "--apply")
+	execCmdNilAssertion(t, "project", "prune", "--keep-scans", "1", "--apply", "--format", "json")
+}
+
+func TestRunPruneProjectsCommandNoPolicy(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "project", "prune")
+	assert.Equal(t, err.Error(), "Failed pruning projects: Please provide at least one retention policy: --keep-scans or --inactive-days")
+}
+
+func TestPlanProjectPrune(t *testing.T) {
+	projectsWrapper := &mock.ProjectsMockWrapper{}
+	scansWrapper := &mock.ScansMockWrapper{}
+	project := &wrappers.ProjectResponseModel{ID: "MOCK", Name: "MOCK", Tags: map[string]string{"env": "prod"}}
+
+	plan, err := planProjectPrune(project, &pruneProjectsPolicy{keepScans: 1, keepTags: []string{"env:prod"}}, projectsWrapper, scansWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan), 0)
+
+	plan, err = planProjectPrune(project, &pruneProjectsPolicy{keepScans: 1, keepTags: []string{"env:dev"}}, projectsWrapper, scansWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan), 0, "the master scan is the one kept, and isn't deleted as a feature/MOCK scan")
+
+	policy := &pruneProjectsPolicy{keepScans: 1, inactiveSince: time.Now().AddDate(0, 0, -120)}
+	plan, err = planProjectPrune(project, policy, projectsWrapper, scansWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan), 1)
+	assert.Equal(t, plan[0].Action, pruneDeleteProject)
+}
+
+// similarBranchScansWrapper Match the branch filter as a substring, as the scans API does, newest scans first
+type similarBranchScansWrapper struct {
+	mock.ScansMockWrapper
+	scans []wrappers.ScanResponseModel
+}
+
+func (w *similarBranchScansWrapper) Get(filters map[string]string) (*wrappers.ScansCollectionResponseModel, *wrappers.ErrorModel, error) {
+	var filtered []wrappers.ScanResponseModel
+	for _, scan := range w.scans {
+		if strings.Contains(scan.Branch, filters[params.BranchQueryParam]) {
+			filtered = append(filtered, scan)
+		}
+	}
+	offset, _ := strconv.Atoi(filters[params.OffsetQueryParam])
+	limit, _ := strconv.Atoi(filters[params.LimitQueryParam])
+	end := offset + limit
+	if end > len(filtered) {
+		end = len(filtered)
+	}
+	var page []wrappers.ScanResponseModel
+	if offset < end {
+		page = filtered[offset:end]
+	}
+	return &wrappers.ScansCollectionResponseModel{Scans: page, FilteredTotalCount: uint(len(filtered))}, nil, nil
+}
+
+func TestPlanProjectPruneSimilarBranches(t *testing.T) {
+	scansWrapper := &similarBranchScansWrapper{scans: []wrappers.ScanResponseModel{
+		{ID: "master-4", Branch: "master", Status: wrappers.ScanRunning},
+		{ID: "master-old-2", Branch: "master-old"},
+		{ID: "master-3", Branch: "master"},
+		{ID: "master-old-1", Branch: "master-old"},
+		{ID: "master-2", Branch: "master"},
+		{ID: "master-1", Branch: "master"},
+	}}
+	project := &wrappers.ProjectResponseModel{ID: "MOCK", Name: "MOCK"}
+
+	plan, err := planProjectPrune(project, &pruneProjectsPolicy{keepScans: 2}, &mock.ProjectsMockWrapper{}, scansWrapper)
+	assert.NilError(t, err)
+	assert.Equal(t, len(plan), 1)
+	assert.Equal(t, plan[0].ScanID, "master-1")
+	assert.Equal(t, plan[0].Branch, "master")
+}
+
+func TestHasKeepTag(t *testing.T) {
+	tags := map[string]string{"keep": "", "env": "prod"}
+	assert.Assert(t, hasKeepTag(tags, []string{"keep"}))
+	assert.Assert(t, hasKeepTag(tags, []string{"env:prod"}))
+	assert.Assert(t, !hasKeepTag(tags, []string{"env:dev", "other"}))
+}
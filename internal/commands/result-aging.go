@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	failedGettingAging    = "Failed getting the results aging"
+	defaultSLA            = "high=30;medium=90;low=180"
+	defaultSLAWarningDays = 7
+	slaBreached           = "Breached"
+	slaDueSoon            = "Due soon"
+)
+
+type resultAgingView struct {
+	Engine       string
+	Owner        string
+	Severity     string
+	Status       string
+	DaysOpen     int       `format:"name:Days open"`
+	DaysLeft     int       `format:"name:Days left"`
+	FirstFoundAt time.Time `format:"name:First found at;time:01-02-06"`
+	Name         string
+	Location     string `format:"omitempty"`
+	SimilarityID string `format:"name:Similarity ID"`
+	FirstScanID  string `format:"name:First scan ID;omitempty"`
+}
+
+type resultAgingGroupView struct {
+	Engine   string
+	Owner    string
+	Breached int
+	DueSoon  int `format:"name:Due soon"`
+}
+
+type slaPolicy struct {
+	days        map[string]int
+	warningDays int
+}
+
+func resultAgingSubCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) *cobra.Command {
+	agingCmd := &cobra.Command{
+		Use:   "aging",
+		Short: "List the results of a scan that are out of their SLA or about to breach it",
+		Long: "The aging command compares how long every exploitable result of a scan has been open, " +
+			"since it was first found, with the SLA days of its severity.",
+		Example: heredoc.Doc(
+			`
+			$ cx results aging --scan-id <scan Id> --sla "high=15;medium=60" --codeowners .github/CODEOWNERS
+			$ cx results aging --scan-id <scan Id> --threshold "sast-high=1;sca-high=1"
+		`,
+		),
+		RunE: runGetResultsAgingCommand(resultsWrapper, scanWrapper),
+	}
+	addScanIDFlag(agingCmd, "ID to report on.")
+	_ = agingCmd.MarkPersistentFlagRequired(commonParams.ScanIDFlag)
+	agingCmd.PersistentFlags().String(
+		commonParams.SLAFlag,
+		defaultSLA,
+		"SLA days by severity. Format <severity>=<days>, severities without SLA are not reported",
+	)
+	agingCmd.PersistentFlags().Int(
+		commonParams.SLAWarningDaysFlag,
+		defaultSLAWarningDays,
+		"Report the results breaching their SLA within this number of days",
+	)
+	agingCmd.PersistentFlags().String(commonParams.CodeOwnersFlag, "", commonParams.CodeOwnersFlagUsage)
+	agingCmd.PersistentFlags().String(
+		commonParams.Threshold,
+		"",
+		"SLA breaches threshold. Format <engine>-<severity>=<limit>. Example: \"sast-high=1;sca-high=1\"",
+	)
+	addFormatFlag(agingCmd, printer.FormatTable, printer.FormatJSON, printer.FormatList, printer.FormatCSV, printer.FormatSummaryMarkdown)
+	return agingCmd
+}
+
+func runGetResultsAgingCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		policy, err := getSLAPolicy(cmd)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingAging)
+		}
+		var owners *codeOwners
+		if codeOwnersPath, _ := cmd.Flags().GetString(commonParams.CodeOwnersFlag); codeOwnersPath != "" {
+			owners, err = readCodeOwners(codeOwnersPath)
+			if err != nil {
+				return err
+			}
+		}
+
+		scanID, _ := cmd.Flags().GetString(commonParams.ScanIDFlag)
+		scan, errorModel, err := scanWrapper.GetByID(scanID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGetting)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedGetting, errorModel.Code, errorModel.Message)
+		}
+		results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingAging)
+		}
+
+		views := toResultAgingViews(results, policy, owners, time.Now())
+		err = printByFormat(cmd, views)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingAging)
+		}
+		format, _ := cmd.Flags().GetString(commonParams.FormatFlag)
+		if len(views) > 0 && !printer.IsFormat(format, printer.FormatJSON) && !printer.IsFormat(format, printer.FormatCSV) {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			err = printByFormat(cmd, toResultAgingGroupViews(views))
+			if err != nil {
+				return errors.Wrapf(err, "%s", failedGettingAging)
+			}
+		}
+
+		threshold, _ := cmd.Flags().GetString(commonParams.Threshold)
+		if strings.TrimSpace(threshold) == "" {
+			return nil
+		}
+		return checkThreshold(parseThreshold(threshold), getSLABreachesCountMap(views))
+	}
+}
+
+func getSLAPolicy(cmd *cobra.Command) (*slaPolicy, error) {
+	sla, _ := cmd.Flags().GetString(commonParams.SLAFlag)
+	warningDays, _ := cmd.Flags().GetInt(commonParams.SLAWarningDaysFlag)
+	if warningDays < 0 {
+		return nil, errors.Errorf("--%s can't be negative", commonParams.SLAWarningDaysFlag)
+	}
+	policy := &slaPolicy{days: make(map[string]int), warningDays: warningDays}
+	sla = strings.ReplaceAll(strings.ReplaceAll(sla, " ", ""), ",", ";")
+	for _, severitySLA := range strings.Split(strings.ToLower(sla), ";") {
+		if severitySLA == "" {
+			continue
+		}
+		keyValuePair := strings.Split(severitySLA, "=")
+		if len(keyValuePair) != commonParams.KeyValuePairSize {
+			return nil, errors.Errorf("Invalid --%s value %s", commonParams.SLAFlag, severitySLA)
+		}
+		days, err := strconv.Atoi(keyValuePair[1])
+		if err != nil || days < 0 {
+			return nil, errors.Errorf("Invalid --%s value %s", commonParams.SLAFlag, severitySLA)
+		}
+		policy.days[keyValuePair[0]] = days
+	}
+	if len(policy.days) == 0 {
+		return nil, errors.Errorf("Please provide the SLA days of at least one severity with --%s", commonParams.SLAFlag)
+	}
+	return policy, nil
+}
+
+// toResultAgingViews List the exploitable results out of SLA or about to breach it, grouped by engine and owner
+func toResultAgingViews(results *wrappers.ScanResultsCollection, policy *slaPolicy, owners *codeOwners, now time.Time) []resultAgingView {
+	var views []resultAgingView
+	if results == nil {
+		return views
+	}
+	for _, result := range results.Results {
+		slaDays, ok := policy.days[strings.ToLower(result.Severity)]
+		if !ok || !isExploitable(result.State) {
+			continue
+		}
+		firstFoundAt, ok := getFirstFoundAt(result)
+		if !ok {
+			continue
+		}
+		daysOpen := int(now.Sub(firstFoundAt).Hours() / hoursPerDay)
+		daysLeft := slaDays - daysOpen
+		var status string
+		switch {
+		case daysLeft < 0:
+			status = slaBreached
+		case daysLeft <= policy.warningDays:
+			status = slaDueSoon
+		default:
+			continue
+		}
+		location := getResultLocation(result)
+		name := result.ScanResultData.QueryName
+		if name == "" {
+			name = result.ScanResultData.PackageIdentifier
+		}
+		views = append(views, resultAgingView{
+			Engine:       strings.Replace(result.Type, commonParams.KicsType, commonParams.IacType, 1),
+			Owner:        formatOwners(owners.ownersOf(location)),
+			Severity:     strings.ToLower(result.Severity),
+			Status:       status,
+			DaysOpen:     daysOpen,
+			DaysLeft:     daysLeft,
+			FirstFoundAt: firstFoundAt,
+			Name:         name,
+			Location:     location,
+			SimilarityID: result.SimilarityID,
+			FirstScanID:  result.FirstScanID,
+		})
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].Engine != views[j].Engine {
+			return views[i].Engine < views[j].Engine
+		}
+		if views[i].Owner != views[j].Owner {
+			return views[i].Owner < views[j].Owner
+		}
+		return views[i].DaysLeft < views[j].DaysLeft
+	})
+	return views
+}
+
+func getFirstFoundAt(result *wrappers.ScanResult) (time.Time, bool) {
+	for _, value := range []string{result.FirstFoundAt, result.Created} {
+		if value == "" {
+			continue
+		}
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// toResultAgingGroupViews Count the results of every engine and owner, keeping the order of the views
+func toResultAgingGroupViews(views []resultAgingView) []resultAgingGroupView {
+	var groups []resultAgingGroupView
+	for i := range views {
+		if len(groups) == 0 || groups[len(groups)-1].Engine != views[i].Engine || groups[len(groups)-1].Owner != views[i].Owner {
+			groups = append(groups, resultAgingGroupView{Engine: views[i].Engine, Owner: views[i].Owner})
+		}
+		if views[i].Status == slaBreached {
+			groups[len(groups)-1].Breached++
+		} else {
+			groups[len(groups)-1].DueSoon++
+		}
+	}
+	return groups
+}
+
+// getSLABreachesCountMap Count the results out of SLA by engine and severity, ex: sast-high
+func getSLABreachesCountMap(views []resultAgingView) map[string]int {
+	breaches := make(map[string]int)
+	for i := range views {
+		if views[i].Status == slaBreached {
+			breaches[views[i].Engine+"-"+views[i].Severity]++
+		}
+	}
+	return breaches
+}
@@ -0,0 +1,261 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+)
+
+const (
+	bflFormatDot     = "dot"
+	bflFormatMermaid = "mermaid"
+)
+
+type bflQuery struct {
+	ID   string
+	Name string
+}
+
+// bflQueryModel The best fix locations of a query
+type bflQueryModel struct {
+	query bflQuery
+	model *wrappers.BFLResponseModel
+}
+
+type bflRankView struct {
+	Rank      int
+	QueryName string `format:"name:Query name"`
+	QueryID   string `format:"name:Query ID"`
+	Results   int
+	FileName  string `format:"name:File name"`
+	Line      uint
+	Column    uint
+	Method    string
+	Name      string
+}
+
+// getSastQueries List the distinct SAST queries of the results
+func getSastQueries(results *wrappers.ScanResultsCollection) []bflQuery {
+	var queries []bflQuery
+	if results == nil {
+		return queries
+	}
+	seen := make(map[string]bool)
+	for _, result := range results.Results {
+		if result.Type != commonParams.SastType {
+			continue
+		}
+		queryID := formatQueryID(result.ScanResultData.QueryID)
+		if queryID == "" || seen[queryID] {
+			continue
+		}
+		seen[queryID] = true
+		queries = append(queries, bflQuery{ID: queryID, Name: result.ScanResultData.QueryName})
+	}
+	return queries
+}
+
+// formatQueryID Format a query ID decoded as a JSON number, a float64 or a string
+func formatQueryID(queryID interface{}) string {
+	switch value := queryID.(type) {
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		return value
+	}
+	return ""
+}
+
+// getBflForQueries Fetch the best fix locations of every query with bounded concurrency, skipping the queries without them
+func getBflForQueries(bflWrapper wrappers.BflWrapper, scanID string, queries []bflQuery) ([]bflQueryModel, error) {
+	models := make([]bflQueryModel, len(queries))
+	failures := make([]error, len(queries))
+	semaphore := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	for i := range queries {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			params := map[string]string{
+				commonParams.ScanIDQueryParam:  scanID,
+				commonParams.QueryIDQueryParam: queries[i].ID,
+			}
+			model, errorModel, err := bflWrapper.GetBflByScanIDAndQueryID(params)
+			if err == nil && errorModel != nil {
+				err = errors.Errorf("CODE: %d, %s", errorModel.Code, errorModel.Message)
+			}
+			models[i] = bflQueryModel{query: queries[i], model: model}
+			failures[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	var found []bflQueryModel
+	var lastErr error
+	for i := range models {
+		if failures[i] != nil {
+			log.Printf("Skipping the best fix location of query %s (%s): %v", models[i].query.Name, models[i].query.ID, failures[i])
+			lastErr = failures[i]
+			continue
+		}
+		if models[i].model != nil {
+			found = append(found, models[i])
+		}
+	}
+	if len(found) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return found, nil
+}
+
+// toBflRankViews Rank the best fix locations of all queries by the number of results each one eliminates
+func toBflRankViews(models []bflQueryModel) []bflRankView {
+	views := make([]bflRankView, 0)
+	for _, queryModel := range models {
+		for _, tree := range queryModel.model.Trees {
+			if tree.BFL == nil {
+				continue
+			}
+			views = append(views, bflRankView{
+				QueryName: queryModel.query.Name,
+				QueryID:   queryModel.query.ID,
+				Results:   len(tree.Results),
+				FileName:  tree.BFL.FileName,
+				Line:      tree.BFL.Line,
+				Column:    tree.BFL.Column,
+				Method:    tree.BFL.Method,
+				Name:      tree.BFL.Name,
+			})
+		}
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		return views[i].Results > views[j].Results
+	})
+	for i := range views {
+		views[i].Rank = i + 1
+	}
+	return views
+}
+
+type bflGraphNode struct {
+	id    string
+	label string
+	bfl   bool
+}
+
+type bflGraph struct {
+	title string
+	nodes []bflGraphNode
+	edges [][2]string
+}
+
+// toBflGraphs Build a graph of every tree, the nodes being renamed to identifiers valid in DOT and Mermaid
+func toBflGraphs(models []bflQueryModel) []bflGraph {
+	var graphs []bflGraph
+	for _, queryModel := range models {
+		for _, tree := range queryModel.model.Trees {
+			prefix := fmt.Sprintf("t%d_", len(graphs))
+			graph := bflGraph{title: fmt.Sprintf("%s (%d results)", queryModel.query.Name, len(tree.Results))}
+			ids := make(map[string]string)
+			addNode := func(key string, node *wrappers.ScanResultNode) string {
+				if id, ok := ids[key]; ok {
+					return id
+				}
+				id := fmt.Sprintf("%sn%d", prefix, len(ids))
+				ids[key] = id
+				label := key
+				if node != nil {
+					label = fmt.Sprintf("%s\n%s:%d", node.Name, node.FileName, node.Line)
+				}
+				isBfl := node != nil && tree.BFL != nil && (node == tree.BFL || (tree.BFL.ID != "" && key == tree.BFL.ID))
+				graph.nodes = append(graph.nodes, bflGraphNode{id: id, label: label, bfl: isBfl})
+				return id
+			}
+
+			keys := make([]string, 0, len(tree.Nodes))
+			for key := range tree.Nodes {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				addNode(key, tree.Nodes[key])
+			}
+			if tree.BFL != nil {
+				key := tree.BFL.ID
+				if key == "" {
+					key = "bfl"
+				}
+				addNode(key, tree.BFL)
+			}
+			for _, pair := range tree.NodesAdjacencyPairs {
+				if len(pair) == commonParams.KeyValuePairSize {
+					graph.edges = append(graph.edges, [2]string{addNode(pair[0], tree.Nodes[pair[0]]), addNode(pair[1], tree.Nodes[pair[1]])})
+				}
+			}
+			graphs = append(graphs, graph)
+		}
+	}
+	return graphs
+}
+
+// writeBflDot Write the trees as Graphviz DOT, one cluster per tree
+func writeBflDot(w io.Writer, graphs []bflGraph) error {
+	var builder strings.Builder
+	builder.WriteString("digraph bfl {\n  rankdir=TB;\n  node [shape=box];\n")
+	for i, graph := range graphs {
+		builder.WriteString(fmt.Sprintf("  subgraph cluster_%d {\n    label=%s;\n", i, strconv.Quote(graph.title)))
+		for _, node := range graph.nodes {
+			style := ""
+			if node.bfl {
+				style = ", style=filled, fillcolor=\"#f1605d\""
+			}
+			builder.WriteString(fmt.Sprintf("    %s [label=%s%s];\n", node.id, strconv.Quote(node.label), style))
+		}
+		for _, edge := range graph.edges {
+			builder.WriteString(fmt.Sprintf("    %s -> %s;\n", edge[0], edge[1]))
+		}
+		builder.WriteString("  }\n")
+	}
+	builder.WriteString("}\n")
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// writeBflMermaid Write the trees as a Mermaid flowchart, one subgraph per tree
+func writeBflMermaid(w io.Writer, graphs []bflGraph) error {
+	var builder strings.Builder
+	builder.WriteString("flowchart TD\n")
+	escape := strings.NewReplacer("\"", "#quot;", "\n", "<br/>")
+	for i, graph := range graphs {
+		builder.WriteString(fmt.Sprintf("  subgraph tree%d [\"%s\"]\n", i, escape.Replace(graph.title)))
+		for _, node := range graph.nodes {
+			builder.WriteString(fmt.Sprintf("    %s[\"%s\"]\n", node.id, escape.Replace(node.label)))
+		}
+		for _, edge := range graph.edges {
+			builder.WriteString(fmt.Sprintf("    %s --> %s\n", edge[0], edge[1]))
+		}
+		builder.WriteString("  end\n")
+		for _, node := range graph.nodes {
+			if node.bfl {
+				builder.WriteString(fmt.Sprintf("  style %s fill:#f1605d\n", node.id))
+			}
+		}
+	}
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
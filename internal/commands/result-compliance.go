@@ -0,0 +1,291 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	failedGettingCompliance = "Failed getting the compliance report"
+	compliancePassed        = "Passed"
+	complianceFailed        = "Failed"
+	allCategories           = "all"
+)
+
+type complianceCategoryView struct {
+	Category string
+	Name     string
+	High     int
+	Medium   int
+	Low      int
+	Info     int
+	Total    int
+	Status   string
+}
+
+type complianceReport struct {
+	Framework  string
+	ScanID     string
+	Categories []complianceCategoryView
+}
+
+func resultComplianceSubCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) *cobra.Command {
+	frameworks := make([]string, 0, len(complianceFrameworks))
+	for framework := range complianceFrameworks {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Strings(frameworks)
+
+	complianceCmd := &cobra.Command{
+		Use:   "compliance",
+		Short: "Group the results of a scan by the categories of a compliance framework",
+		Long: "The compliance command counts the exploitable results of a scan in every category of a compliance framework. " +
+			"The results are categorized by the compliances they list, or by their CWE when they list none. " +
+			"A category fails when it has results.",
+		Example: heredoc.Doc(
+			`
+			$ cx results compliance --scan-id <scan Id> --framework owasp-top-10-2021 --format markdown
+			$ cx results compliance --scan-id <scan Id> --framework cwe-top-25 --fail-on-category CWE-89,CWE-79
+		`,
+		),
+		RunE: runGetResultsComplianceCommand(resultsWrapper, scanWrapper),
+	}
+	addScanIDFlag(complianceCmd, "ID to report on.")
+	_ = complianceCmd.MarkPersistentFlagRequired(commonParams.ScanIDFlag)
+	complianceCmd.PersistentFlags().String(
+		commonParams.FrameworkFlag,
+		owaspTop10Framework,
+		fmt.Sprintf("Compliance framework, one of: %s", strings.Join(frameworks, ", ")),
+	)
+	complianceCmd.PersistentFlags().StringSlice(
+		commonParams.FailOnCategoryFlag,
+		[]string{},
+		fmt.Sprintf("Fail when one of these categories has results, or any category with '%s', ex: A01,A03", allCategories),
+	)
+	addFormatFlag(complianceCmd, printer.FormatTable, printer.FormatJSON, printer.FormatSummaryMarkdown, printer.FormatHTML)
+	return complianceCmd
+}
+
+func runGetResultsComplianceCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		frameworkName, _ := cmd.Flags().GetString(commonParams.FrameworkFlag)
+		framework, ok := complianceFrameworks[strings.ToLower(frameworkName)]
+		if !ok {
+			return errors.Errorf("%s: Unknown --%s %s", failedGettingCompliance, commonParams.FrameworkFlag, frameworkName)
+		}
+		failOnCategories, _ := cmd.Flags().GetStringSlice(commonParams.FailOnCategoryFlag)
+		if err := validateComplianceCategories(framework, failOnCategories); err != nil {
+			return errors.Wrapf(err, "%s", failedGettingCompliance)
+		}
+
+		scanID, _ := cmd.Flags().GetString(commonParams.ScanIDFlag)
+		scan, errorModel, err := scanWrapper.GetByID(scanID)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGetting)
+		}
+		if errorModel != nil {
+			return errors.Errorf(ErrorCodeFormat, failedGetting, errorModel.Code, errorModel.Message)
+		}
+		results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingCompliance)
+		}
+
+		views := toComplianceCategoryViews(framework, results)
+		format, _ := cmd.Flags().GetString(commonParams.FormatFlag)
+		if printer.IsFormat(format, printer.FormatHTML) {
+			tmpl, tmplErr := template.New("complianceTemplate").Parse(wrappers.ResultsComplianceTemplate)
+			if tmplErr != nil {
+				return errors.Wrapf(tmplErr, "%s", failedGettingCompliance)
+			}
+			report := &complianceReport{Framework: framework.name, ScanID: scanID, Categories: views}
+			err = tmpl.ExecuteTemplate(cmd.OutOrStdout(), "ComplianceTemplate", report)
+		} else {
+			err = printByFormat(cmd, views)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingCompliance)
+		}
+		return checkComplianceCategories(framework, views, failOnCategories)
+	}
+}
+
+// toComplianceCategoryViews Count the exploitable results in every category of the framework
+func toComplianceCategoryViews(framework *complianceFramework, results *wrappers.ScanResultsCollection) []complianceCategoryView {
+	views := make([]complianceCategoryView, len(framework.categories))
+	categoriesByCwe := make(map[int]int)
+	categoriesByID := make(map[string]int)
+	for i, category := range framework.categories {
+		views[i] = complianceCategoryView{Category: category.ID, Name: category.Name}
+		categoriesByID[category.ID] = i
+		for _, cwe := range category.cwes {
+			if _, ok := categoriesByCwe[cwe]; !ok {
+				categoriesByCwe[cwe] = i
+			}
+		}
+	}
+	var uncategorized *complianceCategoryView
+	if results != nil {
+		for _, result := range results.Results {
+			if !isExploitable(result.State) {
+				continue
+			}
+			index, ok := complianceCategoryIndex(framework, result, categoriesByID, categoriesByCwe)
+			if !ok {
+				continue
+			}
+			var view *complianceCategoryView
+			if index >= 0 {
+				view = &views[index]
+			} else {
+				if uncategorized == nil {
+					uncategorized = &complianceCategoryView{Category: uncategorizedID, Name: "Results of the framework without a known category"}
+				}
+				view = uncategorized
+			}
+			countComplianceResult(view, result)
+		}
+	}
+	if uncategorized != nil {
+		views = append(views, *uncategorized)
+	}
+	for i := range views {
+		views[i].Status = compliancePassed
+		if views[i].Total > 0 {
+			views[i].Status = complianceFailed
+		}
+	}
+	return views
+}
+
+// complianceCategoryIndex The category of the result, -1 when uncategorized, false when the result is out of the framework.
+// The compliances of the result come first, the CWE tables only categorizing the results without a category in them.
+func complianceCategoryIndex(
+	framework *complianceFramework,
+	result *wrappers.ScanResult,
+	categoriesByID map[string]int,
+	categoriesByCwe map[int]int,
+) (int, bool) {
+	compliances := frameworkCompliances(result, framework.compliance)
+	if len(result.VulnerabilityDetails.Compliances) > 0 && len(compliances) == 0 {
+		return 0, false
+	}
+	for _, compliance := range compliances {
+		if index, ok := namedComplianceCategory(compliance, framework.compliance, categoriesByID); ok {
+			return index, true
+		}
+	}
+	if cwe, ok := parseCweID(result.VulnerabilityDetails.CweID); ok {
+		if index, found := categoriesByCwe[cwe]; found {
+			return index, true
+		}
+	}
+	if index, ok := categoriesByID[framework.scaCategory]; ok && result.Type == commonParams.ScaType {
+		return index, true
+	}
+	return -1, len(compliances) > 0
+}
+
+// frameworkCompliances The compliances of the result naming the framework, lower cased
+func frameworkCompliances(result *wrappers.ScanResult, compliance string) []string {
+	var compliances []string
+	for _, resultCompliance := range result.VulnerabilityDetails.Compliances {
+		if resultCompliance != nil && strings.Contains(strings.ToLower(*resultCompliance), compliance) {
+			compliances = append(compliances, strings.ToLower(*resultCompliance))
+		}
+	}
+	return compliances
+}
+
+// namedComplianceCategory The category named after the framework in a compliance, such as A03 in "OWASP Top 10 2021 A03: Injection"
+func namedComplianceCategory(compliance, frameworkCompliance string, categoriesByID map[string]int) (int, bool) {
+	rest := compliance[strings.Index(compliance, frameworkCompliance)+len(frameworkCompliance):]
+	words := strings.FieldsFunc(rest, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '-'
+	})
+	for _, word := range words {
+		if index, ok := categoriesByID[strings.ToUpper(strings.Trim(word, ".-"))]; ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func countComplianceResult(view *complianceCategoryView, result *wrappers.ScanResult) {
+	view.Total++
+	switch strings.ToLower(result.Severity) {
+	case highLabel:
+		view.High++
+	case mediumLabel:
+		view.Medium++
+	case lowLabel:
+		view.Low++
+	case infoLabel:
+		view.Info++
+	}
+}
+
+// parseCweID Read a CWE ID given as a number, or as a string such as 79 or CWE-79
+func parseCweID(cweID interface{}) (int, bool) {
+	switch value := cweID.(type) {
+	case json.Number:
+		cwe, err := value.Int64()
+		return int(cwe), err == nil
+	case float64:
+		return int(value), true
+	case int:
+		return value, true
+	case string:
+		cwe, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "CWE-"))
+		return cwe, err == nil
+	}
+	return 0, false
+}
+
+func validateComplianceCategories(framework *complianceFramework, categories []string) error {
+	for _, category := range categories {
+		if strings.EqualFold(category, allCategories) || strings.EqualFold(category, uncategorizedID) {
+			continue
+		}
+		known := false
+		for i := range framework.categories {
+			known = known || strings.EqualFold(framework.categories[i].ID, category)
+		}
+		if !known {
+			return errors.Errorf("Unknown %s category %s", framework.name, category)
+		}
+	}
+	return nil
+}
+
+// checkComplianceCategories Fail when one of the given categories, or any category with all, has results
+func checkComplianceCategories(framework *complianceFramework, views []complianceCategoryView, categories []string) error {
+	var failed []string
+	for i := range views {
+		if views[i].Status != complianceFailed {
+			continue
+		}
+		for _, category := range categories {
+			if strings.EqualFold(category, allCategories) || strings.EqualFold(category, views[i].Category) {
+				failed = append(failed, fmt.Sprintf("%s: %d", views[i].Category, views[i].Total))
+				break
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("Compliance check finished with status Failed for %s : %s", framework.name, strings.Join(failed, " | "))
+	}
+	return nil
+}
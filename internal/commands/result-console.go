@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/gookit/color"
+)
+
+const consoleDetailsIndent = "    "
+
+// consolePainter Colorize the console output only when it is a terminal supporting colors
+type consolePainter struct {
+	enabled bool
+}
+
+func newConsolePainter(out io.Writer) *consolePainter {
+	file, ok := out.(*os.File)
+	if !ok {
+		return &consolePainter{}
+	}
+	info, err := file.Stat()
+	return &consolePainter{enabled: err == nil && info.Mode()&os.ModeCharDevice != 0 && color.SupportColor()}
+}
+
+func (p *consolePainter) paint(style color.Color, text string) string {
+	if !p.enabled || text == "" {
+		return text
+	}
+	return style.Sprint(text)
+}
+
+func (p *consolePainter) severity(severity string) string {
+	label := strings.ToUpper(severity)
+	switch strings.ToLower(severity) {
+	case highLabel:
+		return p.paint(color.FgRed, label)
+	case mediumLabel:
+		return p.paint(color.FgYellow, label)
+	case lowLabel:
+		return p.paint(color.FgCyan, label)
+	}
+	return p.paint(color.FgGray, label)
+}
+
+// writeConsoleDetails Write every result with its attack vector, expected value or dependency path
+func writeConsoleDetails(out io.Writer, results *wrappers.ScanResultsCollection) error {
+	painter := newConsolePainter(out)
+	var builder strings.Builder
+	number := 0
+	if results != nil {
+		for _, result := range results.Results {
+			var details []string
+			switch result.Type {
+			case commonParams.SastType:
+				details = sastConsoleDetails(painter, result)
+			case commonParams.KicsType:
+				details = kicsConsoleDetails(painter, result)
+			case commonParams.ScaType:
+				details = scaConsoleDetails(painter, result)
+			default:
+				continue
+			}
+			number++
+			builder.WriteString(fmt.Sprintf(
+				"[%d] %s %s %s (%s)\n",
+				number,
+				painter.severity(result.Severity),
+				painter.paint(color.FgMagenta, strings.ToUpper(result.Type)),
+				painter.paint(color.Bold, consoleResultTitle(result)),
+				result.State,
+			))
+			for _, detail := range details {
+				builder.WriteString(consoleDetailsIndent + detail + "\n")
+			}
+			builder.WriteString("\n")
+		}
+	}
+	if number == 0 {
+		builder.WriteString("No results found\n")
+	}
+	_, err := io.WriteString(out, builder.String())
+	return err
+}
+
+func consoleResultTitle(result *wrappers.ScanResult) string {
+	if result.Type == commonParams.ScaType {
+		if result.VulnerabilityDetails.CveName != "" {
+			return result.VulnerabilityDetails.CveName
+		}
+		return result.ID
+	}
+	return result.ScanResultData.QueryName
+}
+
+// sastConsoleDetails Number the nodes of the result from the source to the sink
+func sastConsoleDetails(painter *consolePainter, result *wrappers.ScanResult) []string {
+	nodes := result.ScanResultData.Nodes
+	details := []string{fmt.Sprintf("Language: %s, Attack vector: %d nodes", result.ScanResultData.LanguageName, len(nodes))}
+	for i, node := range nodes {
+		role := ""
+		if i == 0 {
+			role = painter.paint(color.FgGreen, " (source)")
+		} else if i == len(nodes)-1 {
+			role = painter.paint(color.FgRed, " (sink)")
+		}
+		details = append(details, fmt.Sprintf(
+			"%3d. %s in %s %s%s",
+			i+1,
+			painter.paint(color.Bold, node.Name),
+			node.Method,
+			painter.paint(color.FgBlue, fmt.Sprintf("%s:%d:%d", node.FileName, node.Line, node.Column)),
+			role,
+		))
+	}
+	return details
+}
+
+func kicsConsoleDetails(painter *consolePainter, result *wrappers.ScanResult) []string {
+	data := result.ScanResultData
+	return []string{
+		fmt.Sprintf("Location: %s", painter.paint(color.FgBlue, fmt.Sprintf("%s:%d", data.Filename, data.Line))),
+		fmt.Sprintf("Platform: %s, Issue type: %s", data.Platform, data.IssueType),
+		fmt.Sprintf("Expected: %s", painter.paint(color.FgGreen, data.ExpectedValue)),
+		fmt.Sprintf("Actual:   %s", painter.paint(color.FgRed, data.Value)),
+	}
+}
+
+func scaConsoleDetails(painter *consolePainter, result *wrappers.ScanResult) []string {
+	data := result.ScanResultData
+	details := []string{fmt.Sprintf("Package: %s", data.PackageIdentifier)}
+	if data.ScaPackageCollection != nil {
+		for _, path := range data.ScaPackageCollection.DependencyPathArray {
+			dependencies := make([]string, len(path))
+			for i, dependency := range path {
+				dependencies[i] = dependency.Name
+				if dependency.Version != "" {
+					dependencies[i] += "@" + dependency.Version
+				}
+			}
+			details = append(details, fmt.Sprintf("Dependency path: %s", strings.Join(dependencies, " > ")))
+		}
+	}
+	recommended := notAvailableString
+	if data.RecommendedVersion != nil && fmt.Sprint(data.RecommendedVersion) != "" {
+		recommended = fmt.Sprint(data.RecommendedVersion)
+	}
+	details = append(details, fmt.Sprintf("Recommended version: %s", painter.paint(color.FgGreen, recommended)))
+	return details
+}
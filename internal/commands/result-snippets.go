@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"strings"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+)
+
+const (
+	defaultContextLines   = 2
+	driftedSourcesWarning = "Warning: %d result location(s) don't match the local sources in %s, " +
+		"which may differ from the scanned revision"
+)
+
+// setResultsSnippets Add the local lines around every SAST node and KICS line, returning the number of drifted locations
+func setResultsSnippets(results *wrappers.ScanResultsCollection, sourceRoot string, contextLines uint) int {
+	if results == nil {
+		return 0
+	}
+	drifted := 0
+	for _, result := range results.Results {
+		for _, node := range result.ScanResultData.Nodes {
+			node.Snippet = readSnippet(sourceRoot, node.FileName, node.Line, contextLines, node.Name)
+			if node.Snippet != nil && node.Snippet.Drifted {
+				drifted++
+			}
+		}
+		if result.Type == commonParams.KicsType {
+			data := &result.ScanResultData
+			data.Snippet = readSnippet(sourceRoot, data.Filename, data.Line, contextLines, "")
+			if data.Snippet != nil && data.Snippet.Drifted {
+				drifted++
+			}
+		}
+	}
+	return drifted
+}
+
+// readSnippet Read the lines around a location, which drifted when it is missing locally or its line lacks the scanned token
+func readSnippet(sourceRoot, fileName string, line, contextLines uint, token string) *wrappers.ScanResultSnippet {
+	if fileName == "" || line == 0 {
+		return nil
+	}
+	snippet := &wrappers.ScanResultSnippet{Lines: readSourceLines(sourceRoot, fileName, line, contextLines)}
+	snippet.Drifted = len(snippet.Lines) == 0
+	for _, sourceLine := range snippet.Lines {
+		if sourceLine.Number == line && token != "" && !strings.Contains(sourceLine.Text, token) {
+			snippet.Drifted = true
+		}
+	}
+	return snippet
+}
+
+// toSnippetsSummary List the snippets of the exploitable results for the summary reports
+func toSnippetsSummary(results *wrappers.ScanResultsCollection) []wrappers.SnippetSummary {
+	var snippets []wrappers.SnippetSummary
+	if results == nil {
+		return snippets
+	}
+	for _, result := range results.Results {
+		if !isExploitable(result.State) {
+			continue
+		}
+		newSummary := func(fileName string, line uint, snippet *wrappers.ScanResultSnippet) wrappers.SnippetSummary {
+			return wrappers.SnippetSummary{
+				Type:     result.Type,
+				Severity: result.Severity,
+				Name:     strings.ReplaceAll(result.ScanResultData.QueryName, "_", " "),
+				FileName: fileName,
+				Line:     line,
+				Lines:    snippet.Lines,
+				Drifted:  snippet.Drifted,
+			}
+		}
+		for _, node := range result.ScanResultData.Nodes {
+			if node.Snippet != nil {
+				snippets = append(snippets, newSummary(node.FileName, node.Line, node.Snippet))
+			}
+		}
+		if result.ScanResultData.Snippet != nil {
+			snippets = append(snippets, newSummary(result.ScanResultData.Filename, result.ScanResultData.Line, result.ScanResultData.Snippet))
+		}
+	}
+	return snippets
+}
+
+// toSarifContextRegion Convert a snippet to the SARIF region around a location
+func toSarifContextRegion(snippet *wrappers.ScanResultSnippet) *wrappers.SarifContextRegion {
+	if snippet == nil || len(snippet.Lines) == 0 {
+		return nil
+	}
+	texts := make([]string, len(snippet.Lines))
+	for i, sourceLine := range snippet.Lines {
+		texts[i] = sourceLine.Text
+	}
+	return &wrappers.SarifContextRegion{
+		StartLine: snippet.Lines[0].Number,
+		EndLine:   snippet.Lines[len(snippet.Lines)-1].Number,
+		Snippet:   &wrappers.SarifSnippet{Text: strings.Join(texts, "\n")},
+	}
+}
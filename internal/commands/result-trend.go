@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	failedGettingTrend = "Failed getting the results trend"
+	defaultTrendSince  = "90d"
+	trendFormatHTML    = "html"
+	trendChartWidth    = 800
+	trendChartHeight   = 240
+	trendChartPadding  = 10
+	newColor           = "#f1605d"
+	fixedColor         = "#0fcdc2"
+)
+
+type resultsTrendView struct {
+	ScanID      string    `format:"name:Scan ID"`
+	CreatedAt   time.Time `format:"name:Created at;time:01-02-06 15:04:05"`
+	Branch      string
+	Total       int
+	High        int
+	Medium      int
+	Low         int
+	Info        int
+	Sast        int
+	IacSecurity int `format:"name:IaC Security"`
+	Sca         int `format:"name:SCA"`
+	New         int
+	Fixed       int
+	Counts      map[string]int `format:"-"`
+}
+
+type resultsTrendReport struct {
+	ProjectID string
+	Branch    string
+	Since     string
+	Scans     []resultsTrendView
+	Series    []wrappers.TrendChartSeries
+	Bars      []wrappers.TrendChartBar
+	Width     int
+	Height    int
+}
+
+func resultTrendSubCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) *cobra.Command {
+	trendCmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Show how the results of a project evolve across its scans",
+		Long: "The trend command counts the exploitable results of every completed scan of a project, " +
+			"by engine and severity, and the results that are new or fixed since the previous scan.",
+		Example: heredoc.Doc(
+			`
+			$ cx results trend --project-id <project Id> --branch main --since 90d --format markdown
+			$ cx results trend --project-id <project Id> --format html > trend.html
+		`,
+		),
+		RunE: runGetResultsTrendCommand(resultsWrapper, scanWrapper),
+	}
+	addProjectIDFlag(trendCmd, "Project ID of the scans")
+	_ = trendCmd.MarkPersistentFlagRequired(commonParams.ProjectIDFlag)
+	trendCmd.PersistentFlags().String(commonParams.BranchFlag, "", "Only the scans of this branch")
+	trendCmd.PersistentFlags().String(commonParams.SinceFlag, defaultTrendSince, "Only the scans created in this period, ex: 72h, 90d")
+	addFormatFlag(trendCmd, printer.FormatTable, printer.FormatJSON, printer.FormatCSV, printer.FormatSummaryMarkdown, trendFormatHTML)
+	return trendCmd
+}
+
+func runGetResultsTrendCommand(resultsWrapper wrappers.ResultsWrapper, scanWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		projectID, _ := cmd.Flags().GetString(commonParams.ProjectIDFlag)
+		branch, _ := cmd.Flags().GetString(commonParams.BranchFlag)
+		sinceValue, _ := cmd.Flags().GetString(commonParams.SinceFlag)
+		age, err := parseAge(sinceValue)
+		if err != nil {
+			return errors.Errorf("%s: Invalid --%s value %s", failedGettingTrend, commonParams.SinceFlag, sinceValue)
+		}
+		since := time.Now().Add(-age)
+
+		scans, err := getTrendScans(scanWrapper, projectID, branch, since)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedGettingTrend)
+		}
+		scanResults := make([]*wrappers.ScanResultsCollection, len(scans))
+		for i := range scans {
+			scanResults[i], err = ReadResults(resultsWrapper, &scans[i], make(map[string]string))
+			if err != nil {
+				return errors.Wrapf(err, "%s", failedGettingTrend)
+			}
+		}
+		views := toResultsTrendViews(scans, scanResults)
+
+		format, _ := cmd.Flags().GetString(commonParams.FormatFlag)
+		if strings.EqualFold(format, trendFormatHTML) {
+			report := newResultsTrendReport(projectID, branch, since, views)
+			tmpl, tmplErr := template.New("trendTemplate").Parse(wrappers.ResultsTrendTemplate)
+			if tmplErr != nil {
+				return errors.Wrapf(tmplErr, "%s", failedGettingTrend)
+			}
+			return tmpl.ExecuteTemplate(cmd.OutOrStdout(), "TrendTemplate", report)
+		}
+		return printByFormat(cmd, views)
+	}
+}
+
+// getTrendScans Read the completed scans created since the given time, oldest first
+func getTrendScans(scanWrapper wrappers.ScansWrapper, projectID, branch string, since time.Time) ([]wrappers.ScanResponseModel, error) {
+	params := map[string]string{
+		commonParams.ProjectIDQueryParam: projectID,
+		commonParams.StatusesQueryParam:  wrappers.ScanCompleted,
+		commonParams.SortQueryParam:      "-created_at",
+	}
+	if branch != "" {
+		params[commonParams.BranchQueryParam] = branch
+	}
+	var scans []wrappers.ScanResponseModel
+	err := readAllPages(params, bulkPageSize, 0, fetchScanModelsPage(scanWrapper), func(items []interface{}, _ int) (bool, error) {
+		for _, item := range items {
+			scan := item.(wrappers.ScanResponseModel)
+			if scan.CreatedAt.Before(since) {
+				return true, nil
+			}
+			// The branch filter of the API also matches the branches containing the name
+			if branch != "" && scan.Branch != branch {
+				continue
+			}
+			scans = append(scans, scan)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].CreatedAt.Before(scans[j].CreatedAt)
+	})
+	return scans, nil
+}
+
+// toResultsTrendViews Count the results of every scan, comparing each one with the previous scan
+func toResultsTrendViews(scans []wrappers.ScanResponseModel, scanResults []*wrappers.ScanResultsCollection) []resultsTrendView {
+	views := make([]resultsTrendView, len(scans))
+	var previous map[string]bool
+	for i := range scans {
+		counts := getSummaryCountMap(scanResults[i])
+		view := resultsTrendView{
+			ScanID:    scans[i].ID,
+			CreatedAt: scans[i].CreatedAt,
+			Branch:    scans[i].Branch,
+			Counts:    counts,
+		}
+		for key, count := range counts {
+			view.Total += count
+			switch {
+			case strings.HasSuffix(key, "-"+highLabel):
+				view.High += count
+			case strings.HasSuffix(key, "-"+mediumLabel):
+				view.Medium += count
+			case strings.HasSuffix(key, "-"+lowLabel):
+				view.Low += count
+			case strings.HasSuffix(key, "-"+infoLabel):
+				view.Info += count
+			}
+			switch {
+			case strings.HasPrefix(key, commonParams.SastType+"-"):
+				view.Sast += count
+			case strings.HasPrefix(key, commonParams.IacType+"-"):
+				view.IacSecurity += count
+			case strings.HasPrefix(key, commonParams.ScaType+"-"):
+				view.Sca += count
+			}
+		}
+
+		current := getExploitableResultKeys(scanResults[i])
+		if previous != nil {
+			for key := range current {
+				if !previous[key] {
+					view.New++
+				}
+			}
+			for key := range previous {
+				if !current[key] {
+					view.Fixed++
+				}
+			}
+		}
+		previous = current
+		views[i] = view
+	}
+	return views
+}
+
+// getExploitableResultKeys Identify the exploitable results across scans by engine and similarity ID
+func getExploitableResultKeys(results *wrappers.ScanResultsCollection) map[string]bool {
+	keys := make(map[string]bool)
+	if results == nil {
+		return keys
+	}
+	for _, result := range results.Results {
+		if isExploitable(result.State) {
+			keys[result.Type+":"+result.SimilarityID] = true
+		}
+	}
+	return keys
+}
+
+// newResultsTrendReport Compute the SVG geometry of the trend charts
+func newResultsTrendReport(projectID, branch string, since time.Time, views []resultsTrendView) *resultsTrendReport {
+	report := &resultsTrendReport{
+		ProjectID: projectID,
+		Branch:    branch,
+		Since:     since.Format(commonParams.DateLayout),
+		Scans:     views,
+		Width:     trendChartWidth,
+		Height:    trendChartHeight,
+	}
+	if len(views) == 0 {
+		return report
+	}
+
+	maxCount, maxChange := 1, 1
+	for i := range views {
+		if views[i].Total > maxCount {
+			maxCount = views[i].Total
+		}
+		if views[i].New > maxChange {
+			maxChange = views[i].New
+		}
+		if views[i].Fixed > maxChange {
+			maxChange = views[i].Fixed
+		}
+	}
+	step := float64(trendChartWidth-2*trendChartPadding) / float64(len(views))
+	x := func(i int) float64 {
+		return trendChartPadding + step*float64(i) + step/2
+	}
+	y := func(value, maxValue int) float64 {
+		return trendChartHeight - float64(value)*float64(trendChartHeight-trendChartPadding)/float64(maxValue)
+	}
+
+	series := []struct {
+		name  string
+		color string
+		value func(view *resultsTrendView) int
+	}{
+		{"Total", "#565360", func(view *resultsTrendView) int { return view.Total }},
+		{"High", "#f1605d", func(view *resultsTrendView) int { return view.High }},
+		{"Medium", "#f9ae4d", func(view *resultsTrendView) int { return view.Medium }},
+		{"Low", "#bdbdbd", func(view *resultsTrendView) int { return view.Low }},
+	}
+	for _, s := range series {
+		points := make([]string, len(views))
+		for i := range views {
+			points[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(s.value(&views[i]), maxCount))
+		}
+		report.Series = append(report.Series, wrappers.TrendChartSeries{Name: s.name, Color: s.color, Points: strings.Join(points, " ")})
+	}
+
+	barWidth := step / 3
+	for i := range views {
+		newY := y(views[i].New, maxChange)
+		fixedY := y(views[i].Fixed, maxChange)
+		report.Bars = append(report.Bars,
+			wrappers.TrendChartBar{
+				X: x(i) - barWidth, Y: newY, Width: barWidth, Height: trendChartHeight - newY, Color: newColor,
+				Title: fmt.Sprintf("%s: %d new", views[i].ScanID, views[i].New),
+			},
+			wrappers.TrendChartBar{
+				X: x(i), Y: fixedY, Width: barWidth, Height: trendChartHeight - fixedY, Color: fixedColor,
+				Title: fmt.Sprintf("%s: %d fixed", views[i].ScanID, views[i].Fixed),
+			},
+		)
+	}
+	return report
+}
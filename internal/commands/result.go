@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -15,6 +16,7 @@ import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/checkmarx/ast-cli/internal/commands/util"
 	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	"github.com/checkmarx/ast-cli/internal/logger"
 
 	commonParams "github.com/checkmarx/ast-cli/internal/params"
 
@@ -127,9 +129,12 @@ func NewResultsCommand(
 	}
 	showResultCmd := resultShowSubCommand(resultsWrapper, scanWrapper, resultsPdfReportsWrapper, risksOverviewWrapper)
 	codeBashingCmd := resultCodeBashing(codeBashingWrapper)
-	bflResultCmd := resultBflSubCommand(bflWrapper)
+	bflResultCmd := resultBflSubCommand(bflWrapper, resultsWrapper, scanWrapper)
+	trendCmd := resultTrendSubCommand(resultsWrapper, scanWrapper)
+	agingCmd := resultAgingSubCommand(resultsWrapper, scanWrapper)
+	complianceCmd := resultComplianceSubCommand(resultsWrapper, scanWrapper)
 	resultCmd.AddCommand(
-		showResultCmd, bflResultCmd, codeBashingCmd,
+		showResultCmd, bflResultCmd, codeBashingCmd, trendCmd, agingCmd, complianceCmd,
 	)
 	return resultCmd
 }
@@ -157,42 +162,65 @@ func resultShowSubCommand(
 		printer.FormatJSON,
 		printer.FormatSummary,
 		printer.FormatSummaryConsole,
+		printer.FormatConsoleDetailed,
 		printer.FormatSarif,
 		printer.FormatSummaryJSON,
 		printer.FormatPDF,
 		printer.FormatSummaryMarkdown,
+		printer.FormatCSV,
 	)
 	resultShowCmd.PersistentFlags().String(commonParams.ReportFormatPdfToEmailFlag, "", pdfToEmailFlagDescription)
 	resultShowCmd.PersistentFlags().String(commonParams.ReportFormatPdfOptionsFlag, defaultPdfOptionsDataSections, pdfOptionsFlagDescription)
 	resultShowCmd.PersistentFlags().String(commonParams.TargetFlag, "cx_result", "Output file")
 	resultShowCmd.PersistentFlags().String(commonParams.TargetPathFlag, ".", "Output Path")
 	resultShowCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterResultsListFlagUsage)
+	resultShowCmd.PersistentFlags().String(commonParams.CodeOwnersFlag, "", commonParams.CodeOwnersFlagUsage)
+	resultShowCmd.PersistentFlags().String(
+		commonParams.SourceRootFlag,
+		"",
+		"Local checkout of the scanned sources, used to add code snippets to the results",
+	)
+	resultShowCmd.PersistentFlags().Uint(
+		commonParams.ContextLinesFlag,
+		defaultContextLines,
+		"Number of lines shown before and after each location, use with --"+commonParams.SourceRootFlag,
+	)
 	return resultShowCmd
 }
 
-func resultBflSubCommand(bflWrapper wrappers.BflWrapper) *cobra.Command {
+func resultBflSubCommand(
+	bflWrapper wrappers.BflWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scanWrapper wrappers.ScansWrapper,
+) *cobra.Command {
 	resultBflCmd := &cobra.Command{
 		Use:   "bfl",
 		Short: "Show best fix location for a query id within the scan result.",
-		Long:  "The bfl command enables the ability to show best fix location for a querid within the scan result.",
+		Long: "The bfl command enables the ability to show best fix location for a querid within the scan result. " +
+			"Without a query id, the best fix locations of every SAST query of the scan are ranked by the number of results they fix.",
 		Example: heredoc.Doc(
 			`
 			$ cx results bfl --scan-id <scan Id> --query-id <query Id>
+			$ cx results bfl --scan-id <scan Id> --format table
+			$ cx results bfl --scan-id <scan Id> --format dot > bfl.dot
 		`,
 		),
-		RunE: runGetBestFixLocationCommand(bflWrapper),
+		RunE: runGetBestFixLocationCommand(bflWrapper, resultsWrapper, scanWrapper),
 	}
 	addScanIDFlag(resultBflCmd, "ID to report on.")
-	addQueryIDFlag(resultBflCmd, "Query Id from the result.")
-	addFormatFlag(resultBflCmd, printer.FormatList, printer.FormatJSON)
+	addQueryIDFlag(resultBflCmd, "Query Id from the result. Without it, every SAST query of the scan is reported")
+	addFormatFlag(resultBflCmd, printer.FormatList, printer.FormatJSON, printer.FormatTable, bflFormatDot, bflFormatMermaid)
 
 	markFlagAsRequired(resultBflCmd, commonParams.ScanIDFlag)
-	markFlagAsRequired(resultBflCmd, commonParams.QueryIDFlag)
 
 	return resultBflCmd
 }
 
-func runGetBestFixLocationCommand(bflWrapper wrappers.BflWrapper) func(cmd *cobra.Command, args []string) error {
+func runGetBestFixLocationCommand(
+	bflWrapper wrappers.BflWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scanWrapper wrappers.ScansWrapper,
+) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		var bflResponseModel *wrappers.BFLResponseModel
 		var errorModel *wrappers.WebError
@@ -209,6 +237,9 @@ func runGetBestFixLocationCommand(bflWrapper wrappers.BflWrapper) func(cmd *cobr
 		if len(queryIds) > 1 {
 			return errors.Errorf("%s", "Multiple query-ids are not allowed.")
 		}
+		if queryID == "" {
+			return runGetScanBestFixLocations(cmd, bflWrapper, resultsWrapper, scanWrapper, scanID)
+		}
 
 		params := make(map[string]string)
 		params[commonParams.ScanIDQueryParam] = scanID
@@ -224,6 +255,9 @@ func runGetBestFixLocationCommand(bflWrapper wrappers.BflWrapper) func(cmd *cobr
 		if errorModel != nil {
 			return errors.Errorf("%s: CODE: %d, %s", failedGettingBfl, errorModel.Code, errorModel.Message)
 		} else if bflResponseModel != nil {
+			if isBflGraphFormat(cmd) {
+				return printBflGraph(cmd, []bflQueryModel{{query: bflQuery{ID: queryID, Name: queryID}, model: bflResponseModel}})
+			}
 			err = printByFormat(cmd, toBflView(*bflResponseModel))
 			if err != nil {
 				return err
@@ -234,6 +268,48 @@ func runGetBestFixLocationCommand(bflWrapper wrappers.BflWrapper) func(cmd *cobr
 	}
 }
 
+// runGetScanBestFixLocations Report the best fix locations of every SAST query of the scan
+func runGetScanBestFixLocations(
+	cmd *cobra.Command,
+	bflWrapper wrappers.BflWrapper,
+	resultsWrapper wrappers.ResultsWrapper,
+	scanWrapper wrappers.ScansWrapper,
+	scanID string,
+) error {
+	scan, errorModel, err := scanWrapper.GetByID(scanID)
+	if err != nil {
+		return errors.Wrapf(err, "%s", failedGetting)
+	}
+	if errorModel != nil {
+		return errors.Errorf(ErrorCodeFormat, failedGetting, errorModel.Code, errorModel.Message)
+	}
+	results, err := ReadResults(resultsWrapper, scan, make(map[string]string))
+	if err != nil {
+		return errors.Wrapf(err, "%s", failedGettingBfl)
+	}
+	models, err := getBflForQueries(bflWrapper, scanID, getSastQueries(results))
+	if err != nil {
+		return errors.Wrapf(err, "%s", failedGettingBfl)
+	}
+	if isBflGraphFormat(cmd) {
+		return printBflGraph(cmd, models)
+	}
+	return printByFormat(cmd, toBflRankViews(models))
+}
+
+func isBflGraphFormat(cmd *cobra.Command) bool {
+	format, _ := cmd.Flags().GetString(commonParams.FormatFlag)
+	return printer.IsFormat(format, bflFormatDot) || printer.IsFormat(format, bflFormatMermaid)
+}
+
+func printBflGraph(cmd *cobra.Command, models []bflQueryModel) error {
+	format, _ := cmd.Flags().GetString(commonParams.FormatFlag)
+	if printer.IsFormat(format, bflFormatDot) {
+		return writeBflDot(cmd.OutOrStdout(), toBflGraphs(models))
+	}
+	return writeBflMermaid(cmd.OutOrStdout(), toBflGraphs(models))
+}
+
 func toBflView(bflResponseModel wrappers.BFLResponseModel) []wrappers.ScanResultNode {
 	if (bflResponseModel.TotalCount) > 0 {
 		views := make([]wrappers.ScanResultNode, bflResponseModel.TotalCount)
@@ -491,6 +567,16 @@ func writeConsoleSummary(summary *wrappers.ResultSummary) error {
 			fmt.Printf("              |              SCA: %*d|     \n", defaultPaddingSize, summary.ScaIssues)
 		}
 		fmt.Printf("              -----------------------------------     \n")
+		if len(summary.Owners) > 0 {
+			fmt.Printf("              Results per Owner:                     \n")
+			for _, owner := range summary.Owners {
+				fmt.Printf(
+					"              %s: %d (High: %d, Medium: %d, Low: %d, Info: %d)\n",
+					owner.Owner, owner.TotalIssues, owner.HighIssues, owner.MediumIssues, owner.LowIssues, owner.InfoIssues,
+				)
+			}
+			fmt.Printf("              -----------------------------------     \n")
+		}
 		fmt.Printf("              Checkmarx One - Scan Summary & Details: %s\n", summary.BaseURI)
 	} else {
 		fmt.Printf("Scan executed in asynchronous mode or still running. Hence, no results generated.\n")
@@ -526,6 +612,10 @@ func runGetResultCommand(
 		if err != nil {
 			return errors.Wrapf(err, "%s", failedListingResults)
 		}
+		annotations, err := getResultsAnnotations(cmd)
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedListingResults)
+		}
 		return CreateScanReport(
 			resultsWrapper,
 			risksOverviewWrapper,
@@ -537,6 +627,7 @@ func runGetResultCommand(
 			formatPdfOptions,
 			targetFile,
 			targetPath,
+			annotations,
 			params)
 	}
 }
@@ -592,6 +683,7 @@ func CreateScanReport(
 	formatPdfOptions,
 	targetFile,
 	targetPath string,
+	annotations *resultsAnnotations,
 	params map[string]string,
 ) error {
 	if scanID == "" {
@@ -613,11 +705,29 @@ func CreateScanReport(
 	if err != nil {
 		return err
 	}
+	if annotations.codeOwnersPath != "" {
+		owners, ownersErr := readCodeOwners(annotations.codeOwnersPath)
+		if ownersErr != nil {
+			return ownersErr
+		}
+		setResultsOwners(results, owners)
+	}
+	if annotations.sourceRoot != "" {
+		if drifted := setResultsSnippets(results, annotations.sourceRoot, annotations.contextLines); drifted > 0 {
+			log.Printf(driftedSourcesWarning, drifted, annotations.sourceRoot)
+		}
+	}
 
 	summary, err := SummaryReport(results, scan, risksOverviewWrapper, resultsWrapper)
 	if err != nil {
 		return err
 	}
+	if annotations.codeOwnersPath != "" {
+		summary.Owners = toOwnersSummary(summary, results)
+	}
+	if annotations.sourceRoot != "" {
+		summary.Snippets = toSnippetsSummary(results)
+	}
 
 	reportList := strings.Split(reportTypes, ",")
 	for _, reportType := range reportList {
@@ -629,6 +739,27 @@ func CreateScanReport(
 	return nil
 }
 
+// resultsAnnotations Local data added to the results before the reports are created
+type resultsAnnotations struct {
+	codeOwnersPath string
+	sourceRoot     string
+	contextLines   uint
+}
+
+func getResultsAnnotations(cmd *cobra.Command) (*resultsAnnotations, error) {
+	annotations := &resultsAnnotations{}
+	annotations.codeOwnersPath, _ = cmd.Flags().GetString(commonParams.CodeOwnersFlag)
+	annotations.sourceRoot, _ = cmd.Flags().GetString(commonParams.SourceRootFlag)
+	annotations.contextLines, _ = cmd.Flags().GetUint(commonParams.ContextLinesFlag)
+	if annotations.sourceRoot != "" {
+		info, err := os.Stat(annotations.sourceRoot)
+		if err != nil || !info.IsDir() {
+			return nil, errors.Errorf("Invalid --%s %s: Please provide a directory", commonParams.SourceRootFlag, annotations.sourceRoot)
+		}
+	}
+	return annotations, nil
+}
+
 func validateEmails(emailString string) ([]string, error) {
 	re := regexp.MustCompile(`^[a-zA-Z0-9_.+-]+@[a-zA-Z0-9-]+\.[a-zA-Z0-9-.]+$`)
 	emails := strings.Split(emailString, ",")
@@ -700,6 +831,9 @@ func createReport(
 	if printer.IsFormat(format, printer.FormatSummaryConsole) {
 		return writeConsoleSummary(summary)
 	}
+	if printer.IsFormat(format, printer.FormatConsoleDetailed) {
+		return writeConsoleDetails(os.Stdout, results)
+	}
 	if printer.IsFormat(format, printer.FormatSummary) {
 		summaryRpt := createTargetName(targetFile, targetPath, "html")
 		convertNotAvailableNumberToZero(summary)
@@ -719,6 +853,10 @@ func createReport(
 		convertNotAvailableNumberToZero(summary)
 		return writeMarkdownSummary(summaryRpt, summary)
 	}
+	if printer.IsFormat(format, printer.FormatCSV) {
+		csvRpt := createTargetName(targetFile, targetPath, printer.FormatCSV)
+		return exportCSVResults(csvRpt, results)
+	}
 	err := fmt.Errorf("bad report format %s", format)
 	return err
 }
@@ -852,6 +990,38 @@ func exportJSONResults(targetFile string, results *wrappers.ScanResultsCollectio
 	return nil
 }
 
+func exportCSVResults(targetFile string, results *wrappers.ScanResultsCollection) error {
+	log.Println("Creating CSV Report: ", targetFile)
+	f, err := os.Create(targetFile)
+	if err != nil {
+		return errors.Wrapf(err, "%s: failed to create target file  ", failedGettingAll)
+	}
+	defer f.Close()
+	writer := csv.NewWriter(f)
+	_ = writer.Write([]string{"Type", "Severity", "State", "Status", "Name", "Location", "Similarity ID", "First found at", "Owners"})
+	if results != nil {
+		for _, result := range results.Results {
+			name := result.ScanResultData.QueryName
+			if name == "" {
+				name = result.ScanResultData.PackageIdentifier
+			}
+			_ = writer.Write([]string{
+				result.Type,
+				result.Severity,
+				result.State,
+				result.Status,
+				name,
+				getResultLocation(result),
+				result.SimilarityID,
+				result.FirstFoundAt,
+				strings.Join(result.Owners, " "),
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
 func exportJSONSummaryResults(targetFile string, results *wrappers.ResultSummary) error {
 	var err error
 	var resultsJSON []byte
@@ -1200,6 +1370,9 @@ func initSarifResult(result *wrappers.ScanResult) wrappers.SarifScanResult {
 	scanResult.RuleID, _, scanResult.Message.Text = findRuleID(result)
 	scanResult.Level = findSarifLevel(result)
 	scanResult.Locations = []wrappers.SarifLocation{}
+	if len(result.Owners) > 0 {
+		scanResult.Properties = &wrappers.SarifResultProperties{Owners: result.Owners}
+	}
 
 	return scanResult
 }
@@ -1255,6 +1428,7 @@ func parseSarifResultKics(result *wrappers.ScanResult, scanResults []wrappers.Sa
 	scanLocation.PhysicalLocation.Region.StartLine = result.ScanResultData.Line
 	scanLocation.PhysicalLocation.Region.StartColumn = 1
 	scanLocation.PhysicalLocation.Region.EndColumn = 2
+	scanLocation.PhysicalLocation.ContextRegion = toSarifContextRegion(result.ScanResultData.Snippet)
 	scanResult.Locations = append(scanResult.Locations, scanLocation)
 
 	scanResults = append(scanResults, scanResult)
@@ -1279,6 +1453,7 @@ func parseSarifResultSast(result *wrappers.ScanResult, scanResults []wrappers.Sa
 		length := node.Length
 		scanLocation.PhysicalLocation.Region.StartColumn = column
 		scanLocation.PhysicalLocation.Region.EndColumn = column + length
+		scanLocation.PhysicalLocation.ContextRegion = toSarifContextRegion(node.Snippet)
 
 		scanResult.Locations = append(scanResult.Locations, scanLocation)
 	}
@@ -1373,3 +1548,50 @@ func addPackageInformation(
 	}
 	return resultsModel
 }
+
+type sourceLine = wrappers.SourceLine
+
+// sourceFilePath The file of a result under the source root, false when its name leads out of it
+func sourceFilePath(sourceRoot, fileName string) (string, bool) {
+	root := filepath.Clean(sourceRoot)
+	filePath := filepath.Join(root, filepath.FromSlash(strings.TrimLeft(fileName, "/")))
+	relativePath, err := filepath.Rel(root, filePath)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filePath, true
+}
+
+// readSourceLines Read a line of a scanned file and the lines around it from the local checkout
+func readSourceLines(sourceRoot, fileName string, line, contextLines uint) []sourceLine {
+	if sourceRoot == "" || fileName == "" || line == 0 {
+		return nil
+	}
+	filePath, ok := sourceFilePath(sourceRoot, fileName)
+	if !ok {
+		logger.PrintIfVerbose(fmt.Sprintf("Ignoring source file %s outside of %s", fileName, sourceRoot))
+		return nil
+	}
+	content, err := util.ReadFileAsString(filePath)
+	if err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Unable to read source file %s: %v", filePath, err))
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if line > uint(len(lines)) {
+		return nil
+	}
+	firstLine := uint(1)
+	if line > contextLines {
+		firstLine = line - contextLines
+	}
+	lastLine := line + contextLines
+	if lastLine > uint(len(lines)) {
+		lastLine = uint(len(lines))
+	}
+	sourceLines := make([]sourceLine, 0, lastLine-firstLine+1)
+	for number := firstLine; number <= lastLine; number++ {
+		sourceLines = append(sourceLines, sourceLine{Number: number, Text: lines[number-1]})
+	}
+	return sourceLines
+}
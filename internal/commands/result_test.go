@@ -3,12 +3,19 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"html/template"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
 	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
 	"gotest.tools/assert"
 )
 
@@ -53,6 +60,99 @@ func TestRunGetResultsByScanIdJsonFormat(t *testing.T) {
 	os.Remove(fmt.Sprintf("%s.%s", fileName, printer.FormatJSON))
 }
 
+func TestRunGetResultsByScanIdWithCodeOwners(t *testing.T) {
+	targetPath := t.TempDir()
+	codeOwnersPath := targetPath + "/CODEOWNERS"
+	err := os.WriteFile(codeOwnersPath, []byte("dummy-file-name @org/team\n"), 0600)
+	assert.NilError(t, err)
+
+	execCmdNilAssertion(
+		t, "results", "show", "--scan-id", "MOCK", "--report-format", "json,sarif,csv,markdown",
+		"--output-path", targetPath, "--codeowners", codeOwnersPath,
+	)
+	for _, extension := range []string{printer.FormatJSON, printer.FormatSarif, printer.FormatCSV, "md"} {
+		content, readErr := os.ReadFile(fmt.Sprintf("%s/%s.%s", targetPath, fileName, extension))
+		assert.NilError(t, readErr)
+		assert.Assert(t, strings.Contains(string(content), "@org/team"), extension)
+	}
+}
+
+func TestRunGetResultsByScanIdInvalidCodeOwners(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--codeowners", "missing/CODEOWNERS")
+	assert.ErrorContains(t, err, failedReadingCodeOwners)
+}
+
+func TestRunGetResultsByScanIdWithSourceRoot(t *testing.T) {
+	targetPath := t.TempDir()
+	var source strings.Builder
+	for i := 1; i <= 12; i++ {
+		source.WriteString(fmt.Sprintf("line %d <b>\n", i))
+	}
+	err := os.WriteFile(targetPath+"/dummy-file-name", []byte(source.String()), 0600)
+	assert.NilError(t, err)
+
+	execCmdNilAssertion(
+		t, "results", "show", "--scan-id", "MOCK", "--report-format", "json,sarif,markdown,summaryHTML",
+		"--output-path", targetPath, "--source-root", targetPath, "--context-lines", "1",
+	)
+	expected := map[string]string{
+		printer.FormatJSON:  `"snippet":{"lines":[{"number":9,"text":"line 9 \u003cb\u003e"}`,
+		printer.FormatSarif: `"contextRegion":{"startLine":9,"endLine":11`,
+		"md":                "10 | line 10 <b>",
+		"html":              "10 | line 10 &lt;b&gt;",
+	}
+	for extension, text := range expected {
+		content, readErr := os.ReadFile(fmt.Sprintf("%s/%s.%s", targetPath, fileName, extension))
+		assert.NilError(t, readErr)
+		assert.Assert(t, strings.Contains(string(content), text), extension)
+	}
+	content, err := os.ReadFile(fmt.Sprintf("%s/%s.html", targetPath, fileName))
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), `<div class="snippets-row">`))
+}
+
+func TestRunGetResultsByScanIdInvalidSourceRoot(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--source-root", "missing-directory")
+	assert.ErrorContains(t, err, "Invalid --source-root missing-directory")
+}
+
+func TestSetResultsSnippets(t *testing.T) {
+	sourceRoot := t.TempDir()
+	err := os.WriteFile(sourceRoot+"/Main.java", []byte("class Main {\n  String name = input();\n}\n"), 0600)
+	assert.NilError(t, err)
+	results := &wrappers.ScanResultsCollection{Results: []*wrappers.ScanResult{
+		{
+			Type: "sast",
+			ScanResultData: wrappers.ScanResultData{
+				QueryName: "Stored_XSS",
+				Nodes: []*wrappers.ScanResultNode{
+					{FileName: "/Main.java", Line: 2, Name: "input"},
+					{FileName: "/Main.java", Line: 2, Name: "output"},
+					{FileName: "/Missing.java", Line: 1},
+					{FileName: "/Main.java"},
+				},
+			},
+		},
+		{Type: "kics", ScanResultData: wrappers.ScanResultData{Filename: "/Main.java", Line: 3}},
+	}}
+
+	assert.Equal(t, setResultsSnippets(results, sourceRoot, 1), 2)
+	nodes := results.Results[0].ScanResultData.Nodes
+	assert.DeepEqual(t, nodes[0].Snippet, &wrappers.ScanResultSnippet{Lines: []wrappers.SourceLine{
+		{Number: 1, Text: "class Main {"}, {Number: 2, Text: "  String name = input();"}, {Number: 3, Text: "}"},
+	}})
+	assert.Assert(t, nodes[1].Snippet.Drifted)
+	assert.Assert(t, nodes[2].Snippet.Drifted)
+	assert.Assert(t, nodes[3].Snippet == nil)
+	assert.Equal(t, len(results.Results[1].ScanResultData.Snippet.Lines), 3)
+
+	snippets := toSnippetsSummary(results)
+	assert.Equal(t, len(snippets), 4)
+	assert.Equal(t, snippets[0].Name, "Stored XSS")
+	assert.Equal(t, toSarifContextRegion(nodes[0].Snippet).Snippet.Text, "class Main {\n  String name = input();\n}")
+	assert.Assert(t, toSarifContextRegion(nodes[2].Snippet) == nil)
+}
+
 func TestRunGetResultsByScanIdSummaryJsonFormat(t *testing.T) {
 	execCmdNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--report-format", "summaryJSON")
 
@@ -71,6 +171,56 @@ func TestRunGetResultsByScanIdSummaryConsoleFormat(t *testing.T) {
 	execCmdNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--report-format", "summaryConsole")
 }
 
+func TestRunGetResultsByScanIdConsoleDetailedFormat(t *testing.T) {
+	execCmdNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--report-format", "console-detailed")
+}
+
+func TestWriteConsoleDetails(t *testing.T) {
+	results := &wrappers.ScanResultsCollection{Results: []*wrappers.ScanResult{
+		{
+			Type:     params.SastType,
+			Severity: "HIGH",
+			State:    "TO_VERIFY",
+			ScanResultData: wrappers.ScanResultData{
+				QueryName: "SQL_Injection",
+				Nodes: []*wrappers.ScanResultNode{
+					{Name: "input", Method: "read", FileName: "a.go", Line: 1, Column: 2},
+					{Name: "query", Method: "exec", FileName: "b.go", Line: 3, Column: 4},
+				},
+			},
+		},
+		{
+			Type:           params.KicsType,
+			Severity:       "MEDIUM",
+			ScanResultData: wrappers.ScanResultData{QueryName: "Privileged", ExpectedValue: "false", Value: "true", Filename: "Dockerfile", Line: 5},
+		},
+		{
+			Type:                 params.ScaType,
+			Severity:             "LOW",
+			VulnerabilityDetails: wrappers.VulnerabilityDetails{CveName: "CVE-2021-1"},
+			ScanResultData: wrappers.ScanResultData{
+				PackageIdentifier:  "lib-1.0",
+				RecommendedVersion: "1.1",
+				ScaPackageCollection: &wrappers.ScaPackageCollection{DependencyPathArray: [][]wrappers.DependencyPath{
+					{{Name: "app", Version: "1"}, {Name: "lib", Version: "1.0"}},
+				}},
+			},
+		},
+	}}
+	buffer := bytes.NewBufferString("")
+	assert.NilError(t, writeConsoleDetails(buffer, results))
+	output := buffer.String()
+	assert.Assert(t, strings.Contains(output, "[1] HIGH SAST SQL_Injection (TO_VERIFY)"), output)
+	assert.Assert(t, strings.Contains(output, "  1. input in read a.go:1:2 (source)"), output)
+	assert.Assert(t, strings.Contains(output, "  2. query in exec b.go:3:4 (sink)"), output)
+	assert.Assert(t, strings.Contains(output, "Expected: false"), output)
+	assert.Assert(t, strings.Contains(output, "Actual:   true"), output)
+	assert.Assert(t, strings.Contains(output, "[3] LOW SCA CVE-2021-1"), output)
+	assert.Assert(t, strings.Contains(output, "Dependency path: app@1 > lib@1.0"), output)
+	assert.Assert(t, strings.Contains(output, "Recommended version: 1.1"), output)
+	assert.Assert(t, !strings.Contains(output, "\x1b["), output)
+}
+
 func TestRunGetResultsByScanIdPDFFormat(t *testing.T) {
 	execCmdNilAssertion(t, "results", "show", "--scan-id", "MOCK", "--report-format", "pdf")
 	_, err := os.Stat(fmt.Sprintf("%s.%s", fileName, printer.FormatPDF))
@@ -197,10 +347,7 @@ func TestResultBflHelp(t *testing.T) {
 
 func TestRunGetBflWithMissingOrEmptyScanIdAndQueryId(t *testing.T) {
 	err := execCmdNotNilAssertion(t, "results", "bfl")
-	assert.Equal(t, err.Error(), "required flag(s) \"query-id\", \"scan-id\" not set")
-
-	err = execCmdNotNilAssertion(t, "results", "bfl", "--scan-id", "")
-	assert.Equal(t, err.Error(), "required flag(s) \"query-id\" not set")
+	assert.Equal(t, err.Error(), "required flag(s) \"scan-id\" not set")
 
 	err = execCmdNotNilAssertion(t, "results", "bfl", "--query-id", "")
 	assert.Equal(t, err.Error(), "required flag(s) \"scan-id\" not set")
@@ -232,6 +379,63 @@ func TestRunGetBFLByScanIdAndQueryIdWithFormatList(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestRunGetBFLByScanIdForAllQueries(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	cmd := createASTTestCommand()
+	cmd.SetOut(buffer)
+	err := executeTestCommand(cmd, "results", "bfl", "--scan-id", "MOCK", "--format", "json")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(buffer.String(), "\"QueryName\":\"Mock_Query\""), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "\"Results\":2"), buffer.String())
+}
+
+func TestRunGetBFLByScanIdWithFormatDot(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	cmd := createASTTestCommand()
+	cmd.SetOut(buffer)
+	err := executeTestCommand(cmd, "results", "bfl", "--scan-id", "MOCK", "--format", "dot")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(buffer.String(), "digraph bfl {"), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "t0_n1 -> t0_n0;"), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "fillcolor=\"#f1605d\""), buffer.String())
+}
+
+func TestRunGetBFLByScanIdAndQueryIdWithFormatMermaid(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	cmd := createASTTestCommand()
+	cmd.SetOut(buffer)
+	err := executeTestCommand(cmd, "results", "bfl", "--scan-id", "MOCK", "--query-id", "MOCK", "--format", "mermaid")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(buffer.String(), "flowchart TD"), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "t0_n1 --> t0_n0"), buffer.String())
+	assert.Assert(t, strings.Contains(buffer.String(), "style t0_n0 fill:#f1605d"), buffer.String())
+}
+
+func TestToBflRankViews(t *testing.T) {
+	tree := func(results int, name string) wrappers.BFLTreeModel {
+		return wrappers.BFLTreeModel{
+			BFL:     &wrappers.ScanResultNode{Name: name},
+			Results: make([]*wrappers.ScanResultData, results),
+		}
+	}
+	models := []bflQueryModel{
+		{query: bflQuery{ID: "1", Name: "first"}, model: &wrappers.BFLResponseModel{Trees: []wrappers.BFLTreeModel{tree(1, "a"), {}}}},
+		{query: bflQuery{ID: "2", Name: "second"}, model: &wrappers.BFLResponseModel{Trees: []wrappers.BFLTreeModel{tree(3, "b")}}},
+	}
+	views := toBflRankViews(models)
+	assert.Equal(t, len(views), 2)
+	assert.Equal(t, views[0].Rank, 1)
+	assert.Equal(t, views[0].QueryID, "2")
+	assert.Equal(t, views[0].Results, 3)
+	assert.Equal(t, views[1].Name, "a")
+}
+
+func TestFormatQueryID(t *testing.T) {
+	assert.Equal(t, formatQueryID(float64(1234)), "1234")
+	assert.Equal(t, formatQueryID("5678"), "5678")
+	assert.Equal(t, formatQueryID(nil), "")
+}
+
 func TestRunGetResultsGeneratingPdfReportWithInvalidEmail(t *testing.T) {
 	err := execCmdNotNilAssertion(t,
 		"results", "show",
@@ -277,3 +481,212 @@ func TestRunGetResultsGeneratingPdfReporWithOptions(t *testing.T) {
 	_, err = os.Stat(fmt.Sprintf("%s.%s", fileName, printer.FormatPDF))
 	assert.NilError(t, err, "report file should exist: "+fileName+printer.FormatPDF)
 }
+
+func TestReadSourceLines(t *testing.T) {
+	sourceRoot := t.TempDir()
+	err := os.WriteFile(sourceRoot+"/Main.java", []byte("line 1\r\nline 2\r\nline 3\r\nline 4\r\n"), 0600)
+	assert.NilError(t, err)
+
+	lines := readSourceLines(sourceRoot, "/Main.java", 1, 1)
+	assert.DeepEqual(t, lines, []sourceLine{{Number: 1, Text: "line 1"}, {Number: 2, Text: "line 2"}})
+	lines = readSourceLines(sourceRoot, "/Main.java", 3, 0)
+	assert.DeepEqual(t, lines, []sourceLine{{Number: 3, Text: "line 3"}})
+	assert.Assert(t, readSourceLines(sourceRoot, "/Missing.java", 1, 1) == nil)
+	assert.Assert(t, readSourceLines("", "/Main.java", 1, 1) == nil)
+}
+
+func TestReadSourceLinesOutsideSourceRoot(t *testing.T) {
+	parent := t.TempDir()
+	sourceRoot := filepath.Join(parent, "src")
+	assert.NilError(t, os.Mkdir(sourceRoot, 0700))
+	assert.NilError(t, os.WriteFile(filepath.Join(parent, "secret.txt"), []byte("secret"), 0600))
+	assert.NilError(t, os.WriteFile(filepath.Join(sourceRoot, "Main.java"), []byte("main"), 0600))
+
+	assert.Assert(t, readSourceLines(sourceRoot, "/../secret.txt", 1, 0) == nil)
+	assert.Assert(t, readSourceLines(sourceRoot, "lib/../../secret.txt", 1, 0) == nil)
+	lines := readSourceLines(sourceRoot, "/lib/../Main.java", 1, 0)
+	assert.DeepEqual(t, lines, []sourceLine{{Number: 1, Text: "main"}})
+}
+
+func TestResultsTrend(t *testing.T) {
+	execCmdNilAssertion(t, "results", "trend", "--project-id", "MOCK")
+	execCmdNilAssertion(t, "results", "trend", "--project-id", "MOCK", "--branch", "main", "--since", "30d", "--format", "csv")
+	execCmdNilAssertion(t, "results", "trend", "--project-id", "MOCK", "--format", "html")
+}
+
+func TestResultsTrendInvalidSince(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "trend", "--project-id", "MOCK", "--since", "ninety")
+	assert.ErrorContains(t, err, "Invalid --since value ninety")
+}
+
+func TestGetTrendScansSimilarBranches(t *testing.T) {
+	now := time.Now()
+	scansWrapper := &similarBranchScansWrapper{scans: []wrappers.ScanResponseModel{
+		{ID: "main-2", Branch: "main", CreatedAt: now.Add(-time.Hour)},
+		{ID: "main-old-1", Branch: "main-old", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "main-1", Branch: "main", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "main-0", Branch: "main", CreatedAt: now.Add(-48 * time.Hour)},
+	}}
+
+	scans, err := getTrendScans(scansWrapper, "MOCK", "main", now.Add(-24*time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, len(scans), 2)
+	assert.Equal(t, scans[0].ID, "main-1")
+	assert.Equal(t, scans[1].ID, "main-2")
+
+	scans, err = getTrendScans(scansWrapper, "MOCK", "", now.Add(-24*time.Hour))
+	assert.NilError(t, err)
+	assert.Equal(t, len(scans), 3)
+}
+
+func TestToResultsTrendViews(t *testing.T) {
+	now := time.Now()
+	scans := []wrappers.ScanResponseModel{
+		{ID: "first", CreatedAt: now.Add(-time.Hour)},
+		{ID: "second", CreatedAt: now},
+	}
+	scanResults := []*wrappers.ScanResultsCollection{
+		{Results: []*wrappers.ScanResult{
+			{Type: "sast", Severity: "HIGH", SimilarityID: "1"},
+			{Type: "sast", Severity: "LOW", SimilarityID: "2"},
+			{Type: "kics", Severity: "MEDIUM", SimilarityID: "3", State: notExploitable},
+		}},
+		{Results: []*wrappers.ScanResult{
+			{Type: "sast", Severity: "HIGH", SimilarityID: "1"},
+			{Type: "kics", Severity: "MEDIUM", SimilarityID: "3"},
+			{Type: "sca", Severity: "INFO", SimilarityID: "4"},
+		}},
+	}
+
+	views := toResultsTrendViews(scans, scanResults)
+	assert.Equal(t, len(views), 2)
+	assert.Equal(t, views[0].Total, 2)
+	assert.Equal(t, views[0].High, 1)
+	assert.Equal(t, views[0].Low, 1)
+	assert.Equal(t, views[0].Sast, 2)
+	assert.Equal(t, views[0].New, 0)
+	assert.Equal(t, views[1].Total, 3)
+	assert.Equal(t, views[1].Medium, 1)
+	assert.Equal(t, views[1].Info, 1)
+	assert.Equal(t, views[1].IacSecurity, 1)
+	assert.Equal(t, views[1].Sca, 1)
+	assert.Equal(t, views[1].New, 2)
+	assert.Equal(t, views[1].Fixed, 1)
+
+	report := newResultsTrendReport("MOCK", "main", now.Add(-2*time.Hour), views)
+	assert.Equal(t, len(report.Series), 4)
+	assert.Equal(t, len(report.Bars), 4)
+	tmpl, err := template.New("trendTemplate").Parse(wrappers.ResultsTrendTemplate)
+	assert.NilError(t, err)
+	var html bytes.Buffer
+	assert.NilError(t, tmpl.ExecuteTemplate(&html, "TrendTemplate", report))
+	assert.Assert(t, strings.Contains(html.String(), "<polyline"))
+	assert.Assert(t, strings.Contains(html.String(), "second: 2 new"))
+}
+
+func TestResultsAging(t *testing.T) {
+	execCmdNilAssertion(t, "results", "aging", "--scan-id", "MOCK")
+	execCmdNilAssertion(t, "results", "aging", "--scan-id", "MOCK", "--format", "json", "--threshold", "sast-medium=1")
+}
+
+func TestResultsAgingThreshold(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "aging", "--scan-id", "MOCK", "--threshold", "sast-high=1")
+	assert.ErrorContains(t, err, "Threshold check finished with status Failed")
+}
+
+func TestResultsAgingInvalidSLA(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "aging", "--scan-id", "MOCK", "--sla", "high:30")
+	assert.ErrorContains(t, err, "Invalid --sla value high:30")
+	err = execCmdNotNilAssertion(t, "results", "aging", "--scan-id", "MOCK", "--sla", "")
+	assert.ErrorContains(t, err, "Please provide the SLA days")
+}
+
+func TestToResultAgingViews(t *testing.T) {
+	now := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	results := &wrappers.ScanResultsCollection{Results: []*wrappers.ScanResult{
+		{
+			Type: "sast", Severity: "HIGH", SimilarityID: "1", FirstFoundAt: "2023-05-01T00:00:00Z",
+			ScanResultData: wrappers.ScanResultData{
+				QueryName: "SQL_Injection",
+				Nodes:     []*wrappers.ScanResultNode{{FileName: "/src/payments/Card.java"}},
+			},
+		},
+		{Type: "sast", Severity: "MEDIUM", SimilarityID: "2", Created: "2023-04-05T00:00:00Z"},
+		{Type: "kics", Severity: "LOW", SimilarityID: "3", FirstFoundAt: "2023-06-01T00:00:00Z"},
+		{Type: "sast", Severity: "HIGH", SimilarityID: "4", FirstFoundAt: "2023-01-01T00:00:00Z", State: notExploitable},
+		{Type: "sast", Severity: "INFO", SimilarityID: "5", FirstFoundAt: "2020-01-01T00:00:00Z"},
+		{Type: "sast", Severity: "HIGH", SimilarityID: "6"},
+	}}
+	owners, err := parseCodeOwners("/src/payments/ @org/payments")
+	assert.NilError(t, err)
+	policy := &slaPolicy{days: map[string]int{"high": 30, "medium": 90, "low": 180}, warningDays: 7}
+
+	views := toResultAgingViews(results, policy, owners, now)
+	assert.Equal(t, len(views), 2)
+	assert.Equal(t, views[0].Owner, "@org/payments")
+	assert.Equal(t, views[0].Status, slaBreached)
+	assert.Equal(t, views[0].DaysOpen, 60)
+	assert.Equal(t, views[0].DaysLeft, -30)
+	assert.Equal(t, views[0].Name, "SQL_Injection")
+	assert.Equal(t, views[1].Owner, unownedLabel)
+	assert.Equal(t, views[1].Status, slaDueSoon)
+	assert.Equal(t, views[1].DaysLeft, 4)
+
+	assert.DeepEqual(t, toResultAgingGroupViews(views), []resultAgingGroupView{
+		{Engine: "sast", Owner: "@org/payments", Breached: 1},
+		{Engine: "sast", Owner: unownedLabel, DueSoon: 1},
+	})
+	assert.DeepEqual(t, getSLABreachesCountMap(views), map[string]int{"sast-high": 1})
+}
+
+func TestResultsCompliance(t *testing.T) {
+	execCmdNilAssertion(t, "results", "compliance", "--scan-id", "MOCK")
+	execCmdNilAssertion(t, "results", "compliance", "--scan-id", "MOCK", "--framework", "pci-dss", "--format", "markdown")
+	execCmdNilAssertion(t, "results", "compliance", "--scan-id", "MOCK", "--framework", "cwe-top-25", "--format", "html", "--fail-on-category", "CWE-79")
+}
+
+func TestResultsComplianceFailOnCategory(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "results", "compliance", "--scan-id", "MOCK", "--fail-on-category", "a03")
+	assert.ErrorContains(t, err, "Compliance check finished with status Failed for OWASP Top 10 2021 : A03: 1")
+	err = execCmdNotNilAssertion(t, "results", "compliance", "--scan-id", "MOCK", "--fail-on-category", "A11")
+	assert.ErrorContains(t, err, "Unknown OWASP Top 10 2021 category A11")
+	err = execCmdNotNilAssertion(t, "results", "compliance", "--scan-id", "MOCK", "--framework", "hipaa")
+	assert.ErrorContains(t, err, "Unknown --framework hipaa")
+}
+
+func TestToComplianceCategoryViews(t *testing.T) {
+	// Decoded as the results of the API, the numeric CWE ids being json.Number
+	results := &wrappers.ScanResultsCollection{}
+	assert.NilError(t, json.Unmarshal([]byte(`{"results": [
+		{"type": "sast", "severity": "LOW", "vulnerabilityDetails": {"cweId": "CWE-89", "compliances": ["PCI DSS v3.2.1", "OWASP Top 10 2021 A01: Broken Access Control"]}},
+		{"type": "sast", "severity": "HIGH", "vulnerabilityDetails": {"cweId": "CWE-89", "compliances": ["PCI DSS v3.2.1"]}},
+		{"type": "sast", "severity": "HIGH", "vulnerabilityDetails": {"cweId": 79}},
+		{"type": "sast", "severity": "MEDIUM", "vulnerabilityDetails": {"cweId": "CWE-89"}},
+		{"type": "sast", "severity": "LOW", "vulnerabilityDetails": {"cweId": "CWE-22"}, "state": "NOT_EXPLOITABLE"},
+		{"type": "sca", "severity": "HIGH", "vulnerabilityDetails": {"cweId": "CVE-2021-44228"}},
+		{"type": "sast", "severity": "INFO", "vulnerabilityDetails": {"cweId": 1, "compliances": ["OWASP Top 10 2021"]}},
+		{"type": "kics", "severity": "LOW"}
+	]}`), results))
+	_, isNumber := results.Results[2].VulnerabilityDetails.CweID.(json.Number)
+	assert.Assert(t, isNumber)
+
+	views := toComplianceCategoryViews(complianceFrameworks[owaspTop10Framework], results)
+	assert.Equal(t, len(views), 11)
+	assert.DeepEqual(t, views[0], complianceCategoryView{Category: "A01", Name: "Broken Access Control", Low: 1, Total: 1, Status: complianceFailed})
+	assert.DeepEqual(t, views[1], complianceCategoryView{Category: "A02", Name: "Cryptographic Failures", Status: compliancePassed})
+	assert.DeepEqual(t, views[2], complianceCategoryView{
+		Category: "A03", Name: "Injection", High: 1, Medium: 1, Total: 2, Status: complianceFailed,
+	})
+	assert.Equal(t, views[5].High, 1)
+	assert.Equal(t, views[10].Category, uncategorizedID)
+	assert.Equal(t, views[10].Info, 1)
+
+	framework := complianceFrameworks[owaspTop10Framework]
+	assert.NilError(t, checkComplianceCategories(framework, views, []string{"A02"}))
+	assert.ErrorContains(t, checkComplianceCategories(framework, views, []string{"all"}), "A01: 1 | A03: 2 | A06: 1 | Uncategorized: 1")
+
+	views = toComplianceCategoryViews(complianceFrameworks[pciDssFramework], results)
+	assert.DeepEqual(t, views[0], complianceCategoryView{
+		Category: "6.5.1", Name: "Injection flaws", High: 1, Medium: 1, Low: 1, Total: 3, Status: complianceFailed,
+	})
+}
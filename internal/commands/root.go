@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
 	"log"
 	"os"
@@ -74,8 +75,23 @@ func NewAstCLI(
 	rootCmd.PersistentFlags().Bool(params.InsecureFlag, false, params.InsecureFlagUsage)
 	rootCmd.PersistentFlags().String(params.ProxyFlag, "", params.ProxyFlagUsage)
 	rootCmd.PersistentFlags().String(params.ProxyTypeFlag, "", params.ProxyTypeFlagUsage)
+	rootCmd.PersistentFlags().String(params.ProxyBypassFlag, "", params.ProxyBypassFlagUsage)
 	rootCmd.PersistentFlags().String(params.NtlmProxyDomainFlag, "", params.NtlmProxyDomainFlagUsage)
 	rootCmd.PersistentFlags().String(params.TimeoutFlag, "", params.TimeoutFlagUsage)
+	rootCmd.PersistentFlags().String(params.CACertFlag, "", params.CACertFlagUsage)
+	rootCmd.PersistentFlags().String(params.ClientCertFlag, "", params.ClientCertFlagUsage)
+	rootCmd.PersistentFlags().String(params.ClientKeyFlag, "", params.ClientKeyFlagUsage)
+	rootCmd.PersistentFlags().String(params.ClientCertPasswordFlag, "", params.ClientCertPasswordUsage)
+	rootCmd.PersistentFlags().String(params.LogFormatFlag, params.LogFormatDefault, params.LogFormatFlagUsage)
+	rootCmd.PersistentFlags().String(params.LogLevelFlag, "", params.LogLevelFlagUsage)
+	rootCmd.PersistentFlags().String(params.LogFileFlag, "", params.LogFileFlagUsage)
+	rootCmd.PersistentFlags().Uint(params.LogFileMaxSizeFlag, params.LogFileMaxSizeDefault, params.LogFileMaxSizeFlagUsage)
+	rootCmd.PersistentFlags().Uint(params.LogFileMaxBackupsFlag, params.LogFileMaxBackupsDefault, params.LogFileMaxBackupsFlagUsage)
+	rootCmd.PersistentFlags().String(params.RecordFlag, "", params.RecordFlagUsage)
+	rootCmd.PersistentFlags().String(params.ReplayFlag, "", params.ReplayFlagUsage)
+	rootCmd.PersistentFlags().Float64(params.RateLimitFlag, 0, params.RateLimitFlagUsage)
+	rootCmd.PersistentFlags().Uint(params.MaxInFlightFlag, 0, params.MaxInFlightFlagUsage)
+	rootCmd.PersistentFlags().String(params.RateLimitHostsFlag, "", params.RateLimitHostsFlagUsage)
 	rootCmd.PersistentFlags().String(params.BaseURIFlag, params.BaseURI, params.BaseURIFlagUsage)
 	rootCmd.PersistentFlags().String(params.BaseAuthURIFlag, params.BaseIAMURI, params.BaseAuthURIFlagUsage)
 	rootCmd.PersistentFlags().String(params.ProfileFlag, params.Profile, params.ProfileFlagUsage)
@@ -84,6 +100,9 @@ func NewAstCLI(
 	rootCmd.PersistentFlags().String(params.TenantFlag, params.Tenant, params.TenantFlagUsage)
 	rootCmd.PersistentFlags().Uint(params.RetryFlag, params.RetryDefault, params.RetryUsage)
 	rootCmd.PersistentFlags().Uint(params.RetryDelayFlag, params.RetryDelayDefault, params.RetryDelayUsage)
+	rootCmd.PersistentFlags().Uint(params.RetryMaxDelayFlag, params.RetryMaxDelayDefault, params.RetryMaxDelayUsage)
+	rootCmd.PersistentFlags().Bool(params.RetryPostFlag, false, params.RetryPostUsage)
+	rootCmd.PersistentFlags().String(params.RetryStatusFlag, params.RetryStatusDefault, params.RetryStatusUsage)
 
 	rootCmd.PersistentFlags().Bool(params.ApikeyOverrideFlag, false, "")
 
@@ -92,6 +111,7 @@ func NewAstCLI(
 	// This monitors and traps situations where "extra/garbage" commands
 	// are passed to Cobra.
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		logger.RedirectStandardLog()
 		PrintConfiguration()
 		// Need to check the __complete command to allow correct behavior of the autocomplete
 		if len(args) > 0 && cmd.Name() != params.Help && cmd.Name() != "__complete" {
@@ -106,8 +126,23 @@ func NewAstCLI(
 	_ = viper.BindPFlag(params.TenantKey, rootCmd.PersistentFlags().Lookup(params.TenantFlag))
 	_ = viper.BindPFlag(params.ProxyKey, rootCmd.PersistentFlags().Lookup(params.ProxyFlag))
 	_ = viper.BindPFlag(params.ProxyTypeKey, rootCmd.PersistentFlags().Lookup(params.ProxyTypeFlag))
+	_ = viper.BindPFlag(params.NoProxyKey, rootCmd.PersistentFlags().Lookup(params.ProxyBypassFlag))
 	_ = viper.BindPFlag(params.ProxyDomainKey, rootCmd.PersistentFlags().Lookup(params.NtlmProxyDomainFlag))
 	_ = viper.BindPFlag(params.ClientTimeoutKey, rootCmd.PersistentFlags().Lookup(params.TimeoutFlag))
+	_ = viper.BindPFlag(params.CACertKey, rootCmd.PersistentFlags().Lookup(params.CACertFlag))
+	_ = viper.BindPFlag(params.ClientCertKey, rootCmd.PersistentFlags().Lookup(params.ClientCertFlag))
+	_ = viper.BindPFlag(params.ClientKeyKey, rootCmd.PersistentFlags().Lookup(params.ClientKeyFlag))
+	_ = viper.BindPFlag(params.ClientCertPasswordKey, rootCmd.PersistentFlags().Lookup(params.ClientCertPasswordFlag))
+	_ = viper.BindPFlag(params.LogFormatKey, rootCmd.PersistentFlags().Lookup(params.LogFormatFlag))
+	_ = viper.BindPFlag(params.LogLevelKey, rootCmd.PersistentFlags().Lookup(params.LogLevelFlag))
+	_ = viper.BindPFlag(params.LogFileKey, rootCmd.PersistentFlags().Lookup(params.LogFileFlag))
+	_ = viper.BindPFlag(params.LogFileMaxSizeKey, rootCmd.PersistentFlags().Lookup(params.LogFileMaxSizeFlag))
+	_ = viper.BindPFlag(params.LogFileMaxBackupsKey, rootCmd.PersistentFlags().Lookup(params.LogFileMaxBackupsFlag))
+	_ = viper.BindPFlag(params.RecordKey, rootCmd.PersistentFlags().Lookup(params.RecordFlag))
+	_ = viper.BindPFlag(params.ReplayKey, rootCmd.PersistentFlags().Lookup(params.ReplayFlag))
+	_ = viper.BindPFlag(params.RateLimitKey, rootCmd.PersistentFlags().Lookup(params.RateLimitFlag))
+	_ = viper.BindPFlag(params.MaxInFlightKey, rootCmd.PersistentFlags().Lookup(params.MaxInFlightFlag))
+	_ = viper.BindPFlag(params.RateLimitHostsKey, rootCmd.PersistentFlags().Lookup(params.RateLimitHostsFlag))
 	_ = viper.BindPFlag(params.BaseAuthURIKey, rootCmd.PersistentFlags().Lookup(params.BaseAuthURIFlag))
 	_ = viper.BindPFlag(params.AstAPIKey, rootCmd.PersistentFlags().Lookup(params.AstAPIKeyFlag))
 	_ = viper.BindPFlag(params.AgentNameKey, rootCmd.PersistentFlags().Lookup(params.AgentFlag))
@@ -116,7 +151,11 @@ func NewAstCLI(
 	_ = viper.BindPFlag(params.InsecureFlag, rootCmd.PersistentFlags().Lookup(params.InsecureFlag))
 	_ = viper.BindPFlag(params.RetryFlag, rootCmd.PersistentFlags().Lookup(params.RetryFlag))
 	_ = viper.BindPFlag(params.RetryDelayFlag, rootCmd.PersistentFlags().Lookup(params.RetryDelayFlag))
+	_ = viper.BindPFlag(params.RetryMaxDelayFlag, rootCmd.PersistentFlags().Lookup(params.RetryMaxDelayFlag))
+	_ = viper.BindPFlag(params.RetryPostFlag, rootCmd.PersistentFlags().Lookup(params.RetryPostFlag))
+	_ = viper.BindPFlag(params.RetryStatusFlag, rootCmd.PersistentFlags().Lookup(params.RetryStatusFlag))
 	_ = viper.BindPFlag(params.ApikeyOverrideFlag, rootCmd.PersistentFlags().Lookup(params.ApikeyOverrideFlag))
+	_ = viper.BindPFlag(params.ProfileFlag, rootCmd.PersistentFlags().Lookup(params.ProfileFlag))
 
 	// Set help func
 	rootCmd.SetHelpFunc(
@@ -137,7 +176,7 @@ func NewAstCLI(
 		risksOverviewWrapper,
 		jwtWrapper,
 		scaRealTimeWrapper)
-	projectCmd := NewProjectCommand(projectsWrapper, groupsWrapper)
+	projectCmd := NewProjectCommand(projectsWrapper, groupsWrapper, scansWrapper)
 	resultsCmd := NewResultsCommand(
 		resultsWrapper,
 		scansWrapper,
@@ -160,7 +199,8 @@ func NewAstCLI(
 		tenantWrapper,
 	)
 	configCmd := util.NewConfigCommand()
-	triageCmd := NewResultsPredicatesCommand(resultsPredicatesWrapper)
+	configCmd.AddCommand(util.NewProfileCommand(authWrapper))
+	triageCmd := NewResultsPredicatesCommand(resultsPredicatesWrapper, resultsWrapper, scansWrapper)
 
 	rootCmd.AddCommand(
 		scanCmd,
@@ -253,6 +293,21 @@ func addQueryIDFlag(cmd *cobra.Command, helpMsg string) {
 	cmd.PersistentFlags().String(params.QueryIDFlag, "", helpMsg)
 }
 
+func addYesFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool(params.YesFlag, false, params.YesFlagUsage)
+}
+
+// confirmAction Ask the user to confirm the action, unless --yes was provided
+func confirmAction(cmd *cobra.Command, message string) bool {
+	if yes, _ := cmd.Flags().GetBool(params.YesFlag); yes {
+		return true
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s Continue? (Y/N): ", message)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+}
+
 func printByFormat(cmd *cobra.Command, view interface{}) error {
 	f, _ := cmd.Flags().GetString(params.FormatFlag)
 	return printer.Print(cmd.OutOrStdout(), view, f)
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// bulkConcurrency Number of scans or projects deleted or canceled at the same time
+	bulkConcurrency = 5
+	bulkPageSize    = 100
+	bulkResultDone  = "Done"
+	bulkResultError = "Failed"
+)
+
+// bulkAction Delete or cancel a single scan or project
+type bulkAction func(id string) (*wrappers.ErrorModel, error)
+
+type bulkOutcomeView struct {
+	ID     string
+	Result string
+	Error  string `format:"omitempty"`
+}
+
+func addScanSelectionFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterScanListFlagUsage)
+	cmd.PersistentFlags().String(
+		commonParams.OlderThanFlag,
+		"",
+		"Select only the scans created before this age, ex: 2h, 30m, 180d",
+	)
+	cmd.PersistentFlags().StringSlice(
+		commonParams.ScanStatusFlag,
+		[]string{},
+		"Select only the scans in one of these statuses, ex: Queued,Running",
+	)
+	addYesFlag(cmd)
+	addFormatFlag(cmd, printer.FormatTable, printer.FormatJSON, printer.FormatList)
+}
+
+func isScanSelection(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed(commonParams.FilterFlag) ||
+		cmd.Flags().Changed(commonParams.OlderThanFlag) ||
+		cmd.Flags().Changed(commonParams.ScanStatusFlag)
+}
+
+// runScanBulkAction Select the scans by filters, print them, and apply the action only when --yes is provided
+func runScanBulkAction(
+	cmd *cobra.Command,
+	scansWrapper wrappers.ScansWrapper,
+	actionName, failureMessage string,
+	action bulkAction,
+) error {
+	scans, err := selectScans(cmd, scansWrapper)
+	if err != nil {
+		return errors.Wrapf(err, "%s", failureMessage)
+	}
+	if len(scans) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No scans match the selection.")
+		return nil
+	}
+	err = printByFormat(cmd, toScanViews(scans))
+	if err != nil {
+		return errors.Wrapf(err, "%s", failureMessage)
+	}
+	if yes, _ := cmd.Flags().GetBool(commonParams.YesFlag); !yes {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Use --%s to %s the %d scan(s) listed above.\n", commonParams.YesFlag, actionName, len(scans))
+		return nil
+	}
+
+	scanIDs := make([]string, len(scans))
+	for i := range scans {
+		scanIDs[i] = scans[i].ID
+	}
+	outcomes := applyBulkAction(scanIDs, action)
+	err = printByFormat(cmd, outcomes)
+	if err != nil {
+		return errors.Wrapf(err, "%s", failureMessage)
+	}
+
+	failed := 0
+	for _, outcome := range outcomes {
+		if outcome.Result == bulkResultError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%s: %d of %d scan(s) failed", failureMessage, failed, len(outcomes))
+	}
+	return nil
+}
+
+// selectScans Read every scan matching the filters and keep the ones matching the local age and status predicates
+func selectScans(cmd *cobra.Command, scansWrapper wrappers.ScansWrapper) ([]wrappers.ScanResponseModel, error) {
+	params, err := getFilters(cmd)
+	if err != nil {
+		return nil, err
+	}
+	maxItems := 0
+	if limit, ok := params[commonParams.LimitQueryParam]; ok {
+		maxItems, err = strconv.Atoi(limit)
+		if err != nil || maxItems <= 0 {
+			return nil, errors.Errorf("Invalid %s filter %s", commonParams.LimitQueryParam, limit)
+		}
+	}
+	var createdBefore time.Time
+	olderThan, _ := cmd.Flags().GetString(commonParams.OlderThanFlag)
+	if olderThan != "" {
+		age, ageErr := parseAge(olderThan)
+		if ageErr != nil {
+			return nil, ageErr
+		}
+		createdBefore = time.Now().Add(-age)
+	}
+	statuses, _ := cmd.Flags().GetStringSlice(commonParams.ScanStatusFlag)
+
+	var scans []wrappers.ScanResponseModel
+	read := 0
+	err = readAllPages(params, bulkPageSize, maxItems, fetchScanModelsPage(scansWrapper), func(items []interface{}, _ int) (bool, error) {
+		for _, item := range items {
+			if maxItems > 0 && read >= maxItems {
+				return true, nil
+			}
+			read++
+			scan := item.(wrappers.ScanResponseModel)
+			if isScanSelected(&scan, createdBefore, statuses) {
+				scans = append(scans, scan)
+			}
+		}
+		return maxItems > 0 && read >= maxItems, nil
+	})
+	return scans, err
+}
+
+func isScanSelected(scan *wrappers.ScanResponseModel, createdBefore time.Time, statuses []string) bool {
+	if !createdBefore.IsZero() && !scan.CreatedAt.Before(createdBefore) {
+		return false
+	}
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, status := range statuses {
+		if strings.EqualFold(strings.TrimSpace(status), string(scan.Status)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAge Parse a duration, also accepting a number of days such as 180d
+func parseAge(value string) (time.Duration, error) {
+	var age time.Duration
+	var err error
+	if strings.HasSuffix(value, "d") {
+		var days int
+		days, err = strconv.Atoi(strings.TrimSuffix(value, "d"))
+		age = time.Duration(days) * hoursPerDay * time.Hour
+	} else {
+		age, err = time.ParseDuration(value)
+	}
+	if err != nil || age <= 0 {
+		return 0, errors.Errorf("Invalid --%s value %s", commonParams.OlderThanFlag, value)
+	}
+	return age, nil
+}
+
+// fetchScanModelsPage Fetch a page of scans keeping the response models as items
+func fetchScanModelsPage(scansWrapper wrappers.ScansWrapper) pageFetcher {
+	return func(params map[string]string) ([]interface{}, int, error) {
+		scans, total, err := getScansPage(scansWrapper, params)
+		items := make([]interface{}, len(scans))
+		for i := range scans {
+			items[i] = scans[i]
+		}
+		return items, total, err
+	}
+}
+
+// applyBulkAction Run the action over the IDs with bounded concurrency, keeping the outcomes in order
+func applyBulkAction(ids []string, action bulkAction) []bulkOutcomeView {
+	outcomes := make([]bulkOutcomeView, len(ids))
+	semaphore := make(chan struct{}, bulkConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, id string) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			outcomes[i] = bulkOutcomeView{ID: id, Result: bulkResultDone}
+			errorModel, err := action(id)
+			if err == nil && errorModel != nil {
+				err = errors.Errorf("CODE: %d, %s", errorModel.Code, errorModel.Message)
+			}
+			if err != nil {
+				outcomes[i].Result = bulkResultError
+				outcomes[i].Error = err.Error()
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	return outcomes
+}
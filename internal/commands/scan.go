@@ -283,6 +283,7 @@ func scanCancelSubCommand(scansWrapper wrappers.ScansWrapper) *cobra.Command {
 		Example: heredoc.Doc(
 			`
 			$ cx scan cancel --scan-id <scan ID>
+			$ cx scan cancel --filter project-id=<project ID> --status Queued --older-than 2h --yes
 		`,
 		),
 		Annotations: map[string]string{
@@ -295,6 +296,7 @@ func scanCancelSubCommand(scansWrapper wrappers.ScansWrapper) *cobra.Command {
 		RunE: runCancelScanCommand(scansWrapper),
 	}
 	addScanIDFlag(cancelScanCmd, "One or more scan IDs to cancel, ex: <scan-id>,<scan-id>,...")
+	addScanSelectionFlags(cancelScanCmd)
 	return cancelScanCmd
 }
 
@@ -305,6 +307,7 @@ func scanDeleteSubCommand(scansWrapper wrappers.ScansWrapper) *cobra.Command {
 		Example: heredoc.Doc(
 			`
 			$ cx scan delete --scan-id <scan Id>
+			$ cx scan delete --filter tags-keys=temp,tags-values=true --older-than 180d --yes
 		`,
 		),
 		Annotations: map[string]string{
@@ -317,6 +320,7 @@ func scanDeleteSubCommand(scansWrapper wrappers.ScansWrapper) *cobra.Command {
 		RunE: runDeleteScanCommand(scansWrapper),
 	}
 	addScanIDFlag(deleteScanCmd, "One or more scan IDs to delete, ex: <scan-id>,<scan-id>,...")
+	addScanSelectionFlags(deleteScanCmd)
 	return deleteScanCmd
 }
 
@@ -386,6 +390,7 @@ func scanListSubCommand(scansWrapper wrappers.ScansWrapper) *cobra.Command {
 		RunE: runListScansCommand(scansWrapper),
 	}
 	listScansCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterScanListFlagUsage)
+	addPaginationFlags(listScansCmd)
 	return listScansCmd
 }
 
@@ -511,11 +516,13 @@ func scanCreateSubCommand(
 	addResultFormatFlag(
 		createScanCmd,
 		printer.FormatSummaryConsole,
+		printer.FormatConsoleDetailed,
 		printer.FormatJSON,
 		printer.FormatSummary,
 		printer.FormatSarif,
 		printer.FormatPDF,
 		printer.FormatSummaryMarkdown,
+		printer.FormatCSV,
 	)
 	createScanCmd.PersistentFlags().String(commonParams.ExploitablePathFlag, "", exploitablePathFlagDescription)
 	createScanCmd.PersistentFlags().String(commonParams.LastSastScanTime, "", scaLastScanTimeFlagDescription)
@@ -526,6 +533,7 @@ func scanCreateSubCommand(
 	createScanCmd.PersistentFlags().String(commonParams.TargetFlag, "cx_result", "Output file")
 	createScanCmd.PersistentFlags().String(commonParams.TargetPathFlag, ".", "Output Path")
 	createScanCmd.PersistentFlags().StringSlice(commonParams.FilterFlag, []string{}, filterResultsListFlagUsage)
+	createScanCmd.PersistentFlags().String(commonParams.CodeOwnersFlag, "", commonParams.CodeOwnersFlagUsage)
 	createScanCmd.PersistentFlags().String(commonParams.ProjectGroupList, "", "List of groups to associate to project")
 	createScanCmd.PersistentFlags().String(commonParams.ProjectTagList, "", "List of tags to associate to project")
 	createScanCmd.PersistentFlags().String(
@@ -1622,6 +1630,10 @@ func createReportsAfterScan(
 	if err != nil {
 		return err
 	}
+	annotations, err := getResultsAnnotations(cmd)
+	if err != nil {
+		return err
+	}
 	if !strings.Contains(reportFormats, printer.FormatSummaryConsole) {
 		reportFormats += "," + printer.FormatSummaryConsole
 	}
@@ -1636,6 +1648,7 @@ func createReportsAfterScan(
 		formatPdfOptions,
 		targetFile,
 		targetPath,
+		annotations,
 		params,
 	)
 }
@@ -1656,7 +1669,11 @@ func applyThreshold(
 	if err != nil {
 		return err
 	}
+	return checkThreshold(thresholdMap, summaryMap)
+}
 
+// checkThreshold Compare the counts with the threshold limits, failing when a limit is reached
+func checkThreshold(thresholdMap, summaryMap map[string]int) error {
 	var errorBuilder strings.Builder
 	var messageBuilder strings.Builder
 	for key, thresholdLimit := range thresholdMap {
@@ -1716,14 +1733,25 @@ func getSummaryThresholdMap(resultsWrapper wrappers.ResultsWrapper, scan *wrappe
 	if err != nil {
 		return nil, err
 	}
+	return getSummaryCountMap(results), nil
+}
+
+// getSummaryCountMap Count the exploitable results by engine and severity, ex: sast-high
+func getSummaryCountMap(results *wrappers.ScanResultsCollection) map[string]int {
 	summaryMap := make(map[string]int)
+	if results == nil {
+		return summaryMap
+	}
 	for _, result := range results.Results {
 		if isExploitable(result.State) {
-			key := strings.ToLower(fmt.Sprintf("%s-%s", strings.Replace(result.Type, commonParams.KicsType, commonParams.IacType, 1), result.Severity))
-			summaryMap[key]++
+			summaryMap[getSummaryCountKey(result)]++
 		}
 	}
-	return summaryMap, nil
+	return summaryMap
+}
+
+func getSummaryCountKey(result *wrappers.ScanResult) string {
+	return strings.ToLower(fmt.Sprintf("%s-%s", strings.Replace(result.Type, commonParams.KicsType, commonParams.IacType, 1), result.Severity))
 }
 
 func isExploitable(state string) bool {
@@ -1741,6 +1769,7 @@ func waitForScanCompletion(
 	cmd *cobra.Command,
 ) error {
 	log.Println("Wait for scan to complete", scanResponseModel.ID, scanResponseModel.Status)
+	status := scanResponseModel.Status
 	timeout := time.Now().Add(time.Duration(timeoutMinutes) * time.Minute)
 	fixedWait := time.Duration(waitDelay) * time.Second
 	i := uint64(0)
@@ -1752,7 +1781,7 @@ func waitForScanCompletion(
 		waitDuration := fixedWait + variableWait
 		logger.PrintfIfVerbose("Sleeping %v before polling", waitDuration)
 		time.Sleep(waitDuration)
-		running, err := isScanRunning(scansWrapper, resultsPdfReportsWrapper, resultsWrapper, risksOverviewWrapper, scanResponseModel.ID, cmd)
+		running, err := isScanRunning(scansWrapper, resultsPdfReportsWrapper, resultsWrapper, risksOverviewWrapper, scanResponseModel.ID, &status, cmd)
 		if err != nil {
 			return err
 		}
@@ -1775,12 +1804,26 @@ func waitForScanCompletion(
 	return nil
 }
 
+// logScanStatus Log the transitions of the scan status between the polls
+func logScanStatus(scanID string, previous *wrappers.ScanStatus, current wrappers.ScanStatus) {
+	if *previous == current {
+		return
+	}
+	logger.Info(fmt.Sprintf("Scan %s status changed from %s to %s", scanID, *previous, current), logger.Fields{
+		"scanId": scanID,
+		"from":   string(*previous),
+		"to":     string(current),
+	})
+	*previous = current
+}
+
 func isScanRunning(
 	scansWrapper wrappers.ScansWrapper,
 	resultsPdfReportsWrapper wrappers.ResultsPdfWrapper,
 	resultsWrapper wrappers.ResultsWrapper,
 	risksOverViewWrapper wrappers.RisksOverviewWrapper,
 	scanID string,
+	status *wrappers.ScanStatus,
 	cmd *cobra.Command,
 ) (bool, error) {
 	var scanResponseModel *wrappers.ScanResponseModel
@@ -1793,6 +1836,7 @@ func isScanRunning(
 	if errorModel != nil {
 		log.Fatal(fmt.Sprintf("%s: CODE: %d, %s", failedGetting, errorModel.Code, errorModel.Message))
 	} else if scanResponseModel != nil {
+		logScanStatus(scanID, status, scanResponseModel.Status)
 		if scanResponseModel.Status == wrappers.ScanRunning || scanResponseModel.Status == wrappers.ScanQueued {
 			log.Println("Scan status: ", scanResponseModel.Status)
 			return true, nil
@@ -1827,6 +1871,10 @@ func runListScansCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Com
 			return errors.Wrapf(err, "%s", failedGettingAll)
 		}
 
+		if isAllPages(cmd) {
+			return streamAllPages(cmd, params, "scans", fetchScansPage(scansWrapper))
+		}
+
 		allScansModel, errorModel, err = scansWrapper.Get(params)
 		if err != nil {
 			return errors.Wrapf(err, "%s\n", failedGettingAll)
@@ -1844,6 +1892,31 @@ func runListScansCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Com
 	}
 }
 
+func fetchScansPage(scansWrapper wrappers.ScansWrapper) pageFetcher {
+	return func(params map[string]string) ([]interface{}, int, error) {
+		scans, total, err := getScansPage(scansWrapper, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		items := make([]interface{}, len(scans))
+		for i := range scans {
+			items[i] = toScanView(&scans[i])
+		}
+		return items, total, nil
+	}
+}
+
+func getScansPage(scansWrapper wrappers.ScansWrapper, params map[string]string) ([]wrappers.ScanResponseModel, int, error) {
+	allScansModel, errorModel, err := scansWrapper.Get(params)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "%s", failedGettingAll)
+	}
+	if errorModel != nil {
+		return nil, 0, errors.Errorf(ErrorCodeFormat, failedGettingAll, errorModel.Code, errorModel.Message)
+	}
+	return allScansModel.Scans, int(allScansModel.FilteredTotalCount), nil
+}
+
 func runGetScanByIDCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		var scanResponseModel *wrappers.ScanResponseModel
@@ -1899,6 +1972,9 @@ func runScanWorkflowByIDCommand(scansWrapper wrappers.ScansWrapper) func(cmd *co
 func runDeleteScanCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		scanIDs, _ := cmd.Flags().GetString(commonParams.ScanIDFlag)
+		if scanIDs == "" && isScanSelection(cmd) {
+			return runScanBulkAction(cmd, scansWrapper, "delete", failedDeleting, scansWrapper.Delete)
+		}
 		if scanIDs == "" {
 			return errors.Errorf("%s: Please provide at least one scan ID", failedDeleting)
 		}
@@ -1921,6 +1997,9 @@ func runDeleteScanCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Co
 func runCancelScanCommand(scansWrapper wrappers.ScansWrapper) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		scanIDs, _ := cmd.Flags().GetString(commonParams.ScanIDFlag)
+		if scanIDs == "" && isScanSelection(cmd) {
+			return runScanBulkAction(cmd, scansWrapper, "cancel", failedCanceling, scansWrapper.Cancel)
+		}
 		if scanIDs == "" {
 			return errors.Errorf("%s: Please provide at least one scan ID", failedCanceling)
 		}
@@ -2275,6 +2354,12 @@ func validateCreateScanFlags(cmd *cobra.Command) error {
 	if err != nil {
 		return errors.Errorf("Invalid value for --project-private-package flag. The value must be true or false.")
 	}
+	// Read the CODEOWNERS file before the scan, so an invalid file doesn't fail it after completion
+	if codeOwnersPath, _ := cmd.Flags().GetString(commonParams.CodeOwnersFlag); codeOwnersPath != "" {
+		if _, err = readCodeOwners(codeOwnersPath); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -6,12 +6,14 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	commonParams "github.com/checkmarx/ast-cli/internal/params"
 	"github.com/checkmarx/ast-cli/internal/wrappers"
 	"gotest.tools/assert"
 
 	"github.com/checkmarx/ast-cli/internal/commands/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -81,6 +83,57 @@ func TestRunCancelScanByIdCommand(t *testing.T) {
 	execCmdNilAssertion(t, "scan", "cancel", "--scan-id", "MOCK")
 }
 
+func TestRunDeleteScanByFilterCommand(t *testing.T) {
+	execCmdNilAssertion(t, "scan", "delete", "--filter", "project-id=MOCK", "--older-than", "180d")
+	execCmdNilAssertion(t, "scan", "delete", "--filter", "project-id=MOCK", "--older-than", "2h", "--yes")
+}
+
+func TestRunCancelScanByFilterCommand(t *testing.T) {
+	execCmdNilAssertion(t, "scan", "cancel", "--status", "Queued,Running", "--yes")
+	execCmdNilAssertion(t, "scan", "cancel", "--status", "status", "--yes", "--format", "json")
+}
+
+func TestRunDeleteScanByFilterInvalidAge(t *testing.T) {
+	err := execCmdNotNilAssertion(t, "scan", "delete", "--older-than", "yesterday")
+	assert.Equal(t, err.Error(), "Failed deleting a scan: Invalid --older-than value yesterday")
+}
+
+func TestIsScanSelected(t *testing.T) {
+	scan := &wrappers.ScanResponseModel{Status: wrappers.ScanQueued, CreatedAt: time.Now().Add(-3 * time.Hour)}
+	assert.Assert(t, isScanSelected(scan, time.Time{}, nil))
+	assert.Assert(t, isScanSelected(scan, time.Now().Add(-2*time.Hour), []string{"queued"}))
+	assert.Assert(t, !isScanSelected(scan, time.Now().Add(-4*time.Hour), nil))
+	assert.Assert(t, !isScanSelected(scan, time.Time{}, []string{"Running"}))
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := parseAge("180d")
+	assert.NilError(t, err)
+	assert.Equal(t, age, 180*24*time.Hour)
+	age, err = parseAge("90m")
+	assert.NilError(t, err)
+	assert.Equal(t, age, 90*time.Minute)
+	_, err = parseAge("-1d")
+	assert.Assert(t, err != nil)
+}
+
+func TestApplyBulkAction(t *testing.T) {
+	outcomes := applyBulkAction([]string{"a", "b", "c"}, func(id string) (*wrappers.ErrorModel, error) {
+		switch id {
+		case "b":
+			return &wrappers.ErrorModel{Code: 404, Message: "not found"}, nil
+		case "c":
+			return nil, errors.New("timeout")
+		}
+		return nil, nil
+	})
+	assert.DeepEqual(t, outcomes, []bulkOutcomeView{
+		{ID: "a", Result: bulkResultDone},
+		{ID: "b", Result: bulkResultError, Error: "CODE: 404, not found"},
+		{ID: "c", Result: bulkResultError, Error: "timeout"},
+	})
+}
+
 func TestRunGetAllCommand(t *testing.T) {
 	execCmdNilAssertion(t, "scan", "list")
 }
@@ -97,6 +150,10 @@ func TestRunGetAllCommandOffsetList(t *testing.T) {
 	execCmdNilAssertion(t, "scan", "list", "--format", "list", "--filter", "offset=0")
 }
 
+func TestRunGetAllCommandAllPages(t *testing.T) {
+	execCmdNilAssertion(t, "scan", "list", "--all", "--page-size", "50", "--filter", "offset=0")
+}
+
 func TestRunGetAllCommandStatusesList(t *testing.T) {
 	execCmdNilAssertion(
 		t,
@@ -122,6 +179,13 @@ func TestCreateScan(t *testing.T) {
 	execCmdNilAssertion(t, "scan", "create", "--project-name", "MOCK", "-s", dummyRepo, "-b", "dummy_branch")
 }
 
+func TestCreateScanWithInvalidCodeOwners(t *testing.T) {
+	err := execCmdNotNilAssertion(
+		t, "scan", "create", "--project-name", "MOCK", "-s", dummyRepo, "-b", "dummy_branch", "--codeowners", "missing/CODEOWNERS",
+	)
+	assert.ErrorContains(t, err, failedReadingCodeOwners)
+}
+
 func TestCreateScanSourceDirectory(t *testing.T) {
 	baseArgs := []string{"scan", "create", "--project-name", "MOCK", "-b", "dummy_branch"}
 	execCmdNilAssertion(t, append(baseArgs, "-s", "data", "--file-filter", "!.java")...)
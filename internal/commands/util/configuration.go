@@ -23,11 +23,25 @@ var Properties = map[string]bool{
 	params.TenantKey:                true,
 	params.ProxyKey:                 true,
 	params.ProxyTypeKey:             true,
+	params.NoProxyKey:               true,
 	params.AccessKeyIDConfigKey:     true,
 	params.AccessKeySecretConfigKey: true,
 	params.AstAPIKey:                true,
 	params.BranchKey:                true,
 	params.ClientTimeoutKey:         true,
+	params.RateLimitKey:             true,
+	params.RateLimitBurstKey:        true,
+	params.RateLimitHostsKey:        true,
+	params.MaxInFlightKey:           true,
+	params.CACertKey:                true,
+	params.ClientCertKey:            true,
+	params.ClientKeyKey:             true,
+	params.ClientCertPasswordKey:    true,
+	params.LogFormatKey:             true,
+	params.LogLevelKey:              true,
+	params.LogFileKey:               true,
+	params.LogFileMaxSizeKey:        true,
+	params.LogFileMaxBackupsKey:     true,
 }
 
 func NewConfigCommand() *cobra.Command {
@@ -1,6 +1,7 @@
 package printer
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,12 +20,14 @@ const (
 	FormatSummary         = "summaryHTML"
 	FormatSummaryJSON     = "summaryJSON"
 	FormatSummaryConsole  = "summaryConsole"
+	FormatConsoleDetailed = "console-detailed"
 	FormatList            = "list"
 	FormatTable           = "table"
 	FormatHTML            = "html"
 	FormatPDF             = "pdf"
 	FormatMarkdown        = "md"
 	FormatSummaryMarkdown = "markdown"
+	FormatCSV             = "csv"
 )
 
 func Print(w io.Writer, view interface{}, format string) error {
@@ -40,6 +43,12 @@ func Print(w io.Writer, view interface{}, format string) error {
 	} else if IsFormat(format, FormatTable) {
 		entities := toEntities(view)
 		printTable(w, entities)
+	} else if IsFormat(format, FormatCSV) {
+		entities := toEntities(view)
+		return printCSV(w, entities)
+	} else if IsFormat(format, FormatSummaryMarkdown) {
+		entities := toEntities(view)
+		printMarkdown(w, entities)
 	} else {
 		return errors.Errorf("Invalid format %s", format)
 	}
@@ -131,6 +140,48 @@ func printTable(w io.Writer, entities []*entity) {
 	_, _ = fmt.Fprintln(w)
 }
 
+func printCSV(w io.Writer, entities []*entity) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	csvWriter := csv.NewWriter(w)
+	header := make([]string, len(entities[0].Properties))
+	for i, p := range entities[0].Properties {
+		header[i] = p.Key
+	}
+	_ = csvWriter.Write(header)
+	for _, e := range entities {
+		row := make([]string, len(e.Properties))
+		for i, p := range e.Properties {
+			row[i] = p.Value
+		}
+		_ = csvWriter.Write(row)
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func printMarkdown(w io.Writer, entities []*entity) {
+	if len(entities) == 0 {
+		return
+	}
+	escape := strings.NewReplacer("|", "\\|", "\n", " ", "\r", "")
+	var header, delimiter []string
+	for _, p := range entities[0].Properties {
+		header = append(header, escape.Replace(p.Key))
+		delimiter = append(delimiter, "---")
+	}
+	_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+	_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(delimiter, " | "))
+	for _, e := range entities {
+		row := make([]string, len(e.Properties))
+		for i, p := range e.Properties {
+			row[i] = escape.Replace(p.Value)
+		}
+		_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+	}
+}
+
 func pad(width int, key string) string {
 	padLen := width - len(key) + 1
 	const nonBreakingSpace = string('\u00A0')
@@ -1,6 +1,7 @@
 package printer
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"testing"
@@ -46,3 +47,22 @@ func TestPrintTable(t *testing.T) {
 	err = Print(os.Stdout, []string{"column1", "column2", "column3"}, FormatTable)
 	assert.NilError(t, err, "table print must run well")
 }
+
+type printerTestView struct {
+	Name    string
+	Comment string `format:"name:Comment text"`
+}
+
+func TestPrintCSV(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	err := Print(buffer, []printerTestView{{Name: "first", Comment: "a, b"}}, FormatCSV)
+	assert.NilError(t, err, "csv print must run well")
+	assert.Equal(t, buffer.String(), "Name,Comment text\nfirst,\"a, b\"\n")
+}
+
+func TestPrintMarkdown(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	err := Print(buffer, []printerTestView{{Name: "first", Comment: "a | b"}}, FormatSummaryMarkdown)
+	assert.NilError(t, err, "markdown print must run well")
+	assert.Equal(t, buffer.String(), "| Name | Comment text |\n| --- | --- |\n| first | a \\| b |\n")
+}
@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/checkmarx/ast-cli/internal/commands/util/printer"
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+	"github.com/checkmarx/ast-cli/internal/wrappers/configuration"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	failedProfile           = "Failed to manage profiles"
+	successProfileValidated = "Successfully authenticated to Checkmarx One server with profile %s!\n"
+)
+
+// profileFlags The flags of profile create, mapped to the properties they set
+var profileFlags = map[string]string{
+	params.BaseURIFlag:         params.BaseURIKey,
+	params.BaseAuthURIFlag:     params.BaseAuthURIKey,
+	params.TenantFlag:          params.TenantKey,
+	params.AccessKeyIDFlag:     params.AccessKeyIDConfigKey,
+	params.AccessKeySecretFlag: params.AccessKeySecretConfigKey,
+	params.AstAPIKeyFlag:       params.AstAPIKey,
+}
+
+func NewProfileCommand(authWrapper wrappers.AuthWrapper) *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage configuration profiles",
+		Long:  "The profile command lists, creates, copies, renames, deletes, validates and selects the configuration profiles",
+		Example: heredoc.Doc(
+			`
+			$ cx configure profile list
+			$ cx configure profile create --name staging --base-uri <base_uri> --tenant <tenant> --apikey <api key>
+			$ cx configure profile use --name staging
+		`,
+		),
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List the profiles",
+		Long:    "List the profiles with their base URI, tenant and auth type, the secrets being obfuscated",
+		Example: "$ cx configure profile list --format json",
+		RunE:    runListProfiles(),
+	}
+	listCmd.PersistentFlags().String(
+		params.FormatFlag,
+		"",
+		fmt.Sprintf(params.FormatFlagUsageFormat, []string{printer.FormatTable, printer.FormatJSON, printer.FormatList}),
+	)
+
+	createCmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create a profile",
+		Example: "$ cx configure profile create --name staging --base-uri <base_uri> --tenant <tenant> --client-id <id> --client-secret <secret>",
+		RunE:    runCreateProfile(),
+	}
+	addProfileNameFlag(createCmd)
+	createCmd.Flags().String(params.BaseURIFlag, "", params.BaseURIFlagUsage)
+	createCmd.Flags().String(params.BaseAuthURIFlag, "", params.BaseAuthURIFlagUsage)
+	createCmd.Flags().String(params.TenantFlag, "", params.TenantFlagUsage)
+	createCmd.Flags().String(params.AccessKeyIDFlag, "", params.AccessKeyIDFlagUsage)
+	createCmd.Flags().String(params.AccessKeySecretFlag, "", params.AccessKeySecretFlagUsage)
+	createCmd.Flags().String(params.AstAPIKeyFlag, "", params.AstAPIKeyUsage)
+
+	copyCmd := &cobra.Command{
+		Use:     "copy",
+		Short:   "Copy a profile",
+		Example: "$ cx configure profile copy --name prod --new-name prod-tenant-b",
+		RunE: runProfileChange(func(name, newName string) error {
+			return configuration.CopyProfile(name, newName)
+		}, "Copied profile %s to %s\n"),
+	}
+	addProfileNameFlag(copyCmd)
+	addNewProfileNameFlag(copyCmd)
+
+	renameCmd := &cobra.Command{
+		Use:     "rename",
+		Short:   "Rename a profile",
+		Example: "$ cx configure profile rename --name staging --new-name qa",
+		RunE: runProfileChange(func(name, newName string) error {
+			return configuration.RenameProfile(name, newName)
+		}, "Renamed profile %s to %s\n"),
+	}
+	addProfileNameFlag(renameCmd)
+	addNewProfileNameFlag(renameCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete a profile",
+		Example: "$ cx configure profile delete --name staging",
+		RunE: runProfileChange(func(name, _ string) error {
+			return configuration.DeleteProfile(name)
+		}, "Deleted profile %s\n"),
+	}
+	addProfileNameFlag(deleteCmd)
+
+	useCmd := &cobra.Command{
+		Use:     "use",
+		Short:   "Select the profile used without --profile",
+		Example: "$ cx configure profile use --name staging",
+		RunE: runProfileChange(func(name, _ string) error {
+			return configuration.UseProfile(name)
+		}, "Using profile %s\n"),
+	}
+	addProfileNameFlag(useCmd)
+
+	validateCmd := &cobra.Command{
+		Use:     "validate",
+		Short:   "Validate the authentication of a profile",
+		Long:    "Validate that the CLI is able to communicate with Checkmarx One using the properties of the profile",
+		Example: "$ cx configure profile validate --name staging",
+		RunE:    runValidateProfile(authWrapper),
+	}
+	addProfileNameFlag(validateCmd)
+
+	profileCmd.AddCommand(listCmd, createCmd, copyCmd, renameCmd, deleteCmd, useCmd, validateCmd)
+	return profileCmd
+}
+
+func addProfileNameFlag(cmd *cobra.Command) {
+	cmd.Flags().String(params.ProfileNameFlag, "", "Name of the profile")
+	_ = cmd.MarkFlagRequired(params.ProfileNameFlag)
+}
+
+func addNewProfileNameFlag(cmd *cobra.Command) {
+	cmd.Flags().String(params.NewProfileNameFlag, "", "Name of the new profile")
+	_ = cmd.MarkFlagRequired(params.NewProfileNameFlag)
+}
+
+func runListProfiles() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		summaries, err := configuration.ListProfileSummaries()
+		if err != nil {
+			return errors.Wrapf(err, "%s", failedProfile)
+		}
+		format, _ := cmd.Flags().GetString(params.FormatFlag)
+		if format == "" {
+			format = printer.FormatTable
+		}
+		return printer.Print(cmd.OutOrStdout(), summaries, format)
+	}
+}
+
+func runCreateProfile() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString(params.ProfileNameFlag)
+		properties := make(map[string]string)
+		for flag, property := range profileFlags {
+			properties[property], _ = cmd.Flags().GetString(flag)
+		}
+		if err := configuration.CreateProfile(name, properties); err != nil {
+			return errors.Wrapf(err, "%s", failedProfile)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created profile %s\n", name)
+		return nil
+	}
+}
+
+func runProfileChange(change func(name, newName string) error, message string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString(params.ProfileNameFlag)
+		newName, _ := cmd.Flags().GetString(params.NewProfileNameFlag)
+		if err := change(name, newName); err != nil {
+			return errors.Wrapf(err, "%s", failedProfile)
+		}
+		if newName != "" {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), message, name, newName)
+		} else {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), message, name)
+		}
+		return nil
+	}
+}
+
+// runValidateProfile Run auth validate with the properties of the profile
+func runValidateProfile(authWrapper wrappers.AuthWrapper) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString(params.ProfileNameFlag)
+		if err := configuration.ApplyProfile(name); err != nil {
+			return errors.Wrapf(err, "%s", failedProfile)
+		}
+		authWrapper.SetPath(viper.GetString(params.ScansPathKey))
+		if err := authWrapper.ValidateLogin(); err != nil {
+			return errors.Wrapf(err, "Failed to validate profile %s", name)
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), successProfileValidated, name)
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers/configuration"
+	"github.com/checkmarx/ast-cli/internal/wrappers/mock"
+	"gotest.tools/assert"
+)
+
+func TestProfileCommands(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), ".checkmarx")
+	configuration.SetConfigDir(configDir)
+	defer configuration.SetConfigDir("")
+	cmd := NewProfileCommand(&mock.AuthMockWrapper{})
+
+	err := executeTestCommand(
+		cmd, "create", "--name", "staging", "--base-uri", "https://staging.mock", "--tenant", "tenant", "--apikey", "secret-api-key",
+	)
+	assert.NilError(t, err)
+	stagingPath := filepath.Join(configDir, "checkmarxcli_staging.yaml")
+	info, err := os.Stat(stagingPath)
+	assert.NilError(t, err)
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+	// A property set with configure set, kept by copy and rename
+	file, err := os.OpenFile(stagingPath, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NilError(t, err)
+	_, err = file.WriteString("http_proxy: http://proxy.mock:3128\n")
+	assert.NilError(t, err)
+	assert.NilError(t, file.Close())
+
+	err = executeTestCommand(cmd, "create", "--name", "staging")
+	assert.ErrorContains(t, err, "Profile staging already exists")
+	err = executeTestCommand(cmd, "create", "--name", "../staging")
+	assert.ErrorContains(t, err, "Invalid profile name")
+
+	assert.NilError(t, executeTestCommand(cmd, "copy", "--name", "staging", "--new-name", "prod"))
+	assert.NilError(t, executeTestCommand(cmd, "use", "--name", "prod"))
+	assert.NilError(t, executeTestCommand(cmd, "rename", "--name", "prod", "--new-name", "production"))
+	properties, err := configuration.ReadProfile("production")
+	assert.NilError(t, err)
+	assert.Equal(t, properties[params.ProxyKey], "http://proxy.mock:3128")
+	assert.Equal(t, properties[params.BaseURIKey], "https://staging.mock")
+	err = executeTestCommand(cmd, "rename", "--name", "production", "--new-name", "staging")
+	assert.ErrorContains(t, err, "Profile staging already exists")
+
+	buffer := bytes.NewBufferString("")
+	cmd.SetOut(buffer)
+	assert.NilError(t, executeTestCommand(cmd, "list", "--format", "json"))
+	output := buffer.String()
+	assert.Assert(t, strings.Contains(output, `"Name":"production","Default":true,"BaseURI":"https://staging.mock"`), output)
+	assert.Assert(t, strings.Contains(output, `"AuthType":"API key"`), output)
+	assert.Assert(t, strings.Contains(output, `"Secret":"******-key"`), output)
+	assert.Assert(t, !strings.Contains(output, "secret-api-key"), output)
+	assert.Assert(t, !strings.Contains(output, `"Name":"prod"`), output)
+
+	assert.NilError(t, executeTestCommand(cmd, "validate", "--name", "staging"))
+
+	assert.NilError(t, executeTestCommand(cmd, "delete", "--name", "production"))
+	err = executeTestCommand(cmd, "delete", "--name", "default")
+	assert.ErrorContains(t, err, "The default profile can't be deleted")
+	err = executeTestCommand(cmd, "use", "--name", "production")
+	assert.ErrorContains(t, err, "Profile production doesn't exist")
+
+	buffer.Reset()
+	assert.NilError(t, executeTestCommand(cmd, "list", "--format", "json"))
+	assert.Assert(t, strings.Contains(buffer.String(), `"Name":"default","Default":true`), buffer.String())
+}
+
+func TestProfileCommandsMissingName(t *testing.T) {
+	cmd := NewProfileCommand(&mock.AuthMockWrapper{})
+	err := executeTestCommand(cmd, "use")
+	assert.ErrorContains(t, err, `required flag(s) "name" not set`)
+}
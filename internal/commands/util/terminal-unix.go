@@ -0,0 +1,30 @@
+//go:build linux || darwin
+
+package util
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// MakeRawTerminal Switch the terminal to read single keystrokes without echo, returns a function restoring the
+// previous mode. Fails when the file is not a terminal
+func MakeRawTerminal(file *os.File) (restore func(), err error) {
+	fd := int(file.Fd())
+	termios, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+	raw := *termios
+	raw.Lflag &^= unix.ECHO | unix.ICANON
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	err = unix.IoctlSetTermios(fd, ioctlWriteTermios, &raw)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = unix.IoctlSetTermios(fd, ioctlWriteTermios, termios)
+	}, nil
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package util
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// MakeRawTerminal Switch the console to read single keystrokes without echo, returns a function restoring the
+// previous mode. Fails when the file is not a console
+func MakeRawTerminal(file *os.File) (restore func(), err error) {
+	handle := windows.Handle(file.Fd())
+	var mode uint32
+	err = windows.GetConsoleMode(handle, &mode)
+	if err != nil {
+		return nil, err
+	}
+	err = windows.SetConsoleMode(handle, mode&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_LINE_INPUT))
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = windows.SetConsoleMode(handle, mode)
+	}, nil
+}
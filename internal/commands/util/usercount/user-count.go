@@ -31,6 +31,9 @@ var (
 		params.ProfileFlag,
 		params.RetryFlag,
 		params.RetryDelayFlag,
+		params.RetryMaxDelayFlag,
+		params.RetryPostFlag,
+		params.RetryStatusFlag,
 		params.TenantFlag,
 	}
 )
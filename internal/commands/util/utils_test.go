@@ -1,6 +1,8 @@
 package util
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"gotest.tools/assert"
@@ -12,3 +14,30 @@ func TestNewUtilsCommand(t *testing.T) {
 	cmd := NewUtilsCommand(nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Assert(t, cmd != nil, "Utils command must exist")
 }
+
+func TestMatchGlob(t *testing.T) {
+	assert.Assert(t, MatchGlob("src/*.go", "src/main.go"))
+	assert.Assert(t, !MatchGlob("src/*.go", "src/pkg/main.go"))
+	assert.Assert(t, MatchGlob("**/test/**", "src/test/java/App.java"))
+	assert.Assert(t, MatchGlob("**/test/**", "test/App.java"))
+	assert.Assert(t, MatchGlob("src/**/*.js", "src/index.js"))
+	assert.Assert(t, MatchGlob("file?.txt", "file1.txt"))
+	assert.Assert(t, !MatchGlob("file?.txt", "file10.txt"))
+}
+
+func TestExpandHomeDir(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	assert.NilError(t, err)
+
+	for path, expected := range map[string]string{
+		"~/.ssh/id_rsa":      filepath.Join(homeDir, ".ssh", "id_rsa"),
+		"~":                  homeDir,
+		"keys/id_rsa":        "keys/id_rsa",
+		"/etc/ssh/id_rsa":    "/etc/ssh/id_rsa",
+		"~other/.ssh/id_rsa": "~other/.ssh/id_rsa",
+	} {
+		expanded, expandErr := ExpandHomeDir(path)
+		assert.NilError(t, expandErr)
+		assert.Equal(t, expanded, expected, path)
+	}
+}
@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+// Level The severity of an event, the events below --log-level being dropped
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+	// CorrelationIDHeader The header carrying the correlation ID of the events to Checkmarx One
+	CorrelationIDHeader = "X-Correlation-Id"
+	textTimeLayout      = "2006/01/02 15:04:05"
+	bytesInMegabyte     = 1024 * 1024
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// Fields The structured data of an event
+type Fields map[string]interface{}
+
+var (
+	// correlationID Identify the events and the requests of the command
+	correlationID = uuid.New().String()
+
+	outputMu sync.Mutex
+	sink     *fileSink
+	// reported The invalid settings already reported, once each
+	reported = map[string]bool{}
+)
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// CorrelationID The ID of the command, carried by its events and sent with its requests
+func CorrelationID() string {
+	return correlationID
+}
+
+func Debug(msg string, fields Fields) {
+	Event(LevelDebug, msg, fields)
+}
+
+func Info(msg string, fields Fields) {
+	Event(LevelInfo, msg, fields)
+}
+
+func Warn(msg string, fields Fields) {
+	Event(LevelWarn, msg, fields)
+}
+
+func Error(msg string, fields Fields) {
+	Event(LevelError, msg, fields)
+}
+
+// Event Write the event in --log-format to --log-file, or to the standard logger output, unless below --log-level
+func Event(level Level, msg string, fields Fields) {
+	if level < currentLevel() {
+		return
+	}
+	var line []byte
+	if isJSONFormat() {
+		line = jsonLine(time.Now(), level, msg, fields)
+	} else {
+		line = textLine(time.Now(), msg, fields)
+	}
+	writeLine(line)
+}
+
+// currentLevel --log-level, or debug with --debug
+func currentLevel() Level {
+	if value := strings.ToLower(strings.TrimSpace(viper.GetString(params.LogLevelKey))); value != "" {
+		for level, name := range levelNames {
+			if name == value {
+				return level
+			}
+		}
+		reportOnce(fmt.Sprintf("Invalid --%s %s: use debug, info, warn or error", params.LogLevelFlag, value))
+	}
+	if viper.GetBool(params.DebugFlag) {
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+func isJSONFormat() bool {
+	format := strings.ToLower(strings.TrimSpace(viper.GetString(params.LogFormatKey)))
+	if format != "" && format != TextFormat && format != JSONFormat {
+		reportOnce(fmt.Sprintf("Invalid --%s %s: use text or json", params.LogFormatFlag, format))
+	}
+	return format == JSONFormat
+}
+
+// textLine The message with its fields, sorted, as the standard logger prints it
+func textLine(now time.Time, msg string, fields Fields) []byte {
+	builder := strings.Builder{}
+	builder.WriteString(now.Format(textTimeLayout))
+	builder.WriteString(" ")
+	builder.WriteString(strings.TrimSuffix(msg, "\n"))
+	for _, name := range sortedNames(fields) {
+		builder.WriteString(fmt.Sprintf(" %s=%v", name, fields[name]))
+	}
+	builder.WriteString("\n")
+	return []byte(sanitizeLogs(builder.String()))
+}
+
+// jsonLine The event as a JSON object, one per line
+func jsonLine(now time.Time, level Level, msg string, fields Fields) []byte {
+	event := make(map[string]interface{}, len(fields)+4)
+	for name, value := range fields {
+		if text, ok := value.(string); ok {
+			value = sanitizeLogs(text)
+		}
+		event[name] = value
+	}
+	event["time"] = now.Format(time.RFC3339Nano)
+	event["level"] = level.String()
+	event["msg"] = sanitizeLogs(strings.TrimSuffix(msg, "\n"))
+	event["correlationId"] = correlationID
+	line, err := json.Marshal(event)
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"level": level.String(), "msg": sanitizeLogs(msg), "error": err.Error()})
+	}
+	return append(line, '\n')
+}
+
+func sortedNames(fields Fields) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeLine Append the line to --log-file, falling back to the standard logger output when the file can't be written
+func writeLine(line []byte) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	path := viper.GetString(params.LogFileKey)
+	if path == "" {
+		closeSink()
+		_, _ = standardOutput().Write(line)
+		return
+	}
+	if sink == nil || sink.path != path {
+		closeSink()
+		var err error
+		if sink, err = openFileSink(path); err != nil {
+			reportOnceLocked(fmt.Sprintf("Failed to open the log file %s: %s", path, err))
+			_, _ = standardOutput().Write(line)
+			return
+		}
+	}
+	sink.maxSize = int64(viper.GetUint(params.LogFileMaxSizeKey)) * bytesInMegabyte
+	sink.maxBackups = int(viper.GetUint(params.LogFileMaxBackupsKey))
+	if _, err := sink.Write(line); err != nil {
+		reportOnceLocked(fmt.Sprintf("Failed to write the log file %s: %s", path, err))
+		_, _ = standardOutput().Write(line)
+	}
+}
+
+func closeSink() {
+	if sink != nil {
+		_ = sink.Close()
+		sink = nil
+	}
+}
+
+// standardOutput The output of the standard logger, stderr unless redirected, such as by the tests
+func standardOutput() io.Writer {
+	output := log.Writer()
+	if _, bridged := output.(standardLogWriter); bridged {
+		return os.Stderr
+	}
+	return output
+}
+
+func reportOnce(msg string) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	reportOnceLocked(msg)
+}
+
+func reportOnceLocked(msg string) {
+	if reported[msg] {
+		return
+	}
+	reported[msg] = true
+	_, _ = fmt.Fprintln(os.Stderr, msg)
+}
+
+// RedirectStandardLog Send what the packages print with the standard logger through the events
+// when the logs are in JSON or in a file, restoring the standard logger otherwise
+func RedirectStandardLog() {
+	if isJSONFormat() || viper.GetString(params.LogFileKey) != "" {
+		log.SetFlags(0)
+		log.SetOutput(standardLogWriter{})
+		return
+	}
+	if _, bridged := log.Writer().(standardLogWriter); bridged {
+		log.SetFlags(log.LstdFlags)
+		log.SetOutput(os.Stderr)
+	}
+}
+
+// standardLogWriter Write each line of the standard logger as an info event
+type standardLogWriter struct{}
+
+func (standardLogWriter) Write(p []byte) (int, error) {
+	Info(string(p), nil)
+	return len(p), nil
+}
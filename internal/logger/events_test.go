@@ -0,0 +1,127 @@
+//go:build !integration
+
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+func useTestLogSettings(t *testing.T, format, level, file string) {
+	viper.Set(params.LogFormatKey, format)
+	viper.Set(params.LogLevelKey, level)
+	viper.Set(params.LogFileKey, file)
+	t.Cleanup(func() {
+		viper.Set(params.LogFormatKey, TextFormat)
+		viper.Set(params.LogLevelKey, "")
+		viper.Set(params.LogFileKey, "")
+		RedirectStandardLog()
+		outputMu.Lock()
+		closeSink()
+		outputMu.Unlock()
+	})
+}
+
+func readTestEvents(t *testing.T, path string) []map[string]interface{} {
+	content, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	var events []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		event := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestJSONEventsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "cx.log")
+	viper.Set(params.AstAPIKey, "secret-api-key")
+	defer viper.Set(params.AstAPIKey, "")
+
+	useTestLogSettings(t, JSONFormat, "", path)
+	Info("Scan status changed", Fields{"scanId": "1", "to": "Completed", "key": "secret-api-key"})
+	PrintIfVerbose("dropped below info")
+	viper.Set(params.LogLevelKey, "debug")
+	Debug("Request finished", Fields{"status": 200})
+	viper.Set(params.LogLevelKey, "error")
+	Warn("dropped below error", nil)
+	Error("Failed", nil)
+
+	events := readTestEvents(t, path)
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[0]["level"], "info")
+	assert.Equal(t, events[0]["msg"], "Scan status changed")
+	assert.Equal(t, events[0]["scanId"], "1")
+	assert.Equal(t, events[0]["key"], "***")
+	assert.Equal(t, events[0]["correlationId"], CorrelationID())
+	assert.Equal(t, events[1]["level"], "debug")
+	assert.Equal(t, events[1]["status"], float64(200))
+	assert.Equal(t, events[2]["level"], "error")
+}
+
+func TestTextEventsToStandardOutput(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	useTestLogSettings(t, TextFormat, "", "")
+	Print("Scan status: Running")
+	PrintIfVerbose("dropped without --debug")
+	Info("Request finished", Fields{"status": 200, "method": "GET"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Assert(t, strings.HasSuffix(lines[0], " Scan status: Running"))
+	assert.Assert(t, strings.HasSuffix(lines[1], " Request finished method=GET status=200"))
+}
+
+func TestRedirectStandardLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cx.log")
+
+	useTestLogSettings(t, JSONFormat, "", path)
+	RedirectStandardLog()
+	log.Println("Wait for scan to complete")
+
+	events := readTestEvents(t, path)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0]["msg"], "Wait for scan to complete")
+
+	viper.Set(params.LogFormatKey, TextFormat)
+	viper.Set(params.LogFileKey, "")
+	RedirectStandardLog()
+	assert.Equal(t, log.Writer(), os.Stderr)
+	assert.Equal(t, log.Flags(), log.LstdFlags)
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cx.log")
+	s, err := openFileSink(path)
+	assert.NilError(t, err)
+	defer s.Close()
+	s.maxSize, s.maxBackups = 10, 2
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = s.Write([]byte(line))
+		assert.NilError(t, err)
+	}
+
+	for file, content := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		written, readErr := os.ReadFile(file)
+		assert.NilError(t, readErr)
+		assert.Equal(t, string(written), content)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.Assert(t, os.IsNotExist(err))
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// fileSink The log file, rotated into <path>.1 to <path>.<maxBackups> once it reaches maxSize
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openFileSink(path string) (*fileSink, error) {
+	s := &fileSink{path: path}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := s.open(os.O_APPEND); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open(mode int) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|mode, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+// Write Append the line, rotating the file first when the line would take it past maxSize
+func (s *fileSink) Write(line []byte) (int, error) {
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return n, err
+}
+
+// rotate Shift the backups, dropping the oldest, and start a new file
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	if s.maxBackups > 0 {
+		_ = os.Remove(s.backup(s.maxBackups))
+		for i := s.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(s.backup(i), s.backup(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(s.path, s.backup(1)); err != nil {
+			return err
+		}
+	}
+	return s.open(os.O_TRUNC)
+}
+
+func (s *fileSink) backup(index int) string {
+	return fmt.Sprintf("%s.%d", s.path, index)
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
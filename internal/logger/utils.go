@@ -2,10 +2,10 @@ package logger
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 	"net/http/httputil"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/checkmarx/ast-cli/internal/params"
@@ -22,21 +22,45 @@ var sanitizeFlags = []string{
 	params.UploadURLEnv,
 }
 
-func Print(msg string) {
-	if utf8.Valid([]byte(msg)) {
-		log.Print(sanitizeLogs(msg))
-	} else {
-		log.Print("Request contains binary data and cannot be printed!")
+var (
+	secretsMu sync.RWMutex
+	// secrets The values obtained at runtime, such as the access tokens, redacted like the sanitized flags
+	secrets []string
+)
+
+// AddSecret Redact the value from the logs, for the secrets that aren't flags
+func AddSecret(value string) {
+	if value == "" {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, secret := range secrets {
+		if secret == value {
+			return
+		}
 	}
+	secrets = append(secrets, value)
+}
+
+func Print(msg string) {
+	printMessage(LevelInfo, msg)
 }
 
 func Printf(msg string, args ...interface{}) {
 	Print(fmt.Sprintf(msg, args...))
 }
 
+// PrintIfVerbose Print the message as a debug event, shown with --debug or --log-level debug
 func PrintIfVerbose(msg string) {
-	if viper.GetBool(params.DebugFlag) {
-		Print(msg)
+	printMessage(LevelDebug, msg)
+}
+
+func printMessage(level Level, msg string) {
+	if utf8.Valid([]byte(msg)) {
+		Event(level, msg, nil)
+	} else {
+		Event(level, "Request contains binary data and cannot be printed!", nil)
 	}
 }
 
@@ -64,6 +88,11 @@ func PrintResponse(r *http.Response, body bool) {
 	PrintIfVerbose(string(requestDump))
 }
 
+// Sanitize Redact the values of the sanitized flags, as in the logs
+func Sanitize(msg string) string {
+	return sanitizeLogs(msg)
+}
+
 func sanitizeLogs(msg string) string {
 	for _, flag := range sanitizeFlags {
 		value := viper.GetString(flag)
@@ -71,5 +100,10 @@ func sanitizeLogs(msg string) string {
 			msg = strings.ReplaceAll(msg, value, "***")
 		}
 	}
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		msg = strings.ReplaceAll(msg, secret, "***")
+	}
 	return msg
 }
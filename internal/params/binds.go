@@ -51,6 +51,24 @@ var EnvVarsBinds = []struct {
 	{BranchKey, BranchEnv, ""},
 	{AstRoleKey, AstRoleEnv, ScaAgent},
 	{TokenExpirySecondsKey, TokenExpirySecondsEnv, "300"},
+	{TokenCachePathKey, TokenCachePathEnv, ""},
+	{TokenCacheDisabledKey, TokenCacheDisabledEnv, "false"},
+	{LoginKeyStoreKey, LoginKeyStoreEnv, LoginKeyStoreKeychain},
+	{RateLimitKey, RateLimitEnv, "0"},
+	{RateLimitBurstKey, RateLimitBurstEnv, "0"},
+	{RateLimitHostsKey, RateLimitHostsEnv, ""},
+	{MaxInFlightKey, MaxInFlightEnv, "0"},
+	{CACertKey, CACertEnv, ""},
+	{ClientCertKey, ClientCertEnv, ""},
+	{ClientKeyKey, ClientKeyEnv, ""},
+	{ClientCertPasswordKey, ClientCertPasswordEnv, ""},
+	{RecordKey, RecordEnv, ""},
+	{ReplayKey, ReplayEnv, ""},
+	{LogFormatKey, LogFormatEnv, "text"},
+	{LogLevelKey, LogLevelEnv, ""},
+	{LogFileKey, LogFileEnv, ""},
+	{LogFileMaxSizeKey, LogFileMaxSizeEnv, "10"},
+	{LogFileMaxBackupsKey, LogFileMaxBackupsEnv, "3"},
 	{ClientTimeoutKey, ClientTimeoutEnv, "30"},
 	{ResultsPdfReportPathKey, ResultsPdfReportPathEnv, "api/reports"},
 }
@@ -7,6 +7,9 @@ const (
 	ClientTimeoutEnv                    = "CX_TIMEOUT"
 	ProxyEnv                            = "HTTP_PROXY"
 	CxProxyEnv                          = "CX_HTTP_PROXY"
+	NoProxyEnv                          = "NO_PROXY"
+	NoProxyLowerCaseEnv                 = "no_proxy"
+	CxNoProxyEnv                        = "CX_NO_PROXY"
 	ProxyTypeEnv                        = "CX_PROXY_AUTH_TYPE"
 	ProxyDomainEnv                      = "CX_PROXY_NTLM_DOMAIN"
 	BaseAuthURIEnv                      = "CX_BASE_AUTH_URI"
@@ -30,6 +33,24 @@ const (
 	SastRmPathEnv                       = "CX_SAST_RM_PATH"
 	UploadsPathEnv                      = "CX_UPLOADS_PATH"
 	TokenExpirySecondsEnv               = "CX_TOKEN_EXPIRY_SECONDS"
+	TokenCachePathEnv                   = "CX_TOKEN_CACHE_PATH"
+	TokenCacheDisabledEnv               = "CX_TOKEN_CACHE_DISABLED"
+	LoginKeyStoreEnv                    = "CX_LOGIN_KEY_STORE"
+	RateLimitEnv                        = "CX_RATE_LIMIT"
+	RateLimitBurstEnv                   = "CX_RATE_LIMIT_BURST"
+	RateLimitHostsEnv                   = "CX_RATE_LIMIT_HOSTS"
+	MaxInFlightEnv                      = "CX_MAX_IN_FLIGHT"
+	CACertEnv                           = "CX_CA_CERT"
+	ClientCertEnv                       = "CX_CLIENT_CERT"
+	ClientKeyEnv                        = "CX_CLIENT_KEY"
+	ClientCertPasswordEnv               = "CX_CLIENT_CERT_PASSWORD"
+	RecordEnv                           = "CX_RECORD"
+	ReplayEnv                           = "CX_REPLAY"
+	LogFormatEnv                        = "CX_LOG_FORMAT"
+	LogLevelEnv                         = "CX_LOG_LEVEL"
+	LogFileEnv                          = "CX_LOG_FILE"
+	LogFileMaxSizeEnv                   = "CX_LOG_FILE_MAX_SIZE"
+	LogFileMaxBackupsEnv                = "CX_LOG_FILE_MAX_BACKUPS"
 	AstRoleEnv                          = "CX_AST_ROLE"
 	AstWebAppHealthCheckPathEnv         = "CX_AST_WEB_APP_HEALTH_CHECK_PATH"
 	AstKeycloakWebAppHealthCheckPathEnv = "CX_AST_KEYCLOAK_WEB_APP_HEALTH_CHECK_PATH"
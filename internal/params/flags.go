@@ -9,11 +9,19 @@ const (
 	DebugUsage                 = "Debug mode with detailed logs"
 	RetryFlag                  = "retry"
 	RetryDefault               = 3
-	RetryUsage                 = "Retry requests to Checkmarx One on connection failure"
+	RetryUsage                 = "Retry requests to Checkmarx One on connection failure or retried HTTP status"
 	RetryDelayFlag             = "retry-delay"
 	RetryDelayDefault          = 20
 	RetryDelayPollingDefault   = 60
 	RetryDelayUsage            = "Time between retries in seconds, use with --" + RetryFlag
+	RetryMaxDelayFlag          = "retry-max-delay"
+	RetryMaxDelayDefault       = 300
+	RetryMaxDelayUsage         = "Maximum time between retries in seconds, the delay doubling after each retry, also capping Retry-After"
+	RetryPostFlag              = "retry-post"
+	RetryPostUsage             = "Also retry the POST requests on the retried statuses, which may not be idempotent"
+	RetryStatusFlag            = "retry-status"
+	RetryStatusDefault         = "429,502,503,504"
+	RetryStatusUsage           = "HTTP statuses retried, with optional retries per status. Example: --retry-status \"429=10,502,503,504\""
 	SourcesFlag                = "file-source"
 	SourcesFlagSh              = "s"
 	TenantFlag                 = "tenant"
@@ -39,17 +47,50 @@ const (
 	AccessKeySecretFlagUsage   = "The OAuth2 client secret"
 	InsecureFlag               = "insecure"
 	InsecureFlagUsage          = "Ignore TLS certificate validations"
+	CACertFlag                 = "ca-cert"
+	CACertFlagUsage            = "PEM bundles of the trusted CAs, added to the system ones. Use ',' as the delimiter"
+	ClientCertFlag             = "client-cert"
+	ClientCertFlagUsage        = "Client certificate for mutual TLS, PEM or PKCS#12"
+	ClientKeyFlag              = "client-key"
+	ClientKeyFlagUsage         = "PEM key of the client certificate, when not in the --client-cert file"
+	ClientCertPasswordFlag     = "client-cert-password"
+	ClientCertPasswordUsage    = "Password of the PKCS#12 client certificate"
+	RecordFlag                 = "record"
+	RecordFlagUsage            = "Record the requests and responses, secrets redacted, into the cassette directory"
+	ReplayFlag                 = "replay"
+	ReplayFlagUsage            = "Serve the responses from the cassette directory instead of the network"
+	LogFormatFlag              = "log-format"
+	LogFormatDefault           = "text"
+	LogFormatFlagUsage         = "Format of the logs, text or json"
+	LogLevelFlag               = "log-level"
+	LogLevelFlagUsage          = "Minimum level of the logs: debug, info, warn or error. Defaults to debug with --debug, info otherwise"
+	LogFileFlag                = "log-file"
+	LogFileFlagUsage           = "Write the logs to the file instead of stderr, rotating it by size"
+	LogFileMaxSizeFlag         = "log-file-max-size"
+	LogFileMaxSizeDefault      = 10
+	LogFileMaxSizeFlagUsage    = "Size in MB of the log file before it is rotated, 0 to never rotate"
+	LogFileMaxBackupsFlag      = "log-file-max-backups"
+	LogFileMaxBackupsDefault   = 3
+	LogFileMaxBackupsFlagUsage = "Number of rotated log files to keep"
 	ScanInfoFormatFlag         = "scan-info-format"
 	FormatFlag                 = "format"
 	FormatFlagUsageFormat      = "Format for the output. One of %s"
 	FilterFlag                 = "filter"
 	BaseURIFlag                = "base-uri"
 	ProxyFlag                  = "proxy"
-	ProxyFlagUsage             = "Proxy server to send communication through"
+	ProxyFlagUsage             = "Proxy server to send communication through, http, https or socks5"
 	ProxyTypeFlag              = "proxy-auth-type"
-	ProxyTypeFlagUsage         = "Proxy authentication type, (basic or ntlm)"
+	ProxyTypeFlagUsage         = "Proxy authentication type, (basic, ntlm or none)"
+	ProxyBypassFlag            = "proxy-bypass"
+	ProxyBypassFlagUsage       = "Hosts reached without the proxy, as NO_PROXY. Example: --proxy-bypass \"localhost,.internal.com,10.0.0.0/8\""
 	TimeoutFlag                = "timeout"
 	TimeoutFlagUsage           = "Timeout for network activity, (default 5 seconds)"
+	RateLimitFlag              = "rate-limit"
+	RateLimitFlagUsage         = "Maximum requests per second sent to each host, 0 for no limit"
+	MaxInFlightFlag            = "max-in-flight"
+	MaxInFlightFlagUsage       = "Maximum requests awaiting their response from each host, 0 for no limit"
+	RateLimitHostsFlag         = "rate-limit-hosts"
+	RateLimitHostsFlagUsage    = "Limits of specific hosts. Format <host>=<requests per second>[:<max in flight>]"
 	NtlmProxyDomainFlag        = "proxy-ntlm-domain"
 	NtlmProxyDomainFlagUsage   = "Window domain when using NTLM proxy"
 	BaseURIFlagUsage           = "The base system URI"
@@ -104,6 +145,45 @@ const (
 	SeverityFlag             = "severity"
 	StateFlag                = "state"
 	CommentFlag              = "comment"
+	QueryFlag                = "query"
+	PathGlobFlag             = "path-glob"
+	YesFlag                  = "yes"
+	YesFlagUsage             = "Skip the confirmation prompt"
+	UserFlag                 = "user"
+	FromDateFlag             = "from-date"
+	ToDateFlag               = "to-date"
+	DateLayout               = "2006-01-02"
+	SourceRootFlag           = "source-root"
+	ContextLinesFlag         = "context-lines"
+	ProjectsFileFlag         = "file"
+	ProjectsFileFlagSh       = "f"
+	ConfigKeyFlag            = "key"
+	ConfigValueFlag          = "value"
+	OlderThanFlag            = "older-than"
+	SinceFlag                = "since"
+	ScanStatusFlag           = "status"
+	KeepScansFlag            = "keep-scans"
+	InactiveDaysFlag         = "inactive-days"
+	KeepTagFlag              = "keep-tag"
+	ApplyFlag                = "apply"
+	AllFlag                  = "all"
+	AllFlagUsage             = "Fetch every page and stream the items as JSON lines"
+	PageSizeFlag             = "page-size"
+	PageSizeDefault          = 100
+	PageSizeFlagUsage        = "Number of items fetched per page, use with --" + AllFlag
+	MaxItemsFlag             = "max-items"
+	MaxItemsFlagUsage        = "Maximum number of items to fetch, use with --" + AllFlag
+	SLAFlag                  = "sla"
+	SLAWarningDaysFlag       = "warning-days"
+	CodeOwnersFlag           = "codeowners"
+	CodeOwnersFlagUsage      = "Path to a CODEOWNERS file used to map the results to their owners"
+	FrameworkFlag            = "framework"
+	FailOnCategoryFlag       = "fail-on-category"
+	BrowserFlag              = "browser"
+	CallbackPortFlag         = "callback-port"
+	LoginClientIDFlag        = "login-client-id"
+	ProfileNameFlag          = "name"
+	NewProfileNameFlag       = "new-name"
 	LanguageFlag             = "language"
 	VulnerabilityTypeFlag    = "vulnerability-type"
 	CweIDFlag                = "cwe-id"
@@ -166,6 +246,7 @@ const (
 	StatusesQueryParam     = "statuses"
 	StatusQueryParam       = "status"
 	BranchNameQueryParam   = "branch-name"
+	BranchQueryParam       = "branch"
 	ProjectIDQueryParam    = "project-id"
 	FromDateQueryParam     = "from-date"
 	ToDateQueryParam       = "to-date"
@@ -198,6 +279,12 @@ const (
 // ScaAgent AST Role
 const ScaAgent = "SCA_AGENT"
 
+// Login key stores
+const (
+	LoginKeyStoreKeychain = "keychain"
+	LoginKeyStoreFile     = "file"
+)
+
 var (
 	Version = "dev"
 )
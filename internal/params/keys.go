@@ -7,6 +7,7 @@ var (
 	BranchKey                           = strings.ToLower(BranchEnv)
 	BaseURIKey                          = strings.ToLower(BaseURIEnv)
 	ProxyKey                            = strings.ToLower(ProxyEnv)
+	NoProxyKey                          = strings.ToLower(NoProxyEnv)
 	ProxyTypeKey                        = strings.ToLower(ProxyTypeEnv)
 	ProxyDomainKey                      = strings.ToLower(ProxyDomainEnv)
 	BaseAuthURIKey                      = strings.ToLower(BaseAuthURIEnv)
@@ -28,6 +29,24 @@ var (
 	AccessKeyIDConfigKey                = strings.ToLower(AccessKeyIDEnv)
 	AccessKeySecretConfigKey            = strings.ToLower(AccessKeySecretEnv)
 	TokenExpirySecondsKey               = strings.ToLower(TokenExpirySecondsEnv)
+	TokenCachePathKey                   = strings.ToLower(TokenCachePathEnv)
+	TokenCacheDisabledKey               = strings.ToLower(TokenCacheDisabledEnv)
+	LoginKeyStoreKey                    = strings.ToLower(LoginKeyStoreEnv)
+	RateLimitKey                        = strings.ToLower(RateLimitEnv)
+	RateLimitBurstKey                   = strings.ToLower(RateLimitBurstEnv)
+	RateLimitHostsKey                   = strings.ToLower(RateLimitHostsEnv)
+	MaxInFlightKey                      = strings.ToLower(MaxInFlightEnv)
+	CACertKey                           = strings.ToLower(CACertEnv)
+	ClientCertKey                       = strings.ToLower(ClientCertEnv)
+	ClientKeyKey                        = strings.ToLower(ClientKeyEnv)
+	ClientCertPasswordKey               = strings.ToLower(ClientCertPasswordEnv)
+	RecordKey                           = strings.ToLower(RecordEnv)
+	ReplayKey                           = strings.ToLower(ReplayEnv)
+	LogFormatKey                        = strings.ToLower(LogFormatEnv)
+	LogLevelKey                         = strings.ToLower(LogLevelEnv)
+	LogFileKey                          = strings.ToLower(LogFileEnv)
+	LogFileMaxSizeKey                   = strings.ToLower(LogFileMaxSizeEnv)
+	LogFileMaxBackupsKey                = strings.ToLower(LogFileMaxBackupsEnv)
 	AstRoleKey                          = strings.ToLower(AstRoleEnv)
 	AstWebAppHealthCheckPathKey         = strings.ToLower(AstWebAppHealthCheckPathEnv)
 	AstKeycloakWebAppHealthCheckPathKey = strings.ToLower(AstKeycloakWebAppHealthCheckPathEnv)
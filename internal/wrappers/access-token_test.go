@@ -0,0 +1,67 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+func TestGetAccessTokenConcurrentRefresh(t *testing.T) {
+	var tokenRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		time.Sleep(50 * time.Millisecond)
+		_, _ = io.WriteString(w, `{"access_token":"concurrent-access-token"}`)
+	}))
+	defer server.Close()
+	for key, value := range map[string]interface{}{
+		commonParams.BaseAuthURIKey:           server.URL,
+		commonParams.TenantKey:                "tenant",
+		commonParams.AccessKeyIDConfigKey:     "client",
+		commonParams.AccessKeySecretConfigKey: "secret",
+		commonParams.TokenCacheDisabledKey:    true,
+		commonParams.TokenExpirySecondsKey:    300,
+	} {
+		viper.Set(key, value)
+	}
+	defer func() {
+		viper.Set(commonParams.BaseAuthURIKey, "")
+		viper.Set(commonParams.TenantKey, "")
+		viper.Set(commonParams.AccessKeyIDConfigKey, "")
+		viper.Set(commonParams.AccessKeySecretConfigKey, "")
+		viper.Set(commonParams.TokenCacheDisabledKey, false)
+		viper.Set(commonParams.TokenExpirySecondsKey, 0)
+		writeCredentialsToCache("", time.Time{})
+	}()
+
+	tokens := make([]string, 8)
+	wg := sync.WaitGroup{}
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := GetAccessToken()
+			assert.NilError(t, err)
+			tokens[i] = token
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, atomic.LoadInt32(&tokenRequests), int32(1))
+	for _, token := range tokens {
+		assert.Equal(t, token, "concurrent-access-token")
+	}
+	assert.Equal(t, viper.GetString(commonParams.AstToken), "")
+	assert.Equal(t, logger.Sanitize("Bearer concurrent-access-token"), "Bearer ***")
+}
@@ -3,10 +3,13 @@ package wrappers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
+	"github.com/checkmarx/ast-cli/internal/logger"
 	"github.com/checkmarx/ast-cli/internal/params"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -20,7 +23,12 @@ func NewAuthHTTPWrapper() AuthWrapper {
 	return &AuthHTTPWrapper{}
 }
 
-const failedToParseCreateClientResult = "failed to parse create client result"
+const (
+	failedToParseCreateClientResult = "failed to parse create client result"
+	deviceAuthorizationPath         = "protocol/openid-connect/auth/device"
+	authorizationPath               = "protocol/openid-connect/auth"
+	logoutPath                      = "protocol/openid-connect/logout"
+)
 
 func (a *AuthHTTPWrapper) SetPath(newPath string) {
 	a.path = newPath
@@ -94,3 +102,87 @@ func (a *AuthHTTPWrapper) ValidateLogin() error {
 		return errors.Errorf("failed authentication: %d", resp.StatusCode)
 	}
 }
+
+func (a *AuthHTTPWrapper) StartDeviceAuthorization(clientID string) (*DeviceAuthorization, error) {
+	realmURI, err := getRealmURI()
+	if err != nil {
+		return nil, err
+	}
+	deviceAuthorization := &DeviceAuthorization{}
+	form := url.Values{"client_id": {clientID}, "scope": {loginScope}}
+	err = postOAuthForm(fmt.Sprintf("%s/%s", realmURI, deviceAuthorizationPath), form, deviceAuthorization)
+	if err != nil {
+		return nil, err
+	}
+	return deviceAuthorization, nil
+}
+
+func (a *AuthHTTPWrapper) RequestLoginToken(form url.Values) (*ClientCredentialsInfo, error) {
+	authURI, err := getAuthURI()
+	if err != nil {
+		return nil, err
+	}
+	credentialsInfo := &ClientCredentialsInfo{}
+	err = postOAuthForm(authURI, form, credentialsInfo)
+	if err != nil {
+		return nil, err
+	}
+	return credentialsInfo, nil
+}
+
+func (a *AuthHTTPWrapper) GetAuthorizationURL(query url.Values) (string, error) {
+	realmURI, err := getRealmURI()
+	if err != nil {
+		return "", err
+	}
+	if query.Get("scope") == "" {
+		query.Set("scope", loginScope)
+	}
+	return fmt.Sprintf("%s/%s?%s", realmURI, authorizationPath, query.Encode()), nil
+}
+
+func (a *AuthHTTPWrapper) RevokeLogin(clientID, refreshToken string) error {
+	realmURI, err := getRealmURI()
+	if err != nil {
+		return err
+	}
+	logger.AddSecret(refreshToken)
+	form := url.Values{"client_id": {clientID}, "refresh_token": {refreshToken}}
+	return postOAuthForm(fmt.Sprintf("%s/%s", realmURI, logoutPath), form, nil)
+}
+
+// postOAuthForm Post a form to the auth server, decoding the response into target or into an OAuthError.
+// The codes and refresh tokens of the forms are single use, so the form is retried as any other POST.
+func postOAuthForm(uri string, form url.Values, target interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, uri, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	setAgentName(req)
+	req = addReqMonitor(req)
+	req.Header.Add("content-type", "application/x-www-form-urlencoded")
+	client := GetClient(viper.GetUint(params.ClientTimeoutKey))
+	res, err := doPrivateRequest(client, req, false)
+	if err != nil {
+		return errors.Errorf("%s %s", checkmarxURLError, uri)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		oauthErr := &OAuthError{}
+		if json.Unmarshal(body, oauthErr) != nil || oauthErr.Code == "" {
+			return errors.Errorf("response status code %d body %s", res.StatusCode, string(body))
+		}
+		return oauthErr
+	}
+	if target == nil || len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, target)
+}
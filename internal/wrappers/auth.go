@@ -1,5 +1,7 @@
 package wrappers
 
+import "net/url"
+
 type Oath2Client struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
@@ -13,8 +15,35 @@ type ErrorMsg struct {
 	Code    int    `json:"code"`
 }
 
+// DeviceAuthorization The codes of an OAuth 2.0 device authorization request
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OAuthError An error response of the auth server, such as authorization_pending
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
 type AuthWrapper interface {
 	CreateOauth2Client(client *Oath2Client, username, password, adminClientID, adminClientSecret string) (*ErrorMsg, error)
 	SetPath(path string)
 	ValidateLogin() error
+	StartDeviceAuthorization(clientID string) (*DeviceAuthorization, error)
+	RequestLoginToken(form url.Values) (*ClientCredentialsInfo, error)
+	GetAuthorizationURL(query url.Values) (string, error)
+	RevokeLogin(clientID, refreshToken string) error
 }
@@ -0,0 +1,327 @@
+package wrappers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	cassetteFileFormat   = "%05d.json"
+	cassetteFilePattern  = "*.json"
+	cassetteBodyBase64   = "base64"
+	cassetteRedacted     = "***"
+	jwtRedactedSignature = "redacted"
+)
+
+// cassetteRedactedHeaders The headers recorded as *** whatever their value
+var cassetteRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// jwtPattern The tokens keep their claims, read by the replayed commands, but lose their signature
+var jwtPattern = regexp.MustCompile(`(eyJ[\w-]*\.eyJ[\w-]*)\.[\w-]+`)
+
+// cassetteInteraction A request and its response, one file of the cassette directory
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+// cassetteBody A body as redacted text, base64 when binary, or only its size when omitted
+type cassetteBody struct {
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+	BodyOmitted  int    `json:"bodyOmitted,omitempty"`
+}
+
+// cassette The interactions of a directory, appended to when recording and served in order when replaying
+type cassette struct {
+	dir string
+
+	mu sync.Mutex
+	// next The number of the last recorded interaction
+	next         int
+	interactions []*cassetteInteraction
+	replayed     []bool
+}
+
+var (
+	cassettesMu sync.Mutex
+	// cassettes The opened cassettes, shared by the clients so that the interactions are numbered and replayed in order
+	cassettes = map[string]*cassette{}
+)
+
+// getCassetteTransport Wrap the transport to record into --record, or replace it to serve the responses of --replay
+func getCassetteTransport(base http.RoundTripper) http.RoundTripper {
+	recordDir := viper.GetString(commonParams.RecordKey)
+	replayDir := viper.GetString(commonParams.ReplayKey)
+	switch {
+	case recordDir != "" && replayDir != "":
+		return &failingTransport{
+			err: errors.Errorf("--%s and --%s can't be used together", commonParams.RecordFlag, commonParams.ReplayFlag),
+		}
+	case replayDir != "":
+		c, err := getCassette(replayDir, true)
+		if err != nil {
+			return &failingTransport{err: err}
+		}
+		logger.PrintIfVerbose("Replaying the responses of the cassette " + replayDir)
+		return &replayTransport{cassette: c}
+	case recordDir != "":
+		c, err := getCassette(recordDir, false)
+		if err != nil {
+			return &failingTransport{err: err}
+		}
+		logger.PrintIfVerbose("Recording the requests into the cassette " + recordDir)
+		return &recordingTransport{base: base, cassette: c}
+	}
+	return base
+}
+
+// isCassetteActive True with --record or --replay, the tokens being requested each time for the cassette to hold their exchange
+func isCassetteActive() bool {
+	return viper.GetString(commonParams.RecordKey) != "" || isReplaying()
+}
+
+// isReplaying True with --replay, the replayed responses never being stored
+func isReplaying() bool {
+	return viper.GetString(commonParams.ReplayKey) != ""
+}
+
+func getCassette(dir string, replay bool) (*cassette, error) {
+	key := fmt.Sprintf("%t:%s", replay, dir)
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	if c, ok := cassettes[key]; ok {
+		return c, nil
+	}
+	c := &cassette{dir: dir}
+	var err error
+	if replay {
+		err = c.load()
+	} else {
+		err = os.MkdirAll(dir, 0700)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open the cassette %s", dir)
+	}
+	cassettes[key] = c
+	return c, nil
+}
+
+// load Read the interactions in the order they were recorded
+func (c *cassette) load() error {
+	files, err := filepath.Glob(filepath.Join(c.dir, cassetteFilePattern))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("No recorded interaction")
+	}
+	for _, file := range files {
+		content, readErr := os.ReadFile(file)
+		if readErr != nil {
+			return readErr
+		}
+		interaction := &cassetteInteraction{}
+		if readErr = json.Unmarshal(content, interaction); readErr != nil {
+			return errors.Wrapf(readErr, "Invalid interaction %s", file)
+		}
+		c.interactions = append(c.interactions, interaction)
+	}
+	c.replayed = make([]bool, len(c.interactions))
+	return nil
+}
+
+// record Write the interaction after the existing ones, the cassette being shared with the previous commands
+func (c *cassette) record(interaction *cassetteInteraction) error {
+	content, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		c.next++
+		file, openErr := os.OpenFile(filepath.Join(c.dir, fmt.Sprintf(cassetteFileFormat, c.next)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(openErr) {
+			continue
+		}
+		if openErr != nil {
+			return openErr
+		}
+		_, err = file.Write(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+}
+
+// replay The first interaction of the request not replayed yet, or the last one when all were, such as the polls of a scan
+func (c *cassette) replay(method, url string) (*cassetteInteraction, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last := -1
+	for i, interaction := range c.interactions {
+		if interaction.Request.Method != method || interaction.Request.URL != url {
+			continue
+		}
+		if !c.replayed[i] {
+			c.replayed[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil, false
+	}
+	return c.interactions[last], true
+}
+
+// recordingTransport Send the requests and record them with their responses
+type recordingTransport struct {
+	base     http.RoundTripper
+	cassette *cassette
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	interaction := &cassetteInteraction{
+		Request: cassetteRequest{
+			Method:       req.Method,
+			URL:          redactCassette(req.URL.String()),
+			Header:       redactCassetteHeader(req.Header),
+			cassetteBody: newCassetteBody(body, logger.ContentLengthLimit),
+		},
+		Response: cassetteResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       redactCassetteHeader(resp.Header),
+			cassetteBody: newCassetteBody(respBody, 0),
+		},
+	}
+	if err = t.cassette.record(interaction); err != nil {
+		logger.Printf("Failed to record %s %s into the cassette %s: %s", req.Method, interaction.Request.URL, t.cassette.dir, err)
+	}
+	return resp, nil
+}
+
+// replayTransport Serve the recorded responses, never reaching the network
+type replayTransport struct {
+	cassette *cassette
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	url := redactCassette(req.URL.String())
+	interaction, ok := t.cassette.replay(req.Method, url)
+	if !ok {
+		return nil, errors.Errorf("No recorded response for %s %s in the cassette %s", req.Method, url, t.cassette.dir)
+	}
+	body, err := interaction.Response.bytes()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid recorded response for %s %s", req.Method, url)
+	}
+	header := interaction.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// The redacted body no longer has the recorded length
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// newCassetteBody The body as redacted text, or base64 when binary, omitting the bodies larger than omitAbove if positive
+func newCassetteBody(body []byte, omitAbove int) cassetteBody {
+	switch {
+	case omitAbove > 0 && len(body) > omitAbove:
+		return cassetteBody{BodyOmitted: len(body)}
+	case utf8.Valid(body):
+		return cassetteBody{Body: redactCassette(string(body))}
+	}
+	return cassetteBody{Body: base64.StdEncoding.EncodeToString(body), BodyEncoding: cassetteBodyBase64}
+}
+
+func (b *cassetteBody) bytes() ([]byte, error) {
+	if b.BodyEncoding == cassetteBodyBase64 {
+		return base64.StdEncoding.DecodeString(b.Body)
+	}
+	return []byte(b.Body), nil
+}
+
+// redactCassette Strip the token signatures and redact the values of the sanitized flags, as in the logs
+func redactCassette(value string) string {
+	return logger.Sanitize(jwtPattern.ReplaceAllString(value, "$1."+jwtRedactedSignature))
+}
+
+func redactCassetteHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			redacted.Add(name, redactCassette(value))
+		}
+	}
+	for _, name := range cassetteRedactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, cassetteRedacted)
+		}
+	}
+	return redacted
+}
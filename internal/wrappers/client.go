@@ -1,7 +1,7 @@
 package wrappers
 
 import (
-	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -11,7 +11,10 @@ import (
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -21,20 +24,23 @@ import (
 	"github.com/spf13/viper"
 
 	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers/configuration"
 	"github.com/checkmarx/ast-cli/internal/wrappers/ntlm"
+	"github.com/checkmarx/ast-cli/internal/wrappers/tokencache"
 )
 
 const (
 	expiryGraceSeconds      = 10
 	NoTimeout               = 0
 	ntlmProxyToken          = "ntlm"
+	ntlmIdleConnTimeout     = 90 * time.Second
 	checkmarxURLError       = "Could not reach provided Checkmarx server"
 	APIKeyDecodeErrorFormat = "Token decoding error: %s"
-	tryPrintOffset          = 2
-	retryLimitPrintOffset   = 1
 	MissingURI              = "When using client-id and client-secret please provide base-uri or base-auth-uri"
 	MissingTenant           = "Failed to authenticate - please provide tenant"
 	jwtError                = "Error retrieving URL from jwt token"
+	clientCredentialsGrant  = "client_credentials"
+	passwordGrant           = "password"
 )
 
 type ClientCredentialsInfo struct {
@@ -59,8 +65,13 @@ const baseURLKey = "ast-base-url"
 
 const audienceClaimKey = "aud"
 
+// apiKeyClientID The client the API keys are issued for
+const apiKeyClientID = "ast-app"
+
+// accessTokenMu Serialize the token refresh of the concurrent requests, guarding the cached token
+var accessTokenMu sync.Mutex
 var cachedAccessToken string
-var cachedAccessTime time.Time
+var cachedAccessExpiry time.Time
 
 func setAgentName(req *http.Request) {
 	agentStr := viper.GetString(commonParams.AgentNameKey) + "/" + commonParams.Version
@@ -68,15 +79,22 @@ func setAgentName(req *http.Request) {
 }
 
 func GetClient(timeout uint) *http.Client {
-	proxyTypeStr := viper.GetString(commonParams.ProxyTypeKey)
-	proxyStr := viper.GetString(commonParams.ProxyKey)
-
+	tlsConfig, err := getTLSConfig()
+	proxy, proxyErr := getProxyConfig()
+	if err == nil {
+		err = proxyErr
+	}
 	var client *http.Client
-	if proxyTypeStr == ntlmProxyToken {
-		client = ntmlProxyClient(timeout, proxyStr)
+	if err != nil {
+		logger.PrintIfVerbose(err.Error())
+		client = &http.Client{Transport: &failingTransport{err: err}, Timeout: time.Duration(timeout) * time.Second}
+	} else if proxy.url != nil && proxy.authType == ntlmProxyToken {
+		client = ntmlProxyClient(timeout, proxy, tlsConfig)
 	} else {
-		client = basicProxyClient(timeout, proxyStr)
+		client = basicProxyClient(timeout, proxy, tlsConfig)
 	}
+	client.Transport = &rateLimitedTransport{base: client.Transport}
+	client.Transport = getCassetteTransport(client.Transport)
 
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		if len(via) > 1 {
@@ -96,43 +114,81 @@ func GetClient(timeout uint) *http.Client {
 	return client
 }
 
-func basicProxyClient(timeout uint, proxyStr string) *http.Client {
-	insecure := viper.GetBool("insecure")
-	u, _ := url.Parse(proxyStr)
+func basicProxyClient(timeout uint, proxy *proxyConfig, tlsConfig *tls.Config) *http.Client {
 	var tr *http.Transport
-	if len(proxyStr) > 0 {
-		logger.PrintIfVerbose("Creating HTTP Client with Proxy: " + proxyStr)
+	if proxy.url != nil {
+		logger.PrintIfVerbose("Creating HTTP Client with Proxy: " + proxy.url.Redacted())
 		tr = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
-			Proxy:           http.ProxyURL(u),
+			TLSClientConfig: tlsConfig,
+			Proxy:           proxy.proxyFunc(),
 		}
 	} else {
 		logger.PrintIfVerbose("Creating HTTP Client.")
 		tr = &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+			TLSClientConfig: tlsConfig,
 		}
 	}
 	return &http.Client{Transport: tr, Timeout: time.Duration(timeout) * time.Second}
 }
 
-func ntmlProxyClient(timeout uint, proxyStr string) *http.Client {
+var (
+	ntlmTransportsMu sync.Mutex
+	// ntlmTransports The transports of the NTLM proxies, shared by the clients so that the authenticated tunnels are reused
+	ntlmTransports = map[string]*http.Transport{}
+)
+
+func ntmlProxyClient(timeout uint, proxy *proxyConfig, tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: getNTLMTransport(proxy, tlsConfig),
+		Timeout:   time.Duration(timeout) * time.Second,
+	}
+}
+
+// getNTLMTransport The transport of the proxy, credentials and TLS settings, created on the first request through them
+func getNTLMTransport(proxy *proxyConfig, tlsConfig *tls.Config) *http.Transport {
+	domainStr := viper.GetString(commonParams.ProxyDomainKey)
+	key := strings.Join([]string{
+		proxy.url.String(),
+		domainStr,
+		viper.GetString(commonParams.NoProxyKey),
+		strconv.FormatBool(viper.GetBool(commonParams.InsecureFlag)),
+		viper.GetString(commonParams.CACertKey),
+		viper.GetString(commonParams.ClientCertKey),
+		viper.GetString(commonParams.ClientKeyKey),
+		viper.GetString(commonParams.ClientCertPasswordKey),
+	}, "\n")
+	ntlmTransportsMu.Lock()
+	defer ntlmTransportsMu.Unlock()
+	if transport, ok := ntlmTransports[key]; ok {
+		logger.PrintIfVerbose("Reusing HTTP client using NTLM Proxy using: " + proxy.url.Redacted())
+		return transport
+	}
+
 	dialer := &net.Dialer{
 		Timeout:   30 * time.Second,
 		KeepAlive: 30 * time.Second,
 	}
-	u, _ := url.Parse(proxyStr)
-	domainStr := viper.GetString(commonParams.ProxyDomainKey)
-	proxyUser := u.User.Username()
-	proxyPass, _ := u.User.Password()
-	logger.PrintIfVerbose("Creating HTTP client using NTLM Proxy using: " + proxyStr)
-	ntlmDialContext := ntlm.NewNTLMProxyDialContext(dialer, u, proxyUser, proxyPass, domainStr, nil)
-	return &http.Client{
-		Transport: &http.Transport{
-			Proxy:       nil,
-			DialContext: ntlmDialContext,
+	proxyUser := proxy.url.User.Username()
+	proxyPass, _ := proxy.url.User.Password()
+	logger.PrintIfVerbose("Creating HTTP client using NTLM Proxy using: " + proxy.url.Redacted())
+	var proxyTLSConfig *tls.Config
+	if tlsConfig != nil {
+		proxyTLSConfig = tlsConfig.Clone()
+	}
+	ntlmDialContext := ntlm.NewNTLMProxyDialContext(dialer, proxy.url, proxyUser, proxyPass, domainStr, proxyTLSConfig)
+	transport := &http.Transport{
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if proxy.bypasses(addr) {
+				return dialer.DialContext(ctx, network, addr)
+			}
+			return ntlmDialContext(ctx, network, addr)
 		},
-		Timeout: time.Duration(timeout) * time.Second,
+		TLSClientConfig: tlsConfig,
+		IdleConnTimeout: ntlmIdleConnTimeout,
 	}
+	ntlmTransports[key] = transport
+	return transport
 }
 
 func getURLAndAccessToken(path string) (urlFromPath, accessToken string, err error) {
@@ -361,13 +417,17 @@ func GetAccessToken() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	tokenExpirySeconds := viper.GetInt(commonParams.TokenExpirySecondsKey)
-	accessToken := getClientCredentialsFromCache(tokenExpirySeconds)
+	accessTokenMu.Lock()
+	defer accessTokenMu.Unlock()
+	accessToken := getClientCredentialsFromCache()
 	accessKeyID := viper.GetString(commonParams.AccessKeyIDConfigKey)
 	accessKeySecret := viper.GetString(commonParams.AccessKeySecretConfigKey)
 	astAPIKey := viper.GetString(commonParams.AstAPIKey)
 	if accessKeyID == "" && astAPIKey == "" {
-		return "", errors.Errorf(fmt.Sprintf(FailedToAuth, "access key ID"))
+		if accessToken != "" {
+			return accessToken, nil
+		}
+		return getLoginAccessToken(authURI)
 	} else if accessKeySecret == "" && astAPIKey == "" {
 		return "", errors.Errorf(fmt.Sprintf(FailedToAuth, "access key secret"))
 	}
@@ -406,10 +466,13 @@ func enrichWithPasswordCredentials(
 
 func getClientCredentials(accessKeyID, accessKeySecret, astAPKey, authURI string) (string, error) {
 	logger.PrintIfVerbose("Fetching API access token.")
-	tokenExpirySeconds := viper.GetInt(commonParams.TokenExpirySecondsKey)
 
 	var err error
-	accessToken := getClientCredentialsFromCache(tokenExpirySeconds)
+	accessToken := getClientCredentialsFromCache()
+	cacheKey, cacheSecret := getTokenCacheKey(accessKeyID, accessKeySecret, astAPKey, authURI)
+	if accessToken == "" {
+		accessToken = getClientCredentialsFromDisk(cacheKey, cacheSecret)
+	}
 
 	if accessToken == "" {
 		// If the token is present the default to that.
@@ -423,16 +486,17 @@ func getClientCredentials(accessKeyID, accessKeySecret, astAPKey, authURI string
 			return "", errors.Errorf("%s", err)
 		}
 
-		writeCredentialsToCache(accessToken)
+		expiry := getTokenExpiry(accessToken)
+		writeCredentialsToCache(accessToken, expiry)
+		writeCredentialsToDisk(cacheKey, cacheSecret, accessToken, expiry)
 	}
 
 	return accessToken, nil
 }
 
-func getClientCredentialsFromCache(tokenExpirySeconds int) string {
+func getClientCredentialsFromCache() string {
 	logger.PrintIfVerbose("Checking cache for API access token.")
-	expired := time.Since(cachedAccessTime) > time.Duration(tokenExpirySeconds-expiryGraceSeconds)*time.Second
-	if !expired {
+	if isTokenValid(cachedAccessToken, cachedAccessExpiry) {
 		logger.PrintIfVerbose("Using cached API access token!")
 		return cachedAccessToken
 	}
@@ -440,38 +504,124 @@ func getClientCredentialsFromCache(tokenExpirySeconds int) string {
 	return ""
 }
 
-func writeCredentialsToCache(accessToken string) {
+func writeCredentialsToCache(accessToken string, expiry time.Time) {
 	logger.PrintIfVerbose("Storing API access token to cache.")
-	viper.Set(commonParams.AstToken, accessToken)
+	logger.AddSecret(accessToken)
 	cachedAccessToken = accessToken
-	cachedAccessTime = time.Now()
+	cachedAccessExpiry = expiry
+}
+
+// getTokenExpiry Read the expiry from the exp claim of the token, or assume the configured token lifetime
+func getTokenExpiry(accessToken string) time.Time {
+	if expiry, ok := tokencache.ExpiresAt(accessToken); ok {
+		return expiry
+	}
+	tokenExpirySeconds := viper.GetInt(commonParams.TokenExpirySecondsKey)
+	return time.Now().Add(time.Duration(tokenExpirySeconds) * time.Second)
+}
+
+func isTokenValid(accessToken string, expiry time.Time) bool {
+	return accessToken != "" && time.Now().Add(expiryGraceSeconds*time.Second).Before(expiry)
+}
+
+// getTokenCacheKey Identify the tokens of the credentials, which secret encrypts them on disk
+func getTokenCacheKey(accessKeyID, accessKeySecret, astAPIKey, authURI string) (cacheKey tokencache.Key, cacheSecret string) {
+	cacheKey = tokencache.Key{
+		Profile:  configuration.GetProfile(),
+		Tenant:   viper.GetString(commonParams.TenantKey),
+		ClientID: accessKeyID,
+		AuthURI:  authURI,
+	}
+	cacheSecret = accessKeySecret
+	if astAPIKey != "" {
+		cacheKey.ClientID = apiKeyClientID
+		cacheSecret = astAPIKey
+	}
+	return cacheKey, cacheSecret
+}
+
+func getTokenCache() *tokencache.Cache {
+	if viper.GetBool(commonParams.TokenCacheDisabledKey) || isCassetteActive() {
+		return nil
+	}
+	cachePath := viper.GetString(commonParams.TokenCachePathKey)
+	if cachePath == "" {
+		configDir, err := configuration.GetConfigDir()
+		if err != nil {
+			logger.PrintIfVerbose(fmt.Sprintf("Token cache disabled: %v", err))
+			return nil
+		}
+		cachePath = filepath.Join(configDir, tokencache.FileName)
+	}
+	return tokencache.New(cachePath)
+}
+
+// getClientCredentialsFromDisk Read the token stored by a previous invocation, the cache failures being ignored
+func getClientCredentialsFromDisk(cacheKey tokencache.Key, cacheSecret string) string {
+	cache := getTokenCache()
+	if cache == nil {
+		return ""
+	}
+	logger.PrintIfVerbose("Checking token cache for API access token.")
+	accessToken, err := cache.Get(cacheKey, cacheSecret, time.Now().Add(expiryGraceSeconds*time.Second))
+	if err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Failed reading the token cache: %v", err))
+		return ""
+	}
+	if accessToken == "" {
+		logger.PrintIfVerbose("API access token not found in token cache!")
+		return ""
+	}
+	logger.PrintIfVerbose("Using API access token from token cache!")
+	writeCredentialsToCache(accessToken, getTokenExpiry(accessToken))
+	return accessToken
+}
+
+func writeCredentialsToDisk(cacheKey tokencache.Key, cacheSecret, accessToken string, expiry time.Time) {
+	cache := getTokenCache()
+	if cache == nil {
+		return
+	}
+	logger.PrintIfVerbose("Storing API access token to token cache.")
+	if err := cache.Put(cacheKey, cacheSecret, accessToken, expiry); err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Failed writing the token cache: %v", err))
+	}
 }
 
 func getNewToken(credentialsPayload, authServerURI string) (string, error) {
+	credentialsInfo, err := getNewTokenResponse(credentialsPayload, authServerURI)
+	if err != nil {
+		return "", err
+	}
+	return credentialsInfo.AccessToken, nil
+}
+
+// getNewTokenResponse Request tokens from the auth server, returning the refresh token along the access token
+func getNewTokenResponse(credentialsPayload, authServerURI string) (*ClientCredentialsInfo, error) {
 	payload := strings.NewReader(credentialsPayload)
 	req, err := http.NewRequest(http.MethodPost, authServerURI, payload)
 	setAgentName(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req = addReqMonitor(req)
 	req.Header.Add("content-type", "application/x-www-form-urlencoded")
 	clientTimeout := viper.GetUint(commonParams.ClientTimeoutKey)
 	client := GetClient(clientTimeout)
 
-	res, err := doPrivateRequest(client, req)
+	res, err := doPrivateRequest(client, req, isRepeatableGrant(credentialsPayload))
 	if err != nil {
 		authURL, _ := getAuthURI()
-		return "", errors.Errorf("%s %s", checkmarxURLError, authURL)
+		return nil, errors.Errorf("%s %s", checkmarxURLError, authURL)
 	}
 	if res.StatusCode == http.StatusBadRequest {
-		return "", errors.Errorf("%v %s \n", res.StatusCode, "Provided credentials are invalid")
+		return nil, errors.Errorf("%v %s \n", res.StatusCode, "Provided credentials are invalid")
 	}
 	if res.StatusCode == http.StatusNotFound {
-		return "", errors.Errorf("%v %s \n", res.StatusCode, "Provided Tenant Name is invalid")
+		return nil, errors.Errorf("%v %s \n", res.StatusCode, "Provided Tenant Name is invalid")
 	}
 	if res.StatusCode == http.StatusUnauthorized {
-		return "", errors.Errorf("%v %s \n", res.StatusCode, "Provided credentials are invalid")
+		return nil, errors.Errorf("%v %s \n", res.StatusCode, "Provided credentials are invalid")
 	}
 
 	body, _ := ioutil.ReadAll(res.Body)
@@ -480,10 +630,10 @@ func getNewToken(credentialsPayload, authServerURI string) (string, error) {
 		err = json.Unmarshal(body, &credentialsErr)
 
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		return "", errors.Errorf("%v %s %s", res.StatusCode, credentialsErr.Error, credentialsErr.Description)
+		return nil, errors.Errorf("%v %s %s", res.StatusCode, credentialsErr.Error, credentialsErr.Description)
 	}
 
 	defer func() {
@@ -493,11 +643,11 @@ func getNewToken(credentialsPayload, authServerURI string) (string, error) {
 	credentialsInfo := ClientCredentialsInfo{}
 	err = json.Unmarshal(body, &credentialsInfo)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	logger.PrintIfVerbose("Successfully retrieved API token.")
-	return credentialsInfo.AccessToken, nil
+	return &credentialsInfo, nil
 }
 
 func getCredentialsPayload(accessKeyID, accessKeySecret string) string {
@@ -518,8 +668,20 @@ func getPasswordCredentialsPayload(username, password, adminClientID, adminClien
 	)
 }
 
-func doPrivateRequest(client *http.Client, req *http.Request) (*http.Response, error) {
-	return request(client, req, false)
+// doPrivateRequest Send a request to the auth server, safe when repeating it can't consume a single use grant
+func doPrivateRequest(client *http.Client, req *http.Request, safe bool) (*http.Response, error) {
+	return requestWithRetries(client, req, false, safe)
+}
+
+// isRepeatableGrant The client credentials and password grants can be requested again, unlike the codes
+// and the refresh tokens that the auth server may rotate
+func isRepeatableGrant(credentialsPayload string) bool {
+	form, err := url.ParseQuery(credentialsPayload)
+	if err != nil {
+		return false
+	}
+	grantType := form.Get("grant_type")
+	return grantType == clientCredentialsGrant || grantType == passwordGrant
 }
 
 func doRequest(client *http.Client, req *http.Request) (*http.Response, error) {
@@ -527,11 +689,20 @@ func doRequest(client *http.Client, req *http.Request) (*http.Response, error) {
 }
 
 func request(client *http.Client, req *http.Request, responseBody bool) (*http.Response, error) {
+	return requestWithRetries(client, req, responseBody, false)
+}
+
+// requestWithRetries Send the request, repeating it on connection failures and retried statuses as the retry policy allows.
+// safe marks non-idempotent requests that can be repeated anyway, such as the token requests.
+func requestWithRetries(client *http.Client, req *http.Request, responseBody, safe bool) (*http.Response, error) {
 	var err error
 	var resp *http.Response
 	var body []byte
-	retryLimit := int(viper.GetUint(commonParams.RetryFlag))
-	retryWaitTimeSeconds := viper.GetUint(commonParams.RetryDelayFlag)
+	policy, err := getRetryPolicy()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(logger.CorrelationIDHeader, logger.CorrelationID())
 	logger.PrintRequest(req)
 	if req.Body != nil {
 		body, err = ioutil.ReadAll(req.Body)
@@ -539,30 +710,35 @@ func request(client *http.Client, req *http.Request, responseBody bool) (*http.R
 			return nil, err
 		}
 	}
-	// try starts at -1 as we always do at least one request, retryLimit can be 0
-	for try := -1; try < retryLimit; try++ {
+	statusRetryAllowed := policy.allowsStatusRetry(req, safe)
+	for attempt := 0; ; attempt++ {
 		if body != nil {
 			_ = req.Body.Close()
-			req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+			req.Body = newRequestBody(req, body)
 		}
-		logger.PrintIfVerbose(
-			fmt.Sprintf(
-				"Request attempt %d in %d",
-				try+tryPrintOffset, retryLimit+retryLimitPrintOffset,
-			),
-		)
+		logger.Debug(fmt.Sprintf("Request attempt %d in %d", attempt+1, policy.retries+1), requestFields(req, attempt))
+		start := time.Now()
 		resp, err = client.Do(req)
+		logRequestFinished(req, attempt, resp, err, time.Since(start))
 		if err != nil {
-			logger.PrintIfVerbose(err.Error())
+			if attempt >= policy.retries {
+				return nil, err
+			}
+			wait := policy.backoff(attempt)
+			logRetry(attempt, err.Error(), wait)
+			retrySleep(wait)
+			continue
 		}
-		if resp != nil && err == nil {
+		retries, retried := policy.retriesFor(resp.StatusCode)
+		if !retried || !statusRetryAllowed || attempt >= retries {
 			logger.PrintResponse(resp, responseBody)
 			return resp, nil
 		}
-		logger.PrintIfVerbose(fmt.Sprintf("Request failed in attempt %d", try+tryPrintOffset))
-		time.Sleep(time.Duration(retryWaitTimeSeconds) * time.Second)
+		wait := policy.delay(attempt, resp)
+		discardResponse(resp)
+		logRetry(attempt, resp.Status, wait)
+		retrySleep(wait)
 	}
-	return nil, err
 }
 
 func getAuthURI() (string, error) {
@@ -115,19 +115,38 @@ func SetConfigProperty(propName, propValue string) {
 }
 
 func LoadConfiguration() {
-	usr, err := user.Current()
+	fullPath, err := GetConfigDir()
 	if err != nil {
 		log.Fatal("Cannot file home directory.", err)
 	}
-	fullPath := usr.HomeDir + configDirName
 	verifyConfigDir(fullPath)
+	activeProfile = findProfile(os.Args[1:], fullPath)
 	viper.AddConfigPath(fullPath)
-	configFile := "checkmarxcli"
-	viper.SetConfigName(configFile)
-	viper.SetConfigType("yaml")
+	viper.SetConfigName(profileConfigName(activeProfile))
+	viper.SetConfigType(configFileType)
 	_ = viper.ReadInConfig()
 }
 
+// configDirOverride The configuration directory used instead of the one of the home directory, set by the tests
+var configDirOverride string
+
+// SetConfigDir Use the directory instead of the one of the home directory, the empty string restoring it
+func SetConfigDir(dir string) {
+	configDirOverride = dir
+}
+
+// GetConfigDir The directory of the configuration file and of the token cache
+func GetConfigDir() (string, error) {
+	if configDirOverride != "" {
+		return configDirOverride, nil
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return usr.HomeDir + configDirName, nil
+}
+
 func verifyConfigDir(fullPath string) {
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 		fmt.Println("Creating directory")
@@ -141,6 +160,8 @@ func verifyConfigDir(fullPath string) {
 func ShowConfiguration() {
 	fmt.Println("Current Effective Configuration")
 
+	fmt.Printf("%30v", "Profile: ")
+	fmt.Println(GetProfile())
 	fmt.Printf("%30v", "BaseURI: ")
 	fmt.Println(viper.GetString(params.BaseURIKey))
 	fmt.Printf("%30v", "BaseAuthURIKey: ")
@@ -0,0 +1,327 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	configFileName        = "checkmarxcli"
+	configFileType        = "yaml"
+	profileFileSeparator  = "_"
+	activeProfileFileName = "active-profile"
+	filePermissions       = 0600
+)
+
+var profileNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// activeProfile The profile loaded by LoadConfiguration
+var activeProfile string
+
+// ProfileProperties The properties of a profile shown by the profile commands
+var ProfileProperties = []string{
+	params.BaseURIKey,
+	params.BaseAuthURIKey,
+	params.TenantKey,
+	params.AccessKeyIDConfigKey,
+	params.AccessKeySecretConfigKey,
+	params.AstAPIKey,
+}
+
+// GetProfile The profile of the configuration in use
+func GetProfile() string {
+	if activeProfile != "" {
+		return activeProfile
+	}
+	if profile := viper.GetString(params.ProfileFlag); profile != "" {
+		return profile
+	}
+	return params.Profile
+}
+
+// findProfile The profile of the --profile argument, or the one selected with cx configure profile use.
+// The arguments are read directly because the configuration is loaded before the flags are parsed.
+func findProfile(args []string, configDir string) string {
+	for i, arg := range args {
+		if arg == "--"+params.ProfileFlag && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, "--"+params.ProfileFlag+"=") {
+			return strings.TrimPrefix(arg, "--"+params.ProfileFlag+"=")
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(configDir, activeProfileFileName)); err == nil {
+		if profile := strings.TrimSpace(string(content)); profile != "" {
+			return profile
+		}
+	}
+	return params.Profile
+}
+
+func profileConfigName(profile string) string {
+	if profile == params.Profile {
+		return configFileName
+	}
+	return configFileName + profileFileSeparator + profile
+}
+
+func profileConfigPath(configDir, profile string) string {
+	return filepath.Join(configDir, profileConfigName(profile)+"."+configFileType)
+}
+
+func validateProfileName(profile string) error {
+	if !profileNameRegexp.MatchString(profile) {
+		return errors.Errorf("Invalid profile name %s: use letters, digits, '.', '_' and '-'", profile)
+	}
+	return nil
+}
+
+func profileExists(configDir, profile string) bool {
+	_, err := os.Stat(profileConfigPath(configDir, profile))
+	return err == nil
+}
+
+// ListProfiles The profiles having a configuration file, the default one always included
+func ListProfiles() ([]string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(configDir, configFileName+"*."+configFileType))
+	if err != nil {
+		return nil, err
+	}
+	profiles := []string{params.Profile}
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), "."+configFileType)
+		if strings.HasPrefix(name, configFileName+profileFileSeparator) {
+			profiles = append(profiles, strings.TrimPrefix(name, configFileName+profileFileSeparator))
+		}
+	}
+	sort.Strings(profiles[1:])
+	return profiles, nil
+}
+
+// ReadProfile The properties of the configuration file of the profile
+func ReadProfile(profile string) (map[string]string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	if profile != params.Profile && !profileExists(configDir, profile) {
+		return nil, errors.Errorf("Profile %s doesn't exist", profile)
+	}
+	profileViper := viper.New()
+	profileViper.SetConfigFile(profileConfigPath(configDir, profile))
+	properties := make(map[string]string)
+	if err = profileViper.ReadInConfig(); err != nil && profileExists(configDir, profile) {
+		return nil, errors.Wrapf(err, "Failed to read profile %s", profile)
+	}
+	// Every property set with configure set, the shown ones being present even when unset
+	for _, property := range ProfileProperties {
+		properties[property] = ""
+	}
+	for _, property := range profileViper.AllKeys() {
+		properties[property] = profileViper.GetString(property)
+	}
+	return properties, nil
+}
+
+// CreateProfile Write the configuration file of a new profile with the given properties
+func CreateProfile(profile string, properties map[string]string) error {
+	if err := validateProfileName(profile); err != nil {
+		return err
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if profileExists(configDir, profile) {
+		return errors.Errorf("Profile %s already exists", profile)
+	}
+	verifyConfigDir(configDir)
+	profileViper := viper.New()
+	for property, value := range properties {
+		if value != "" {
+			profileViper.Set(property, value)
+		}
+	}
+	path := profileConfigPath(configDir, profile)
+	if err = profileViper.WriteConfigAs(path); err != nil {
+		return errors.Wrapf(err, "Failed to create profile %s", profile)
+	}
+	return os.Chmod(path, filePermissions)
+}
+
+// CopyProfile Create a profile with a copy of the configuration file of another one, keeping all its properties
+func CopyProfile(source, target string) error {
+	configDir, err := checkProfileTarget(source, target)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(profileConfigPath(configDir, source))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "Failed to read profile %s", source)
+	}
+	verifyConfigDir(configDir)
+	if err = os.WriteFile(profileConfigPath(configDir, target), content, filePermissions); err != nil {
+		return errors.Wrapf(err, "Failed to create profile %s", target)
+	}
+	return nil
+}
+
+// checkProfileTarget Check that the source profile exists and the target one can be created, returning the config directory
+func checkProfileTarget(source, target string) (string, error) {
+	if err := validateProfileName(target); err != nil {
+		return "", err
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if source != params.Profile && !profileExists(configDir, source) {
+		return "", errors.Errorf("Profile %s doesn't exist", source)
+	}
+	if target == params.Profile || profileExists(configDir, target) {
+		return "", errors.Errorf("Profile %s already exists", target)
+	}
+	return configDir, nil
+}
+
+// RenameProfile Move the configuration file of the profile, following it when it is the active one
+func RenameProfile(source, target string) error {
+	if source == params.Profile {
+		return errors.Errorf("The %s profile can't be renamed, copy it instead", params.Profile)
+	}
+	configDir, err := checkProfileTarget(source, target)
+	if err != nil {
+		return err
+	}
+	defaultProfile := findProfile(nil, configDir)
+	if err = os.Rename(profileConfigPath(configDir, source), profileConfigPath(configDir, target)); err != nil {
+		return errors.Wrapf(err, "Failed to rename profile %s", source)
+	}
+	if defaultProfile == source {
+		return UseProfile(target)
+	}
+	return nil
+}
+
+// DeleteProfile Remove the configuration file of the profile, falling back to the default profile when it was active
+func DeleteProfile(profile string) error {
+	if profile == params.Profile {
+		return errors.Errorf("The %s profile can't be deleted", params.Profile)
+	}
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if !profileExists(configDir, profile) {
+		return errors.Errorf("Profile %s doesn't exist", profile)
+	}
+	if err = os.Remove(profileConfigPath(configDir, profile)); err != nil {
+		return errors.Wrapf(err, "Failed to delete profile %s", profile)
+	}
+	if findProfile(nil, configDir) == profile {
+		return UseProfile(params.Profile)
+	}
+	return nil
+}
+
+// UseProfile Make the profile the one used without --profile
+func UseProfile(profile string) error {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	if profile != params.Profile && !profileExists(configDir, profile) {
+		return errors.Errorf("Profile %s doesn't exist", profile)
+	}
+	verifyConfigDir(configDir)
+	path := filepath.Join(configDir, activeProfileFileName)
+	if profile == params.Profile {
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(profile), filePermissions)
+}
+
+// ApplyProfile Use the properties of the profile, proxy and TLS settings included, instead of the loaded configuration
+func ApplyProfile(profile string) error {
+	properties, err := ReadProfile(profile)
+	if err != nil {
+		return err
+	}
+	for property, value := range properties {
+		viper.Set(property, value)
+	}
+	activeProfile = profile
+	return nil
+}
+
+// GetDefaultProfile The profile used without --profile
+func GetDefaultProfile() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return findProfile(nil, configDir), nil
+}
+
+// ProfileSummary The properties of a profile, with the secrets obfuscated
+type ProfileSummary struct {
+	Name        string
+	Default     bool
+	BaseURI     string `format:"name:Base URI"`
+	BaseAuthURI string `format:"name:Base auth URI"`
+	Tenant      string
+	AuthType    string `format:"name:Auth type"`
+	ClientID    string `format:"name:Client ID"`
+	Secret      string
+}
+
+// ListProfileSummaries Summarize every profile
+func ListProfileSummaries() ([]ProfileSummary, error) {
+	profiles, err := ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	defaultProfile, err := GetDefaultProfile()
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]ProfileSummary, 0, len(profiles))
+	for _, profile := range profiles {
+		properties, readErr := ReadProfile(profile)
+		if readErr != nil {
+			return nil, readErr
+		}
+		summary := ProfileSummary{
+			Name:        profile,
+			Default:     profile == defaultProfile,
+			BaseURI:     properties[params.BaseURIKey],
+			BaseAuthURI: properties[params.BaseAuthURIKey],
+			Tenant:      properties[params.TenantKey],
+			AuthType:    "None",
+		}
+		if properties[params.AstAPIKey] != "" {
+			summary.AuthType = "API key"
+			summary.Secret = obfuscateString(properties[params.AstAPIKey])
+		} else if properties[params.AccessKeyIDConfigKey] != "" {
+			summary.AuthType = "Client credentials"
+			summary.ClientID = properties[params.AccessKeyIDConfigKey]
+			summary.Secret = obfuscateString(properties[params.AccessKeySecretConfigKey])
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
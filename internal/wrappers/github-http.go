@@ -253,6 +253,9 @@ func get(client *http.Client, url string, target interface{}, queryParams map[st
 		}
 		req.URL.RawQuery = q.Encode()
 
+		if currentError = waitRateLimitReset(client, req.URL.Hostname()); currentError != nil {
+			return nil, currentError
+		}
 		logger.PrintRequest(req)
 		resp, currentError := client.Do(req)
 		if currentError != nil {
@@ -263,6 +266,12 @@ func get(client *http.Client, url string, target interface{}, queryParams map[st
 		}
 
 		logger.PrintResponse(resp, true)
+		if observeRateLimit(resp) {
+			closeBody(resp)
+			count++
+			err = errors.Errorf("Rate limit of %s exceeded", req.URL.Hostname())
+			continue
+		}
 
 		switch resp.StatusCode {
 		case http.StatusOK:
@@ -148,6 +148,9 @@ func getFromGitLab(
 		}
 		req.URL.RawQuery = q.Encode()
 
+		if currentError = waitRateLimitReset(client, req.URL.Hostname()); currentError != nil {
+			return nil, currentError
+		}
 		logger.PrintRequest(req)
 
 		resp, currentError := client.Do(req)
@@ -159,6 +162,12 @@ func getFromGitLab(
 		}
 
 		logger.PrintResponse(resp, true)
+		if observeRateLimit(resp) {
+			closeResponseBody(resp)
+			count++
+			err = errors.Errorf("Rate limit of %s exceeded", req.URL.Hostname())
+			continue
+		}
 
 		switch resp.StatusCode {
 		case http.StatusOK:
@@ -0,0 +1,156 @@
+package keyring
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Service The service the secrets of the CLI are stored under
+	Service = "checkmarx-ast-cli"
+	label   = "Checkmarx One CLI"
+	// darwinNotFoundCode The exit code of security when the item doesn't exist
+	darwinNotFoundCode = 44
+	// linuxNotFoundCode The exit code of secret-tool lookup, without error message, when the secret doesn't exist
+	linuxNotFoundCode = 1
+	// windowsNotFoundCode The exit code of the scripts when the credential doesn't exist
+	windowsNotFoundCode = 2
+	windowsVault        = "$ErrorActionPreference='Stop';" +
+		"[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime];" +
+		"$vault=New-Object Windows.Security.Credentials.PasswordVault;"
+)
+
+var (
+	ErrNotFound    = errors.New("Secret not found in the OS credential store")
+	ErrUnavailable = errors.New("No OS credential store")
+)
+
+// Store The secrets of the OS credential store: the Keychain on macOS, the Secret Service on Linux
+// and the Credential Manager on Windows
+type Store interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+// runner Run a command with its stdin, returning its output and exit code, an error when it failed
+type runner func(stdin, name string, args ...string) (output string, exitCode int, err error)
+
+// commandStore Run the credential store tools of the OS, the secrets going through stdin rather than the arguments
+type commandStore struct {
+	goos string
+	run  runner
+}
+
+type command struct {
+	name         string
+	args         []string
+	stdin        string
+	notFoundCode int
+}
+
+func New() Store {
+	return &commandStore{goos: runtime.GOOS, run: runCommand}
+}
+
+func (s *commandStore) Get(account string) (string, error) {
+	var cmd *command
+	switch s.goos {
+	case "darwin":
+		cmd = &command{name: "security", args: []string{"find-generic-password", "-s", Service, "-a", account, "-w"}, notFoundCode: darwinNotFoundCode}
+	case "linux":
+		cmd = &command{name: "secret-tool", args: []string{"lookup", "service", Service, "account", account}, notFoundCode: linuxNotFoundCode}
+	case "windows":
+		cmd = powershell(fmt.Sprintf(
+			"try{$c=$vault.Retrieve('%s','%s')}catch{exit %d};$c.RetrievePassword();[Console]::Out.Write($c.Password)",
+			Service, account, windowsNotFoundCode,
+		))
+	}
+	output, err := s.exec(cmd)
+	if err != nil {
+		return "", err
+	}
+	secret := strings.TrimRight(output, "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (s *commandStore) Set(account, secret string) error {
+	var cmd *command
+	switch s.goos {
+	case "darwin":
+		// The interactive mode reads the command from stdin, keeping the secret out of the process list
+		cmd = &command{name: "security", args: []string{"-i"},
+			stdin: fmt.Sprintf("add-generic-password -U -s %s -a %s -l %q -w %s\n", Service, account, label, secret)}
+	case "linux":
+		cmd = &command{name: "secret-tool", args: []string{"store", "--label", label, "service", Service, "account", account}, stdin: secret}
+	case "windows":
+		cmd = powershell(fmt.Sprintf(
+			"$vault.Add((New-Object Windows.Security.Credentials.PasswordCredential('%s','%s',[Console]::In.ReadLine())))",
+			Service, account,
+		))
+		cmd.stdin = secret + "\n"
+	}
+	_, err := s.exec(cmd)
+	return err
+}
+
+func (s *commandStore) Delete(account string) error {
+	var cmd *command
+	switch s.goos {
+	case "darwin":
+		cmd = &command{name: "security", args: []string{"delete-generic-password", "-s", Service, "-a", account}, notFoundCode: darwinNotFoundCode}
+	case "linux":
+		cmd = &command{name: "secret-tool", args: []string{"clear", "service", Service, "account", account}, notFoundCode: linuxNotFoundCode}
+	case "windows":
+		cmd = powershell(fmt.Sprintf("try{$vault.Remove($vault.Retrieve('%s','%s'))}catch{exit %d}", Service, account, windowsNotFoundCode))
+	}
+	_, err := s.exec(cmd)
+	if err == ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func powershell(script string) *command {
+	return &command{
+		name:         "powershell",
+		args:         []string{"-NoProfile", "-NonInteractive", "-Command", windowsVault + script},
+		notFoundCode: windowsNotFoundCode,
+	}
+}
+
+// exec Run the command, ErrNotFound when the secret doesn't exist and ErrUnavailable when the store can't be used
+func (s *commandStore) exec(cmd *command) (string, error) {
+	if cmd == nil {
+		return "", errors.Wrapf(ErrUnavailable, "unsupported system %s", s.goos)
+	}
+	output, exitCode, err := s.run(cmd.stdin, cmd.name, cmd.args...)
+	if err == nil {
+		return output, nil
+	}
+	if exitCode != 0 && exitCode == cmd.notFoundCode && (s.goos != "linux" || err.Error() == "") {
+		return "", ErrNotFound
+	}
+	return "", errors.Wrapf(ErrUnavailable, "%s: %v", cmd.name, err)
+}
+
+func runCommand(stdin, name string, args ...string) (output string, exitCode int, err error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return stdout.String(), exitErr.ExitCode(), errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), 0, err
+}
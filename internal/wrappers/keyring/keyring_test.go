@@ -0,0 +1,69 @@
+//go:build !integration
+
+package keyring
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+type fakeRun struct {
+	stdin, name string
+	args        []string
+}
+
+// fakeRunner Record the command and answer with the output, exit code and error message
+func fakeRunner(runs *[]fakeRun, output string, exitCode int, message string) runner {
+	return func(stdin, name string, args ...string) (string, int, error) {
+		*runs = append(*runs, fakeRun{stdin: stdin, name: name, args: args})
+		if exitCode == 0 && message == "" {
+			return output, 0, nil
+		}
+		return output, exitCode, errors.New(message)
+	}
+}
+
+func TestCommandStoreSecretsGoThroughStdin(t *testing.T) {
+	for _, goos := range []string{"darwin", "linux", "windows"} {
+		var runs []fakeRun
+		store := &commandStore{goos: goos, run: fakeRunner(&runs, "", 0, "")}
+		assert.NilError(t, store.Set("account", "s3cr3t"))
+		assert.Assert(t, strings.Contains(runs[0].stdin, "s3cr3t"), goos)
+		assert.Assert(t, !strings.Contains(strings.Join(runs[0].args, " "), "s3cr3t"), goos)
+	}
+}
+
+func TestCommandStoreGet(t *testing.T) {
+	var runs []fakeRun
+	store := &commandStore{goos: "linux", run: fakeRunner(&runs, "s3cr3t", 0, "")}
+	secret, err := store.Get("account")
+	assert.NilError(t, err)
+	assert.Equal(t, secret, "s3cr3t")
+	assert.Equal(t, runs[0].name, "secret-tool")
+	assert.DeepEqual(t, runs[0].args, []string{"lookup", "service", Service, "account", "account"})
+
+	store = &commandStore{goos: "darwin", run: fakeRunner(&runs, "s3cr3t\n", 0, "")}
+	secret, err = store.Get("account")
+	assert.NilError(t, err)
+	assert.Equal(t, secret, "s3cr3t")
+}
+
+func TestCommandStoreNotFoundAndUnavailable(t *testing.T) {
+	var runs []fakeRun
+	for goos, code := range map[string]int{"darwin": darwinNotFoundCode, "linux": linuxNotFoundCode, "windows": windowsNotFoundCode} {
+		store := &commandStore{goos: goos, run: fakeRunner(&runs, "", code, "")}
+		_, err := store.Get("account")
+		assert.Equal(t, err, ErrNotFound, goos)
+		assert.NilError(t, store.Delete("account"), goos)
+	}
+
+	store := &commandStore{goos: "linux", run: fakeRunner(&runs, "", 1, "Cannot autolaunch D-Bus without X11 $DISPLAY")}
+	_, err := store.Get("account")
+	assert.ErrorContains(t, err, "secret-tool: Cannot autolaunch D-Bus without X11 $DISPLAY: No OS credential store")
+
+	store = &commandStore{goos: "plan9", run: fakeRunner(&runs, "", 0, "")}
+	assert.ErrorContains(t, store.Set("account", "s3cr3t"), "unsupported system plan9: No OS credential store")
+}
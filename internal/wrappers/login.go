@@ -0,0 +1,242 @@
+package wrappers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers/configuration"
+	"github.com/checkmarx/ast-cli/internal/wrappers/keyring"
+	"github.com/checkmarx/ast-cli/internal/wrappers/tokencache"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	LoginClientID      = apiKeyClientID
+	loginScope         = "openid"
+	loginCacheFileName = "login.cache"
+	loginKeyFileName   = "login.key"
+	// loginKeyAccountLength The length of the hash of the key path in the account of the login key
+	loginKeyAccountLength = 16
+	// loginCacheClientID Identify the stored login among the entries of the profile and tenant
+	loginCacheClientID = "cx-login"
+	usernameClaimKey   = "preferred_username"
+)
+
+// loginKeyring The OS credential store of the login key, replaced by the tests
+var loginKeyring = keyring.New()
+
+// LoginNeverExpires Expiry of the refresh tokens without one, such as offline tokens
+var LoginNeverExpires = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Login The refresh token of a user logged in with cx auth login
+type Login struct {
+	ClientID     string    `json:"clientId"`
+	Username     string    `json:"username,omitempty"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// NewLogin Build the login of the tokens returned by the auth server
+func NewLogin(clientID string, credentialsInfo *ClientCredentialsInfo) *Login {
+	login := &Login{ClientID: clientID, RefreshToken: credentialsInfo.RefreshToken, ExpiresAt: LoginNeverExpires}
+	logger.AddSecret(login.RefreshToken)
+	if credentialsInfo.RefreshExpiresIn > 0 {
+		login.ExpiresAt = time.Now().Add(time.Duration(credentialsInfo.RefreshExpiresIn) * time.Second)
+	}
+	login.Username, _ = extractFromTokenClaims(credentialsInfo.AccessToken, usernameClaimKey)
+	return login
+}
+
+// getLoginStore The login is encrypted with a random key kept in the OS credential store, so that reading
+// the configuration directory isn't enough to use the refresh token. Without create, the store is nil until
+// a login created the key.
+func getLoginStore(create bool) (store *tokencache.Cache, key tokencache.Key, secret string, err error) {
+	cacheDir := filepath.Dir(viper.GetString(commonParams.TokenCachePathKey))
+	if viper.GetString(commonParams.TokenCachePathKey) == "" {
+		cacheDir, err = configuration.GetConfigDir()
+		if err != nil {
+			return nil, key, "", err
+		}
+	}
+	secret, err = getLoginKey(filepath.Join(cacheDir, loginKeyFileName), create)
+	if err != nil || secret == "" {
+		return nil, key, "", err
+	}
+	key = tokencache.Key{
+		Profile:  configuration.GetProfile(),
+		Tenant:   viper.GetString(commonParams.TenantKey),
+		ClientID: loginCacheClientID,
+	}
+	return tokencache.New(filepath.Join(cacheDir, loginCacheFileName)), key, secret, nil
+}
+
+// getLoginKey Read the key of the login from the OS credential store, moving there the key file of the
+// previous versions. The key file, readable by the user only, is used when the store is unavailable or
+// when CX_LOGIN_KEY_STORE is file: whoever can read the user's files can then decrypt the login.
+func getLoginKey(keyPath string, create bool) (string, error) {
+	if viper.GetString(commonParams.LoginKeyStoreKey) == commonParams.LoginKeyStoreFile {
+		return loadLoginKeyFile(keyPath, create)
+	}
+	account := loginKeyAccount(keyPath)
+	secret, err := loginKeyring.Get(account)
+	if err == nil {
+		return secret, nil
+	}
+	if err != keyring.ErrNotFound {
+		return loginKeyFileFallback(keyPath, create, err)
+	}
+	if legacy, readErr := os.ReadFile(keyPath); readErr == nil && len(legacy) > 0 {
+		if storeLoginKey(account, string(legacy)) == nil {
+			_ = os.Remove(keyPath)
+		}
+		return string(legacy), nil
+	}
+	if !create {
+		return "", nil
+	}
+	secret, err = tokencache.NewSecret()
+	if err != nil {
+		return "", err
+	}
+	if err = storeLoginKey(account, secret); err != nil {
+		return loginKeyFileFallback(keyPath, create, err)
+	}
+	return secret, nil
+}
+
+// storeLoginKey Store the key in the OS credential store, reading it back as some stores drop secrets silently
+func storeLoginKey(account, secret string) error {
+	if err := loginKeyring.Set(account, secret); err != nil {
+		return err
+	}
+	stored, err := loginKeyring.Get(account)
+	if err != nil {
+		return err
+	}
+	if stored != secret {
+		return errors.Wrap(keyring.ErrUnavailable, "the stored login key doesn't match")
+	}
+	return nil
+}
+
+func loginKeyFileFallback(keyPath string, create bool, storeErr error) (string, error) {
+	if _, statErr := os.Stat(keyPath); create && os.IsNotExist(statErr) {
+		logger.Printf(
+			"The OS credential store is unavailable (%v): the login key is stored in %s, readable by the user only. "+
+				"Set %s=%s to store it there without trying the credential store.",
+			storeErr, keyPath, commonParams.LoginKeyStoreEnv, commonParams.LoginKeyStoreFile,
+		)
+	}
+	return loadLoginKeyFile(keyPath, create)
+}
+
+// loadLoginKeyFile Read the key file, creating it with create, empty when it doesn't exist
+func loadLoginKeyFile(keyPath string, create bool) (string, error) {
+	if _, statErr := os.Stat(keyPath); !create && os.IsNotExist(statErr) {
+		return "", nil
+	}
+	return tokencache.LoadOrCreateSecret(keyPath)
+}
+
+// loginKeyAccount Identify the key of the configuration directory in the OS credential store
+func loginKeyAccount(keyPath string) string {
+	if absPath, err := filepath.Abs(keyPath); err == nil {
+		keyPath = absPath
+	}
+	hash := sha256.Sum256([]byte(keyPath))
+	return "login-key-" + hex.EncodeToString(hash[:])[:loginKeyAccountLength]
+}
+
+func SaveLogin(login *Login) error {
+	store, key, secret, err := getLoginStore(true)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(login)
+	if err != nil {
+		return err
+	}
+	return store.Put(key, secret, string(content), login.ExpiresAt)
+}
+
+// LoadLogin Read the login of the profile and tenant, nil when the user isn't logged in
+func LoadLogin() (*Login, error) {
+	store, key, secret, err := getLoginStore(false)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	content, err := store.Get(key, secret, time.Now())
+	if err != nil || content == "" {
+		return nil, err
+	}
+	login := &Login{}
+	if err = json.Unmarshal([]byte(content), login); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the stored login")
+	}
+	logger.AddSecret(login.RefreshToken)
+	return login, nil
+}
+
+func DeleteLogin() error {
+	store, key, _, err := getLoginStore(false)
+	if err != nil || store == nil {
+		return err
+	}
+	return store.Delete(key)
+}
+
+// getLoginAccessToken Refresh the access token of the logged in user, storing the rotated refresh token
+func getLoginAccessToken(authURI string) (string, error) {
+	login, err := LoadLogin()
+	if err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Failed reading the stored login: %v", err))
+	}
+	if login == nil {
+		return "", errors.Errorf(fmt.Sprintf(FailedToAuth, "access key ID"))
+	}
+	logger.PrintIfVerbose("Using the login of the user.")
+	cacheKey, cacheSecret := getTokenCacheKey(login.ClientID, login.RefreshToken, "", authURI)
+	if accessToken := getClientCredentialsFromDisk(cacheKey, cacheSecret); accessToken != "" {
+		return accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"refresh_token"}, "client_id": {login.ClientID}, "refresh_token": {login.RefreshToken}}
+	credentialsInfo, err := getNewTokenResponse(form.Encode(), authURI)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to refresh the login, please run cx auth login")
+	}
+	logger.AddSecret(credentialsInfo.RefreshToken)
+	if credentialsInfo.RefreshToken != "" && credentialsInfo.RefreshToken != login.RefreshToken && !isReplaying() {
+		refreshed := NewLogin(login.ClientID, credentialsInfo)
+		if refreshed.Username == "" {
+			refreshed.Username = login.Username
+		}
+		if err = SaveLogin(refreshed); err != nil {
+			logger.PrintIfVerbose(fmt.Sprintf("Failed storing the refreshed login: %v", err))
+		}
+		cacheKey, cacheSecret = getTokenCacheKey(login.ClientID, refreshed.RefreshToken, "", authURI)
+	}
+	expiry := getTokenExpiry(credentialsInfo.AccessToken)
+	writeCredentialsToCache(credentialsInfo.AccessToken, expiry)
+	writeCredentialsToDisk(cacheKey, cacheSecret, credentialsInfo.AccessToken, expiry)
+	return credentialsInfo.AccessToken, nil
+}
+
+// getRealmURI The Keycloak realm of the tenant, holding the OpenID Connect endpoints
+func getRealmURI() (string, error) {
+	authURI, err := getAuthURI()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(authURI, "/"+BaseAuthURLSuffix), nil
+}
@@ -0,0 +1,154 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/checkmarx/ast-cli/internal/wrappers/keyring"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+// fakeKeyring An OS credential store in memory, unavailable with err
+type fakeKeyring struct {
+	secrets map[string]string
+	err     error
+}
+
+func (k *fakeKeyring) Get(account string) (string, error) {
+	if k.err != nil {
+		return "", k.err
+	}
+	secret, ok := k.secrets[account]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+	return secret, nil
+}
+
+func (k *fakeKeyring) Set(account, secret string) error {
+	if k.err != nil {
+		return k.err
+	}
+	k.secrets[account] = secret
+	return nil
+}
+
+func (k *fakeKeyring) Delete(account string) error {
+	delete(k.secrets, account)
+	return nil
+}
+
+// useTestLoginKeyring Store the login of the test in a temporary directory, its key in a fake credential store
+func useTestLoginKeyring(t *testing.T, storeErr error) (dir string, store *fakeKeyring) {
+	dir = t.TempDir()
+	store = &fakeKeyring{secrets: map[string]string{}, err: storeErr}
+	previous := loginKeyring
+	loginKeyring = store
+	viper.Set(commonParams.TokenCachePathKey, filepath.Join(dir, "tokens"))
+	t.Cleanup(func() {
+		loginKeyring = previous
+		viper.Set(commonParams.TokenCachePathKey, "")
+	})
+	return dir, store
+}
+
+func TestLoginKeyIsKeptInTheCredentialStore(t *testing.T) {
+	dir, store := useTestLoginKeyring(t, nil)
+	login, err := LoadLogin()
+	assert.NilError(t, err)
+	assert.Assert(t, login == nil)
+
+	assert.NilError(t, SaveLogin(&Login{ClientID: LoginClientID, RefreshToken: "stored-login", ExpiresAt: LoginNeverExpires}))
+	assert.Equal(t, len(store.secrets), 1)
+	_, err = os.Stat(filepath.Join(dir, loginKeyFileName))
+	assert.Assert(t, os.IsNotExist(err))
+	content, err := os.ReadFile(filepath.Join(dir, loginCacheFileName))
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(content), "stored-login"))
+
+	login, err = LoadLogin()
+	assert.NilError(t, err)
+	assert.Equal(t, login.RefreshToken, "stored-login")
+}
+
+func TestLoginKeyFileIsMovedToTheCredentialStore(t *testing.T) {
+	dir, store := useTestLoginKeyring(t, nil)
+	viper.Set(commonParams.LoginKeyStoreKey, commonParams.LoginKeyStoreFile)
+	assert.NilError(t, SaveLogin(&Login{ClientID: LoginClientID, RefreshToken: "stored-login", ExpiresAt: LoginNeverExpires}))
+	viper.Set(commonParams.LoginKeyStoreKey, commonParams.LoginKeyStoreKeychain)
+	assert.Equal(t, len(store.secrets), 0)
+	keyPath := filepath.Join(dir, loginKeyFileName)
+	_, err := os.Stat(keyPath)
+	assert.NilError(t, err)
+
+	login, err := LoadLogin()
+	assert.NilError(t, err)
+	assert.Equal(t, login.RefreshToken, "stored-login")
+	assert.Equal(t, len(store.secrets), 1)
+	_, err = os.Stat(keyPath)
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestLoginKeyFallsBackToAFileWithoutCredentialStore(t *testing.T) {
+	dir, _ := useTestLoginKeyring(t, keyring.ErrUnavailable)
+	assert.NilError(t, SaveLogin(&Login{ClientID: LoginClientID, RefreshToken: "stored-login", ExpiresAt: LoginNeverExpires}))
+	_, err := os.Stat(filepath.Join(dir, loginKeyFileName))
+	assert.NilError(t, err)
+
+	login, err := LoadLogin()
+	assert.NilError(t, err)
+	assert.Equal(t, login.RefreshToken, "stored-login")
+}
+
+func TestLoginRefreshTokensAreRedactedFromTheLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = io.WriteString(w, `{"access_token":"login-access-token","refresh_token":"rotated-refresh-token"}`)
+	}))
+	defer server.Close()
+	dir, _ := useTestLoginKeyring(t, nil)
+	logPath := filepath.Join(dir, "cx.log")
+	for key, value := range map[string]interface{}{
+		commonParams.BaseAuthURIKey:        server.URL,
+		commonParams.TenantKey:             "tenant",
+		commonParams.TokenExpirySecondsKey: 300,
+		commonParams.LogLevelKey:           "debug",
+		commonParams.LogFileKey:            logPath,
+	} {
+		viper.Set(key, value)
+	}
+	defer func() {
+		viper.Set(commonParams.BaseAuthURIKey, "")
+		viper.Set(commonParams.TenantKey, "")
+		viper.Set(commonParams.TokenExpirySecondsKey, 0)
+		viper.Set(commonParams.LogLevelKey, "")
+		viper.Set(commonParams.LogFileKey, "")
+		writeCredentialsToCache("", time.Time{})
+	}()
+	writeCredentialsToCache("", time.Time{})
+	assert.NilError(t, SaveLogin(&Login{ClientID: LoginClientID, RefreshToken: "stored-refresh-token", ExpiresAt: LoginNeverExpires}))
+
+	accessToken, err := GetAccessToken()
+	assert.NilError(t, err)
+	assert.Equal(t, accessToken, "login-access-token")
+	login, err := LoadLogin()
+	assert.NilError(t, err)
+	assert.Equal(t, login.RefreshToken, "rotated-refresh-token")
+	assert.NilError(t, NewAuthHTTPWrapper().RevokeLogin(login.ClientID, login.RefreshToken))
+
+	content, err := os.ReadFile(logPath)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), "refresh_token=***"), string(content))
+	assert.Assert(t, !strings.Contains(string(content), "stored-refresh-token"))
+	assert.Assert(t, !strings.Contains(string(content), "rotated-refresh-token"))
+}
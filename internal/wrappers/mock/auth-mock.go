@@ -1,6 +1,10 @@
 package mock
 
-import "github.com/checkmarx/ast-cli/internal/wrappers"
+import (
+	"net/url"
+
+	"github.com/checkmarx/ast-cli/internal/wrappers"
+)
 
 type AuthMockWrapper struct{}
 
@@ -14,3 +18,24 @@ func (a *AuthMockWrapper) SetPath(_ string) {
 func (a *AuthMockWrapper) ValidateLogin() error {
 	return nil
 }
+
+func (a *AuthMockWrapper) StartDeviceAuthorization(clientID string) (*wrappers.DeviceAuthorization, error) {
+	return &wrappers.DeviceAuthorization{
+		DeviceCode:      "MOCK",
+		UserCode:        "MOCK-CODE",
+		VerificationURI: "https://iam.mock/device",
+		ExpiresIn:       60,
+	}, nil
+}
+
+func (a *AuthMockWrapper) RequestLoginToken(form url.Values) (*wrappers.ClientCredentialsInfo, error) {
+	return &wrappers.ClientCredentialsInfo{AccessToken: "MOCK", RefreshToken: "MOCK", RefreshExpiresIn: 3600}, nil
+}
+
+func (a *AuthMockWrapper) GetAuthorizationURL(query url.Values) (string, error) {
+	return "https://iam.mock/auth?" + query.Encode(), nil
+}
+
+func (a *AuthMockWrapper) RevokeLogin(clientID, refreshToken string) error {
+	return nil
+}
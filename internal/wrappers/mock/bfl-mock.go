@@ -13,22 +13,33 @@ func (bfl *BflMockWrapper) GetBflByScanIDAndQueryID(params map[string]string) (
 	error,
 ) {
 	const mock = "MOCK"
+	const source = "SOURCE"
+	bflNode := &wrappers.ScanResultNode{
+		ID:         mock,
+		Column:     0,
+		FileName:   mock,
+		FullName:   mock,
+		Length:     0,
+		Line:       0,
+		MethodLine: 0,
+		Name:       mock,
+		DomType:    mock,
+	}
 	return &wrappers.BFLResponseModel{
 		ID: mock,
 		Trees: []wrappers.BFLTreeModel{
 			{
-				ID: mock,
-				BFL: &wrappers.ScanResultNode{
-					Column:     0,
-					FileName:   mock,
-					FullName:   mock,
-					Length:     0,
-					Line:       0,
-					MethodLine: 0,
-					Name:       mock,
-					DomType:    mock,
+				ID:  mock,
+				BFL: bflNode,
+				Results: []*wrappers.ScanResultData{
+					{QueryName: mock},
+					{QueryName: mock},
 				},
-				Results: nil,
+				Nodes: map[string]*wrappers.ScanResultNode{
+					source: {ID: source, FileName: source, Line: 1, Name: source},
+					mock:   bflNode,
+				},
+				NodesAdjacencyPairs: [][]string{{source, mock}},
 			},
 		},
 		TotalCount: 1,
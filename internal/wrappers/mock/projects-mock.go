@@ -22,6 +22,54 @@ func (p *ProjectsMockWrapper) Update(projectID string, model *wrappers.Project)
 	return nil
 }
 
+func (p *ProjectsMockWrapper) GetConfiguration(projectID string) ([]wrappers.ProjectConfiguration, *wrappers.ErrorModel, error) {
+	fmt.Println("Called GetConfiguration for project", projectID, "in ProjectsMockWrapper")
+	return []wrappers.ProjectConfiguration{
+		{
+			Key:             "scan.config.sast.presetName",
+			Name:            "presetName",
+			Category:        "sast",
+			OriginLevel:     "Tenant",
+			Value:           "Checkmarx Default",
+			ValueType:       "List",
+			ValueTypeParams: "Checkmarx Default,ASA Premium",
+			AllowOverride:   true,
+		},
+		{
+			Key:           "scan.config.sast.incremental",
+			Name:          "incremental",
+			Category:      "sast",
+			OriginLevel:   "Project",
+			Value:         "false",
+			ValueType:     "Bool",
+			AllowOverride: true,
+		},
+		{
+			Key:           "scan.config.sast.languages",
+			Name:          "languages",
+			Category:      "sast",
+			OriginLevel:   "Tenant",
+			Value:         "",
+			ValueType:     "String",
+			AllowOverride: false,
+		},
+		{
+			Key:           "scan.handler.git.sshKey",
+			Name:          "sshKey",
+			Category:      "git",
+			OriginLevel:   "Project",
+			Value:         "MOCK-KEY",
+			ValueType:     "Secret",
+			AllowOverride: true,
+		},
+	}, nil, nil
+}
+
+func (p *ProjectsMockWrapper) DeleteConfiguration(projectID string, keys []string) (*wrappers.ErrorModel, error) {
+	fmt.Println("Called DeleteConfiguration for project", projectID, "in ProjectsMockWrapper with the keys", keys)
+	return nil, nil
+}
+
 func (p *ProjectsMockWrapper) UpdateConfiguration(projectID string, configuration []wrappers.ProjectConfiguration) (*wrappers.ErrorModel, error) {
 	fmt.Println("Called Update Configuration for project", projectID, " in ProjectsMockWrapper with the configuration ", configuration)
 	return nil, nil
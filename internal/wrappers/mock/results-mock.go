@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/checkmarx/ast-cli/internal/wrappers"
@@ -54,9 +55,16 @@ func (r ResultsMockWrapper) GetAllResultsByScanID(_ map[string]string) (
 		TotalCount: 3,
 		Results: []*wrappers.ScanResult{
 			{
-				Type:     "sast",
-				Severity: "high",
+				Type:         "sast",
+				Severity:     "high",
+				SimilarityID: "MOCK",
+				FirstFoundAt: "2022-01-01T00:00:00Z",
+				VulnerabilityDetails: wrappers.VulnerabilityDetails{
+					CweID: float64(89),
+				},
 				ScanResultData: wrappers.ScanResultData{
+					QueryID:   json.Number("1234"),
+					QueryName: "Mock_Query",
 					Nodes: []*wrappers.ScanResultNode{
 						{
 							FileName: "dummy-file-name",
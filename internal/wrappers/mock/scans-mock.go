@@ -69,6 +69,7 @@ func (m *ScansMockWrapper) Get(_ map[string]string) (
 			{
 				ID:       "MOCK",
 				Status:   "STATUS",
+				Branch:   "master",
 				Metadata: metadata,
 				Engines:  engines,
 			},
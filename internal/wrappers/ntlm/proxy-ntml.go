@@ -7,11 +7,13 @@ import (
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/rc4"
 	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net"
@@ -34,11 +36,25 @@ import (
 type DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
 
 const (
-	expMsgBodyLen          = 40
-	ntmlChallengeLen       = 2
-	ntmlAuthHeaderLen      = 3
-	ntmlMakeLen            = 8
-	avIDMsvAvEOL      avID = iota
+	expMsgBodyLen     = 40
+	ntmlChallengeLen  = 2
+	ntmlAuthHeaderLen = 3
+	ntmlMakeLen       = 8
+	sessionKeyLen     = 16
+	lmV2ResponseLen   = 24
+	// msvAvFlagMICProvided Tell the server that the AUTHENTICATE message carries a MIC
+	msvAvFlagMICProvided = 0x00000002
+)
+
+// The AV_PAIR identifiers of the target info, https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-nlmp/83f5e789-660d-4781-8491-5f8c6641f75e
+const (
+	avIDMsvAvEOL avID = iota
+	avIDMsvAvNbComputerName
+	avIDMsvAvNbDomainName
+	avIDMsvAvDNSComputerName
+	avIDMsvAvDNSDomainName
+	avIDMsvAvDNSTreeName
+	avIDMsvAvFlags
 	avIDMsvAvTimestamp
 )
 
@@ -76,13 +92,19 @@ type varField struct {
 
 type authenticateMessageFields struct {
 	messageHeader
-	LmChallengeResponse varField
-	NtChallengeResponse varField
-	TargetName          varField
-	UserName            varField
-	Workstation         varField
-	_                   [8]byte
-	NegotiateFlags      negotiateFlags
+	LmChallengeResponse       varField
+	NtChallengeResponse       varField
+	TargetName                varField
+	UserName                  varField
+	Workstation               varField
+	EncryptedRandomSessionKey varField
+	NegotiateFlags            negotiateFlags
+}
+
+// authenticateMessageMIC The Version and MIC following the fields when the message is protected by a MIC
+type authenticateMessageMIC struct {
+	Version
+	MIC [sessionKeyLen]byte
 }
 
 type challengeMessageFields struct {
@@ -110,7 +132,8 @@ var defaultFlags = negotiateFlagNTLMSSPNEGOTIATETARGETINFO |
 	negotiateFlagNTLMSSPNEGOTIATEUNICODE |
 	negotiateFlagNTLMSSPNEGOTIATEEXTENDEDSESSIONSECURITY |
 	negotiateFlagNTLMSSPNEGOTIATENTLM |
-	negotiateFlagNTLMSSPNEGOTIATEALWAYSSIGN
+	negotiateFlagNTLMSSPNEGOTIATEALWAYSSIGN |
+	negotiateFlagNTLMSSPNEGOTIATEKEYEXCH
 
 type negotiateFlags uint32
 
@@ -155,11 +178,31 @@ func dialAndNegotiate(addr, proxyUsername, proxyPassword, proxyDomain string, ba
 		log.Printf("Could not call dial context with proxy: %s", err)
 		return conn, err
 	}
+	if proxyDomain == "" {
+		proxyDomain, proxyUsername = splitDomainUsername(proxyUsername)
+	}
+	if err = negotiate(conn, addr, proxyUsername, proxyPassword, proxyDomain); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// splitDomainUsername Split a DOMAIN\user username, the domain being empty for other usernames
+func splitDomainUsername(username string) (domain, user string) {
+	if i := strings.Index(username, "\\"); i > 0 {
+		return username[:i], username[i+1:]
+	}
+	return "", username
+}
+
+// negotiate Authenticate the connection to the proxy with NTLM, opening a tunnel to addr
+func negotiate(conn net.Conn, addr, proxyUsername, proxyPassword, proxyDomain string) error {
 	// NTLM Step 1: Send Negotiate Message
 	negotiateMessage, err := newNegotiateMessage(proxyDomain, "")
 	if err != nil {
 		log.Printf("Could not negotiate domain '%s': %s", proxyDomain, err)
-		return conn, err
+		return err
 	}
 	header := make(http.Header)
 	header.Set("Proxy-Authorization", fmt.Sprintf("NTLM %s", base64.StdEncoding.EncodeToString(negotiateMessage)))
@@ -173,40 +216,40 @@ func dialAndNegotiate(addr, proxyUsername, proxyPassword, proxyDomain string, ba
 	err = connect.Write(conn)
 	if err != nil {
 		log.Printf("Could not write negotiate message to proxy: %s", err)
-		return conn, err
+		return err
 	}
 	// NTLM Step 2: Receive Challenge Message
 	br := bufio.NewReader(conn)
 	resp, err := http.ReadResponse(br, connect)
 	if err != nil {
 		log.Printf("Could not read response from proxy: %s", err)
-		return conn, err
+		return err
 	}
 	_, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
 		log.Printf("Could not read response body from proxy: %s", err)
-		return conn, err
+		return err
 	}
 	_ = resp.Body.Close()
 	if resp.StatusCode != http.StatusProxyAuthRequired {
 		log.Printf("Expected %d as return status, got: %d", http.StatusProxyAuthRequired, resp.StatusCode)
-		return conn, errors.New(http.StatusText(resp.StatusCode))
+		return errors.New(http.StatusText(resp.StatusCode))
 	}
 	challenge := strings.Split(resp.Header.Get("Proxy-Authenticate"), " ")
 	if len(challenge) < ntmlChallengeLen {
 		log.Printf("The proxy did not return an NTLM challenge, got: '%s'", resp.Header.Get("Proxy-Authenticate"))
-		return conn, errors.New("no NTLM challenge received")
+		return errors.New("no NTLM challenge received")
 	}
 	challengeMessage, err := base64.StdEncoding.DecodeString(challenge[1])
 	if err != nil {
 		log.Printf("Could not base64 decode the NTLM challenge: %s", err)
-		return conn, err
+		return err
 	}
 	// NTLM Step 3: Send Authorization Message
-	authenticateMessage, err := processChallenge(challengeMessage, proxyUsername, proxyPassword)
+	authenticateMessage, err := processChallenge(negotiateMessage, challengeMessage, proxyUsername, proxyPassword, proxyDomain)
 	if err != nil {
 		log.Printf("Could not process the NTLM challenge: %s", err)
-		return conn, err
+		return err
 	}
 	header.Set("Proxy-Authorization", fmt.Sprintf("NTLM %s", base64.StdEncoding.EncodeToString(authenticateMessage)))
 	connect = &http.Request{
@@ -217,22 +260,20 @@ func dialAndNegotiate(addr, proxyUsername, proxyPassword, proxyDomain string, ba
 	}
 	if err = connect.Write(conn); err != nil {
 		log.Printf("Could not write authorization to proxy: %s", err)
-		return conn, err
+		return err
 	}
 	resp, err = http.ReadResponse(br, connect)
 	if err != nil {
 		log.Printf("Could not read response from proxy: %s", err)
-		_ = resp.Body.Close()
-		return conn, err
+		return err
 	}
+	_ = resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		log.Printf("Expected %d as return status, got: %d", http.StatusOK, resp.StatusCode)
-		_ = resp.Body.Close()
-		return conn, errors.New(http.StatusText(resp.StatusCode))
+		return errors.New(http.StatusText(resp.StatusCode))
 	}
 	// Succussfully authorized with NTLM
-	_ = resp.Body.Close()
-	return conn, nil
+	return nil
 }
 
 // NewNegotiateMessage creates a new NEGOTIATE message with the
@@ -358,49 +399,56 @@ func (m *authenicateMessage) MarshalBinary() ([]byte, error) {
 	workstation := toUnicode("go-ntlmssp")
 
 	ptr := binary.Size(&authenticateMessageFields{})
+	if m.MIC != nil {
+		ptr += binary.Size(&authenticateMessageMIC{})
+	}
 	f := authenticateMessageFields{
-		messageHeader:       newMessageHeader(ntmlAuthHeaderLen),
-		NegotiateFlags:      m.NegotiateFlags,
-		LmChallengeResponse: newVarField(&ptr, len(m.LmChallengeResponse)),
-		NtChallengeResponse: newVarField(&ptr, len(m.NtChallengeResponse)),
-		TargetName:          newVarField(&ptr, len(target)),
-		UserName:            newVarField(&ptr, len(user)),
-		Workstation:         newVarField(&ptr, len(workstation)),
+		messageHeader:             newMessageHeader(ntmlAuthHeaderLen),
+		NegotiateFlags:            m.NegotiateFlags,
+		LmChallengeResponse:       newVarField(&ptr, len(m.LmChallengeResponse)),
+		NtChallengeResponse:       newVarField(&ptr, len(m.NtChallengeResponse)),
+		TargetName:                newVarField(&ptr, len(target)),
+		UserName:                  newVarField(&ptr, len(user)),
+		Workstation:               newVarField(&ptr, len(workstation)),
+		EncryptedRandomSessionKey: newVarField(&ptr, len(m.EncryptedRandomSessionKey)),
 	}
 
-	f.NegotiateFlags.Unset(negotiateFlagNTLMSSPNEGOTIATEVERSION)
+	// The MIC is located after the version, which is only sent with it
+	if m.MIC == nil {
+		f.NegotiateFlags.Unset(negotiateFlagNTLMSSPNEGOTIATEVERSION)
+	} else {
+		f.NegotiateFlags |= negotiateFlagNTLMSSPNEGOTIATEVERSION
+	}
 
 	b := bytes.Buffer{}
 	err := binary.Write(&b, binary.LittleEndian, &f)
 	if err != nil {
 		return nil, err
 	}
-	err = binary.Write(&b, binary.LittleEndian, &m.LmChallengeResponse)
-	if err != nil {
-		return nil, err
-	}
-	err = binary.Write(&b, binary.LittleEndian, &m.NtChallengeResponse)
-	if err != nil {
-		return nil, err
-	}
-	err = binary.Write(&b, binary.LittleEndian, &target)
-	if err != nil {
-		return nil, err
-	}
-	err = binary.Write(&b, binary.LittleEndian, &user)
-	if err != nil {
-		return nil, err
+	if m.MIC != nil {
+		mic := authenticateMessageMIC{Version: DefaultVersion()}
+		copy(mic.MIC[:], m.MIC)
+		if err = binary.Write(&b, binary.LittleEndian, &mic); err != nil {
+			return nil, err
+		}
 	}
-	err = binary.Write(&b, binary.LittleEndian, &workstation)
-	if err != nil {
-		return nil, err
+	for _, payload := range [][]byte{m.LmChallengeResponse, m.NtChallengeResponse, target, user, workstation, m.EncryptedRandomSessionKey} {
+		if _, err = b.Write(payload); err != nil {
+			return nil, err
+		}
 	}
 	return b.Bytes(), nil
 }
 
+// micOffset The position of the MIC in the AUTHENTICATE message
+func micOffset() int {
+	return binary.Size(&authenticateMessageFields{}) + binary.Size(&Version{})
+}
+
 // ProcessChallenge crafts an AUTHENTICATE message in response to the CHALLENGE message
-// that was received from the server
-func processChallenge(challengeMessageData []byte, user, password string) ([]byte, error) {
+// that was received from the server. The domain defaults to the one of the challenge.
+// When the server sent a timestamp, the message is protected by a MIC over the three messages.
+func processChallenge(negotiateMessageData, challengeMessageData []byte, user, password, domain string) ([]byte, error) {
 	if user == "" && password == "" {
 		return nil, errors.New("anonymous authentication not supported")
 	}
@@ -413,17 +461,18 @@ func processChallenge(challengeMessageData []byte, user, password string) ([]byt
 	if cm.NegotiateFlags.Has(negotiateFlagNTLMSSPNEGOTIATELMKEY) {
 		return nil, errors.New("only NTLM v2 is supported, but server requested v1 (NTLMSSP_NEGOTIATE_LM_KEY)")
 	}
-	if cm.NegotiateFlags.Has(negotiateFlagNTLMSSPNEGOTIATEKEYEXCH) {
-		return nil, errors.New("key exchange requested but not supported (NTLMSSP_NEGOTIATE_KEY_EXCH)")
-	}
 
+	if domain == "" {
+		domain = cm.domain()
+	}
 	am := authenicateMessage{
 		UserName:       user,
-		TargetName:     cm.TargetName,
+		TargetName:     domain,
 		NegotiateFlags: cm.NegotiateFlags,
 	}
 
 	timestamp := cm.TargetInfo[avIDMsvAvTimestamp]
+	withMIC := timestamp != nil
 	if timestamp == nil { // no time sent, take current time
 		ft := uint64(time.Now().UnixNano()) / 100
 		ft += 116444736000000000 // add time between unix & windows offset
@@ -432,14 +481,44 @@ func processChallenge(challengeMessageData []byte, user, password string) ([]byt
 	}
 	clientChallenge := make([]byte, ntmlMakeLen)
 	_, _ = rand.Reader.Read(clientChallenge)
-	ntlmV2Hash := getNtlmV2Hash(password, user, cm.TargetName)
+	ntlmV2Hash := getNtlmV2Hash(password, user, domain)
+	targetInfo := cm.TargetInfoRaw
+	if withMIC {
+		targetInfo = cm.targetInfoWithFlags(msvAvFlagMICProvided)
+	}
 	am.NtChallengeResponse = computeNtlmV2Response(ntlmV2Hash,
-		cm.ServerChallenge[:], clientChallenge, timestamp, cm.TargetInfoRaw)
+		cm.ServerChallenge[:], clientChallenge, timestamp, targetInfo)
 	if cm.TargetInfoRaw == nil {
 		am.LmChallengeResponse = computeLmV2Response(ntlmV2Hash,
 			cm.ServerChallenge[:], clientChallenge)
+	} else if withMIC {
+		am.LmChallengeResponse = make([]byte, lmV2ResponseLen)
+	}
+
+	// The session key signs the MIC, exchanged encrypted with the key derived from the response when requested
+	exportedSessionKey := hmacMd5(ntlmV2Hash, am.NtChallengeResponse[:md5.Size])
+	if cm.NegotiateFlags.Has(negotiateFlagNTLMSSPNEGOTIATEKEYEXCH) {
+		keyExchangeKey := exportedSessionKey
+		exportedSessionKey = make([]byte, sessionKeyLen)
+		if _, err := rand.Reader.Read(exportedSessionKey); err != nil {
+			return nil, err
+		}
+		encryptedKey, err := rc4Encrypt(keyExchangeKey, exportedSessionKey)
+		if err != nil {
+			return nil, err
+		}
+		am.EncryptedRandomSessionKey = encryptedKey
 	}
-	return am.MarshalBinary()
+	if !withMIC {
+		return am.MarshalBinary()
+	}
+	am.MIC = make([]byte, sessionKeyLen)
+	message, err := am.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	copy(message[micOffset():], hmacMd5(exportedSessionKey, negotiateMessageData, challengeMessageData, message))
+	return message, nil
 }
 
 func (m challengeMessageFields) IsValid() bool {
@@ -451,6 +530,41 @@ type challengeMessage struct {
 	TargetName    string
 	TargetInfo    map[avID][]byte
 	TargetInfoRaw []byte
+	// targetInfoIDs The identifiers of the target info in the order the server sent them
+	targetInfoIDs []avID
+}
+
+// domain The NetBIOS domain of the target info, the target name without it
+func (m *challengeMessage) domain() string {
+	if value := m.TargetInfo[avIDMsvAvNbDomainName]; value != nil {
+		if domain, err := fromUnicode(value); err == nil && domain != "" {
+			return domain
+		}
+	}
+	return m.TargetName
+}
+
+// targetInfoWithFlags The target info of the challenge with the MsvAvFlags set, sent back in the NTLMv2 response
+func (m *challengeMessage) targetInfoWithFlags(flags uint32) []byte {
+	b := bytes.Buffer{}
+	writePair := func(id avID, value []byte) {
+		_ = binary.Write(&b, binary.LittleEndian, id)
+		_ = binary.Write(&b, binary.LittleEndian, uint16(len(value)))
+		b.Write(value)
+	}
+	if value := m.TargetInfo[avIDMsvAvFlags]; len(value) == binary.Size(flags) {
+		flags |= binary.LittleEndian.Uint32(value)
+	}
+	flagsValue := make([]byte, binary.Size(flags))
+	binary.LittleEndian.PutUint32(flagsValue, flags)
+	for _, id := range m.targetInfoIDs {
+		if id != avIDMsvAvFlags {
+			writePair(id, m.TargetInfo[id])
+		}
+	}
+	writePair(avIDMsvAvFlags, flagsValue)
+	writePair(avIDMsvAvEOL, nil)
+	return b.Bytes()
 }
 
 func (m *challengeMessage) UnmarshalBinary(data []byte) error {
@@ -494,14 +608,12 @@ func (m *challengeMessage) UnmarshalBinary(data []byte) error {
 				return err
 			}
 			value := make([]byte, l)
-			n, err := r.Read(value)
+			n, err := io.ReadFull(r, value)
 			if err != nil {
-				return err
-			}
-			if n != int(l) {
 				return fmt.Errorf("expected to read %d bytes, got only %d", l, n)
 			}
 			m.TargetInfo[id] = value
+			m.targetInfoIDs = append(m.targetInfoIDs, id)
 		}
 	}
 
@@ -536,6 +648,17 @@ func computeLmV2Response(ntlmV2Hash, serverChallenge, clientChallenge []byte) []
 	return append(hmacMd5(ntlmV2Hash, serverChallenge, clientChallenge), clientChallenge...)
 }
 
+func rc4Encrypt(key, data []byte) ([]byte, error) {
+	//nolint:gosec // RC4 is the cipher of the NTLM key exchange
+	cipher, err := rc4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]byte, len(data))
+	cipher.XORKeyStream(encrypted, data)
+	return encrypted, nil
+}
+
 func hmacMd5(key []byte, data ...[]byte) []byte {
 	mac := hmac.New(md5.New, key)
 	for _, d := range data {
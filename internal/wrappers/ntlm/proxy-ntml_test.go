@@ -0,0 +1,271 @@
+//go:build !integration
+
+package ntlm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const (
+	testUsername   = "user"
+	testPassword   = "password"
+	testDomain     = "CORP"
+	testTargetName = "PROXY-SERVER"
+	// ntlmV2BlobHeaderLen The fixed part of the NTLMv2 client blob preceding the target info
+	ntlmV2BlobHeaderLen = 28
+)
+
+// fakeNTLMProxy A proxy requiring NTLMv2 with a MIC, optionally with key exchange, tunneling the authenticated CONNECTs
+type fakeNTLMProxy struct {
+	listener    net.Listener
+	keyExchange bool
+
+	mu         sync.Mutex
+	handshakes int
+	domains    []string
+}
+
+func newFakeNTLMProxy(t *testing.T, keyExchange bool) *fakeNTLMProxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	proxy := &fakeNTLMProxy{listener: listener, keyExchange: keyExchange}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			go proxy.handle(conn)
+		}
+	}()
+	return proxy
+}
+
+func (p *fakeNTLMProxy) url() *url.URL {
+	return &url.URL{Scheme: "http", Host: p.listener.Addr().String()}
+}
+
+func (p *fakeNTLMProxy) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var negotiateData, challengeData []byte
+	for {
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+		message, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(req.Header.Get("Proxy-Authorization"), "NTLM "))
+		if len(message) < binary.Size(messageHeader{}) {
+			_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: NTLM\r\nContent-Length: 0\r\n\r\n")
+			return
+		}
+		switch binary.LittleEndian.Uint32(message[8:12]) {
+		case 1:
+			negotiateData, challengeData = message, p.challenge()
+			_, _ = fmt.Fprintf(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: NTLM %s\r\nContent-Length: 0\r\n\r\n",
+				base64.StdEncoding.EncodeToString(challengeData))
+		case ntmlAuthHeaderLen:
+			if err = p.verify(negotiateData, challengeData, message); err != nil {
+				_, _ = fmt.Fprintf(conn, "HTTP/1.1 407 Proxy Authentication Required\r\nX-Error: %s\r\nContent-Length: 0\r\n\r\n", err)
+				return
+			}
+			target, err := net.Dial("tcp", req.Host)
+			if err != nil {
+				return
+			}
+			defer target.Close()
+			_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+			go func() { _, _ = io.Copy(target, reader) }()
+			_, _ = io.Copy(conn, target)
+			return
+		}
+	}
+}
+
+// challenge A CHALLENGE message whose target info holds the NetBIOS domain and a timestamp, requiring a MIC
+func (p *fakeNTLMProxy) challenge() []byte {
+	targetInfo := bytes.Buffer{}
+	for _, pair := range []struct {
+		id    avID
+		value []byte
+	}{
+		{avIDMsvAvNbDomainName, toUnicode(testDomain)},
+		{avIDMsvAvNbComputerName, toUnicode("PROXY")},
+		{avIDMsvAvTimestamp, make([]byte, ntmlMakeLen)},
+		{avIDMsvAvEOL, nil},
+	} {
+		_ = binary.Write(&targetInfo, binary.LittleEndian, pair.id)
+		_ = binary.Write(&targetInfo, binary.LittleEndian, uint16(len(pair.value)))
+		targetInfo.Write(pair.value)
+	}
+	targetName := toUnicode(testTargetName)
+	flags := negotiateFlagNTLMSSPNEGOTIATEUNICODE | negotiateFlagNTLMSSPNEGOTIATENTLM |
+		negotiateFlagNTLMSSPNEGOTIATETARGETINFO | negotiateFlagNTLMSSPNEGOTIATEEXTENDEDSESSIONSECURITY
+	if p.keyExchange {
+		flags |= negotiateFlagNTLMSSPNEGOTIATEKEYEXCH
+	}
+	ptr := binary.Size(challengeMessageFields{})
+	fields := challengeMessageFields{
+		messageHeader:  newMessageHeader(2),
+		TargetName:     newVarField(&ptr, len(targetName)),
+		NegotiateFlags: flags,
+		TargetInfo:     newVarField(&ptr, targetInfo.Len()),
+	}
+	_, _ = rand.Read(fields.ServerChallenge[:])
+	message := bytes.Buffer{}
+	_ = binary.Write(&message, binary.LittleEndian, &fields)
+	message.Write(targetName)
+	message.Write(targetInfo.Bytes())
+	return message.Bytes()
+}
+
+// verify Check the NTLMv2 response, the MIC flag of the target info and the MIC signed with the exported session key
+func (p *fakeNTLMProxy) verify(negotiateData, challengeData, message []byte) error {
+	var cm challengeMessage
+	if err := cm.UnmarshalBinary(challengeData); err != nil {
+		return err
+	}
+	var fields authenticateMessageFields
+	if err := binary.Read(bytes.NewReader(message), binary.LittleEndian, &fields); err != nil {
+		return err
+	}
+	ntResponse, err := fields.NtChallengeResponse.ReadFrom(message)
+	if err != nil || len(ntResponse) < md5.Size+ntlmV2BlobHeaderLen {
+		return errors.New("invalid NT response")
+	}
+	domain, _ := fields.TargetName.ReadStringFrom(message, true)
+	user, _ := fields.UserName.ReadStringFrom(message, true)
+	encryptedKey, _ := fields.EncryptedRandomSessionKey.ReadFrom(message)
+	if user != testUsername {
+		return fmt.Errorf("unexpected user %s", user)
+	}
+
+	ntlmV2Hash := getNtlmV2Hash(testPassword, user, domain)
+	if !bytes.Equal(hmacMd5(ntlmV2Hash, cm.ServerChallenge[:], ntResponse[md5.Size:]), ntResponse[:md5.Size]) {
+		return errors.New("invalid NTLMv2 response")
+	}
+	if flags := avPairs(ntResponse[md5.Size+ntlmV2BlobHeaderLen:])[avIDMsvAvFlags]; len(flags) != 4 ||
+		binary.LittleEndian.Uint32(flags)&msvAvFlagMICProvided == 0 {
+		return errors.New("missing MIC flag")
+	}
+
+	exportedSessionKey := hmacMd5(ntlmV2Hash, ntResponse[:md5.Size])
+	if p.keyExchange {
+		if len(encryptedKey) != sessionKeyLen {
+			return errors.New("missing encrypted random session key")
+		}
+		exportedSessionKey, _ = rc4Encrypt(exportedSessionKey, encryptedKey)
+	}
+	if len(message) < micOffset()+sessionKeyLen {
+		return errors.New("missing MIC")
+	}
+	unsigned := append([]byte{}, message...)
+	copy(unsigned[micOffset():], make([]byte, sessionKeyLen))
+	if !bytes.Equal(hmacMd5(exportedSessionKey, negotiateData, challengeData, unsigned), message[micOffset():micOffset()+sessionKeyLen]) {
+		return errors.New("invalid MIC")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handshakes++
+	p.domains = append(p.domains, domain)
+	return nil
+}
+
+// avPairs The values of the AV pairs by id, up to MsvAvEOL
+func avPairs(data []byte) map[avID][]byte {
+	pairs := map[avID][]byte{}
+	for len(data) >= 4 {
+		id := avID(binary.LittleEndian.Uint16(data))
+		length := int(binary.LittleEndian.Uint16(data[2:]))
+		if id == avIDMsvAvEOL || len(data) < 4+length {
+			break
+		}
+		pairs[id] = data[4 : 4+length]
+		data = data[4+length:]
+	}
+	return pairs
+}
+
+// get Send the requests to the target through the proxy with a single transport
+func get(t *testing.T, proxy *fakeNTLMProxy, username, password, domain string, requests int) error {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "through the proxy")
+	}))
+	defer target.Close()
+	transport := &http.Transport{DialContext: NewNTLMProxyDialContext(&net.Dialer{}, proxy.url(), username, password, domain, nil)}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+	for i := 0; i < requests; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, target.URL, http.NoBody)
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.Equal(t, string(body), "through the proxy")
+	}
+	return nil
+}
+
+func TestNTLMProxyReusesAuthenticatedConnection(t *testing.T) {
+	proxy := newFakeNTLMProxy(t, true)
+
+	assert.NilError(t, get(t, proxy, testUsername, testPassword, "", 3))
+	assert.Equal(t, proxy.handshakes, 1)
+	assert.DeepEqual(t, proxy.domains, []string{testDomain})
+}
+
+func TestNTLMProxyWithoutKeyExchange(t *testing.T) {
+	proxy := newFakeNTLMProxy(t, false)
+
+	assert.NilError(t, get(t, proxy, testUsername, testPassword, "", 1))
+	assert.Equal(t, proxy.handshakes, 1)
+}
+
+func TestNTLMProxyConfiguredDomain(t *testing.T) {
+	proxy := newFakeNTLMProxy(t, true)
+
+	assert.NilError(t, get(t, proxy, testUsername, testPassword, "OTHER", 1))
+	assert.NilError(t, get(t, proxy, "DOMAIN\\"+testUsername, testPassword, "", 1))
+	assert.DeepEqual(t, proxy.domains, []string{"OTHER", "DOMAIN"})
+}
+
+func TestNTLMProxyWrongPassword(t *testing.T) {
+	proxy := newFakeNTLMProxy(t, true)
+
+	err := get(t, proxy, testUsername, "wrong", "", 1)
+	assert.ErrorContains(t, err, http.StatusText(http.StatusProxyAuthRequired))
+	assert.Equal(t, proxy.handshakes, 0)
+}
+
+func TestChallengeDomain(t *testing.T) {
+	var cm challengeMessage
+	assert.NilError(t, cm.UnmarshalBinary((&fakeNTLMProxy{}).challenge()))
+	assert.Equal(t, cm.TargetName, testTargetName)
+	assert.Equal(t, cm.domain(), testDomain)
+	assert.Equal(t, len(cm.TargetInfo[avIDMsvAvTimestamp]), ntmlMakeLen)
+
+	cm.TargetInfo = nil
+	assert.Equal(t, cm.domain(), testTargetName)
+}
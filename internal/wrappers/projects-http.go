@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -12,6 +13,11 @@ import (
 	commonParams "github.com/checkmarx/ast-cli/internal/params"
 )
 
+const (
+	projectConfigurationPath   = "api/configuration/project"
+	failedToParseConfiguration = "Failed to parse project configuration response"
+)
+
 type ProjectsHTTPWrapper struct {
 	path string
 }
@@ -56,6 +62,42 @@ func (p *ProjectsHTTPWrapper) Update(projectID string, model *Project) error {
 	}
 }
 
+func (p *ProjectsHTTPWrapper) GetConfiguration(projectID string) ([]ProjectConfiguration, *ErrorModel, error) {
+	clientTimeout := viper.GetUint(commonParams.ClientTimeoutKey)
+	params := map[string]string{
+		commonParams.ProjectIDFlag: projectID,
+	}
+
+	resp, err := SendHTTPRequestWithQueryParams(http.MethodGet, projectConfigurationPath, params, nil, clientTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusInternalServerError:
+		errorModel := ErrorModel{}
+		err = decoder.Decode(&errorModel)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, failedToParseConfiguration)
+		}
+		return nil, &errorModel, nil
+	case http.StatusOK:
+		var configuration []ProjectConfiguration
+		err = decoder.Decode(&configuration)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, failedToParseConfiguration)
+		}
+		return configuration, nil, nil
+	case http.StatusNotFound:
+		return nil, nil, errors.Errorf("project not found")
+	default:
+		return nil, nil, errors.Errorf("response status code %d", resp.StatusCode)
+	}
+}
+
 func (p *ProjectsHTTPWrapper) UpdateConfiguration(projectID string, configuration []ProjectConfiguration) (*ErrorModel, error) {
 	clientTimeout := viper.GetUint(commonParams.ClientTimeoutKey)
 	jsonBytes, err := json.Marshal(configuration)
@@ -67,7 +109,22 @@ func (p *ProjectsHTTPWrapper) UpdateConfiguration(projectID string, configuratio
 		commonParams.ProjectIDFlag: projectID,
 	}
 
-	resp, err := SendHTTPRequestWithQueryParams(http.MethodPatch, "api/configuration/project", params, bytes.NewBuffer(jsonBytes), clientTimeout)
+	resp, err := SendHTTPRequestWithQueryParams(http.MethodPatch, projectConfigurationPath, params, bytes.NewBuffer(jsonBytes), clientTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return handleProjectResponseWithNoBody(resp, err, http.StatusNoContent)
+}
+
+func (p *ProjectsHTTPWrapper) DeleteConfiguration(projectID string, keys []string) (*ErrorModel, error) {
+	clientTimeout := viper.GetUint(commonParams.ClientTimeoutKey)
+	params := map[string]string{
+		commonParams.ProjectIDFlag: projectID,
+		"config-keys":              strings.Join(keys, ","),
+	}
+
+	resp, err := SendHTTPRequestWithQueryParams(http.MethodDelete, projectConfigurationPath, params, nil, clientTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +181,9 @@ func (p *ProjectsHTTPWrapper) GetBranchesByID(projectID string, params map[strin
 
 	var request = "/branches?project-id=" + projectID
 
-	params["limit"] = limitValue
+	if _, ok := params["limit"]; !ok {
+		params["limit"] = limitValue
+	}
 	resp, err := SendHTTPRequestWithQueryParams(http.MethodGet, p.path+request, params, nil, clientTimeout)
 
 	if err != nil {
@@ -35,13 +35,14 @@ type ProjectResponseModel struct {
 }
 
 type ProjectConfiguration struct {
-	Key           string `json:"key"`
-	Name          string `json:"name"`
-	Category      string `json:"category"`
-	OriginLevel   string `json:"originLevel"`
-	Value         string `json:"value"`
-	ValueType     string `json:"valuetype"`
-	AllowOverride bool   `json:"allowOverride"`
+	Key             string `json:"key"`
+	Name            string `json:"name"`
+	Category        string `json:"category"`
+	OriginLevel     string `json:"originLevel"`
+	Value           string `json:"value"`
+	ValueType       string `json:"valuetype"`
+	ValueTypeParams string `json:"valuetypeparams,omitempty"`
+	AllowOverride   bool   `json:"allowOverride"`
 }
 
 type ProjectsWrapper interface {
@@ -52,5 +53,7 @@ type ProjectsWrapper interface {
 	GetBranchesByID(projectID string, params map[string]string) ([]string, *ErrorModel, error)
 	Delete(projectID string) (*ErrorModel, error)
 	Tags() (map[string][]string, *ErrorModel, error)
+	GetConfiguration(projectID string) ([]ProjectConfiguration, *ErrorModel, error)
 	UpdateConfiguration(projectID string, configuration []ProjectConfiguration) (*ErrorModel, error)
+	DeleteConfiguration(projectID string, keys []string) (*ErrorModel, error)
 }
@@ -0,0 +1,173 @@
+package wrappers
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	basicProxyToken   = "basic"
+	noneProxyToken    = "none"
+	httpProxyScheme   = "http"
+	httpsProxyScheme  = "https"
+	socks5ProxyScheme = "socks5"
+	schemeSeparator   = "://"
+	proxyBypassAll    = "*"
+	httpDefaultPort   = "80"
+	httpsDefaultPort  = "443"
+)
+
+// proxyConfig The proxy of the requests, nil url when they are sent directly
+type proxyConfig struct {
+	url      *url.URL
+	authType string
+	bypass   []proxyBypassRule
+}
+
+// proxyBypassRule A NO_PROXY entry: a domain with its subdomains, an IP or a CIDR, optionally restricted to a port
+type proxyBypassRule struct {
+	domain string
+	// subdomainsOnly The entry started with a dot, not matching the domain itself
+	subdomainsOnly bool
+	ip             net.IP
+	network        *net.IPNet
+	port           string
+	all            bool
+}
+
+// getProxyConfig Read --proxy, --proxy-auth-type and --proxy-bypass, rejecting the combinations the clients can't honor
+func getProxyConfig() (*proxyConfig, error) {
+	config := &proxyConfig{
+		authType: strings.ToLower(strings.TrimSpace(viper.GetString(commonParams.ProxyTypeKey))),
+		bypass:   parseProxyBypass(viper.GetString(commonParams.NoProxyKey)),
+	}
+	if config.authType == "" {
+		config.authType = basicProxyToken
+	}
+	if config.authType != basicProxyToken && config.authType != ntlmProxyToken && config.authType != noneProxyToken {
+		return nil, errors.Errorf(
+			"Invalid --%s %s: use %s, %s or %s",
+			commonParams.ProxyTypeFlag, config.authType, basicProxyToken, ntlmProxyToken, noneProxyToken,
+		)
+	}
+	proxyStr := strings.TrimSpace(viper.GetString(commonParams.ProxyKey))
+	if proxyStr == "" {
+		return config, nil
+	}
+	if !strings.Contains(proxyStr, schemeSeparator) {
+		proxyStr = httpProxyScheme + schemeSeparator + proxyStr
+	}
+	proxyURL, err := url.Parse(proxyStr)
+	if err != nil || proxyURL.Host == "" {
+		return nil, errors.Errorf("Invalid --%s: expected <scheme>://[<user>:<password>@]<host>:<port>", commonParams.ProxyFlag)
+	}
+	proxyURL.Scheme = strings.ToLower(proxyURL.Scheme)
+	switch proxyURL.Scheme {
+	case httpProxyScheme, httpsProxyScheme:
+	case socks5ProxyScheme:
+		if config.authType == ntlmProxyToken {
+			return nil, errors.Errorf("NTLM authentication requires an HTTP proxy, not %s", proxyURL.Scheme)
+		}
+	default:
+		return nil, errors.Errorf("Unsupported proxy scheme %s: use http, https or socks5", proxyURL.Scheme)
+	}
+	if config.authType == noneProxyToken {
+		proxyURL.User = nil
+	}
+	config.url = proxyURL
+	return config, nil
+}
+
+// parseProxyBypass Parse NO_PROXY entries separated by commas or spaces
+func parseProxyBypass(value string) []proxyBypassRule {
+	var rules []proxyBypassRule
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == proxyBypassAll {
+			rules = append(rules, proxyBypassRule{all: true})
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			rules = append(rules, proxyBypassRule{network: network})
+			continue
+		}
+		rule := proxyBypassRule{}
+		if host, port, err := net.SplitHostPort(entry); err == nil {
+			entry, rule.port = host, port
+		}
+		entry = strings.Trim(entry, "[]")
+		if ip := net.ParseIP(entry); ip != nil {
+			rule.ip = ip
+		} else {
+			entry = strings.TrimPrefix(entry, proxyBypassAll)
+			rule.subdomainsOnly = strings.HasPrefix(entry, ".")
+			rule.domain = strings.TrimPrefix(entry, ".")
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// bypasses Whether the address, host and port, is reached without the proxy
+func (c *proxyConfig) bypasses(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+	for _, rule := range c.bypass {
+		if rule.matches(host, port, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *proxyBypassRule) matches(host, port string, ip net.IP) bool {
+	if r.all {
+		return true
+	}
+	if r.port != "" && r.port != port {
+		return false
+	}
+	switch {
+	case r.network != nil:
+		return ip != nil && r.network.Contains(ip)
+	case r.ip != nil:
+		return ip != nil && r.ip.Equal(ip)
+	case r.domain == "":
+		return false
+	case host == r.domain:
+		return !r.subdomainsOnly
+	}
+	return strings.HasSuffix(host, "."+r.domain)
+}
+
+// proxyFunc The proxy of each request of the transport, none for the bypassed hosts
+func (c *proxyConfig) proxyFunc() func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if c.bypasses(requestAddr(req.URL)) {
+			return nil, nil
+		}
+		return c.url, nil
+	}
+}
+
+// requestAddr The host and port of the URL, the port defaulting to the one of the scheme
+func requestAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = httpDefaultPort
+		if u.Scheme == httpsProxyScheme {
+			port = httpsDefaultPort
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,241 @@
+package wrappers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+)
+
+const (
+	rateLimitHostsSeparator   = ","
+	rateLimitHostSeparator    = "="
+	rateLimitInFlightSelector = ":"
+	rateLimitRemainingHeader  = "RateLimit-Remaining"
+	rateLimitResetHeader      = "RateLimit-Reset"
+	// xRateLimitPrefix GitHub prefixes the rate limit headers, GitLab sends both forms
+	xRateLimitPrefix = "X-"
+)
+
+// hostLimit The requests per second and the requests in flight allowed to a host, 0 for no limit
+type hostLimit struct {
+	rate        float64
+	burst       int
+	maxInFlight int
+}
+
+// hostLimiter Throttle the requests sent to a host, shared by every client of the process
+type hostLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	// pausedUntil When the quota reported by the host is renewed
+	pausedUntil time.Time
+	inFlight    chan struct{}
+}
+
+var (
+	hostLimitersMu sync.Mutex
+	hostLimiters   = map[string]*hostLimiter{}
+)
+
+func newHostLimiter(limit hostLimit) *hostLimiter {
+	limiter := &hostLimiter{rate: limit.rate}
+	if limit.rate > 0 {
+		limiter.burst = float64(limit.burst)
+		if limiter.burst < 1 {
+			limiter.burst = math.Max(1, math.Ceil(limit.rate))
+		}
+		limiter.tokens = limiter.burst
+	}
+	if limit.maxInFlight > 0 {
+		limiter.inFlight = make(chan struct{}, limit.maxInFlight)
+	}
+	return limiter
+}
+
+// getHostLimiter The limiter of the host, created from the configuration on the first request to it
+func getHostLimiter(host string) *hostLimiter {
+	hostLimitersMu.Lock()
+	defer hostLimitersMu.Unlock()
+	limiter, ok := hostLimiters[host]
+	if !ok {
+		limiter = newHostLimiter(getHostLimit(host))
+		hostLimiters[host] = limiter
+	}
+	return limiter
+}
+
+func getHostLimit(host string) hostLimit {
+	limit := hostLimit{
+		rate:        viper.GetFloat64(commonParams.RateLimitKey),
+		burst:       viper.GetInt(commonParams.RateLimitBurstKey),
+		maxInFlight: viper.GetInt(commonParams.MaxInFlightKey),
+	}
+	hostLimits, err := parseHostLimits(viper.GetString(commonParams.RateLimitHostsKey))
+	if err != nil {
+		logger.PrintIfVerbose(fmt.Sprintf("Ignoring the rate limits of the hosts: %v", err))
+		return limit
+	}
+	if hostLimit, ok := hostLimits[strings.ToLower(host)]; ok {
+		hostLimit.burst = limit.burst
+		if hostLimit.maxInFlight < 0 {
+			hostLimit.maxInFlight = limit.maxInFlight
+		}
+		return hostLimit
+	}
+	return limit
+}
+
+// parseHostLimits Parse limits such as "api.github.com=5:2,gitlab.com=10", a missing max in flight being -1
+func parseHostLimits(value string) (map[string]hostLimit, error) {
+	limits := make(map[string]hostLimit)
+	for _, rule := range strings.Split(value, rateLimitHostsSeparator) {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		parts := strings.SplitN(rule, rateLimitHostSeparator, commonParams.KeyValuePairSize)
+		if len(parts) != commonParams.KeyValuePairSize || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid rule %s: expected <host>=<requests per second>[:<max in flight>]", rule)
+		}
+		limit := hostLimit{maxInFlight: -1}
+		values := strings.SplitN(parts[1], rateLimitInFlightSelector, commonParams.KeyValuePairSize)
+		rate, err := strconv.ParseFloat(strings.TrimSpace(values[0]), 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid requests per second in %s", rule)
+		}
+		limit.rate = rate
+		if len(values) == commonParams.KeyValuePairSize {
+			limit.maxInFlight, err = strconv.Atoi(strings.TrimSpace(values[1]))
+			if err != nil || limit.maxInFlight < 0 {
+				return nil, fmt.Errorf("invalid max in flight in %s", rule)
+			}
+		}
+		limits[strings.ToLower(strings.TrimSpace(parts[0]))] = limit
+	}
+	return limits, nil
+}
+
+// reserve Take a token of the bucket, returning how long to wait before sending the request
+func (l *hostLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var wait time.Duration
+	if l.rate <= 0 {
+		return wait
+	}
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	// The tokens go negative so that the waiting requests are spread at the rate
+	l.tokens--
+	if l.tokens < 0 {
+		if tokenWait := time.Duration(-l.tokens / l.rate * float64(time.Second)); tokenWait > wait {
+			wait = tokenWait
+		}
+	}
+	return wait
+}
+
+// pause Hold the requests to the host until the time it renews its quota
+func (l *hostLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+func (l *hostLimiter) getPausedUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.pausedUntil
+}
+
+// rateLimitSleep Wait for the quota of a host, replaced by the tests
+var rateLimitSleep = time.Sleep
+
+// waitRateLimitReset Wait until the host renews the quota it reported exhausted. The wait happens between the
+// requests, out of the timeout of the client, and fails at once when the reset is further away than that timeout.
+func waitRateLimitReset(client *http.Client, host string) error {
+	until := getHostLimiter(host).getPausedUntil()
+	wait := time.Until(until)
+	if wait <= 0 {
+		return nil
+	}
+	if client.Timeout > 0 && wait > client.Timeout {
+		return fmt.Errorf("rate limit of %s exhausted, it resets at %s", host, until.Format(time.RFC3339))
+	}
+	logger.PrintIfVerbose(fmt.Sprintf("Rate limit of %s exhausted, waiting %s", host, wait.Round(time.Second)))
+	rateLimitSleep(wait)
+	return nil
+}
+
+// rateLimitedTransport Apply the limiter of the host to every request
+type rateLimitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limiter := getHostLimiter(req.URL.Hostname())
+	if limiter.inFlight != nil {
+		select {
+		case limiter.inFlight <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		defer func() { <-limiter.inFlight }()
+	}
+	if wait := limiter.reserve(time.Now()); wait > 0 {
+		logger.PrintIfVerbose(fmt.Sprintf("Rate limit of %s reached, waiting %s", req.URL.Hostname(), wait.Round(time.Millisecond)))
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// observeRateLimit Pause the requests to the host of the response when it reports its quota exhausted,
+// the retry loops of the SCMs waiting for the reset with waitRateLimitReset. Returns true when the request was rejected for the rate limit and can be sent again.
+func observeRateLimit(resp *http.Response) bool {
+	remaining, reset, ok := parseRateLimitHeaders(resp.Header)
+	if !ok || remaining > 0 {
+		return false
+	}
+	logger.PrintIfVerbose(fmt.Sprintf("Rate limit of %s exhausted until %s", resp.Request.URL.Hostname(), reset.Format(time.RFC3339)))
+	getHostLimiter(resp.Request.URL.Hostname()).pause(reset)
+	return resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRateLimitHeaders Read the remaining requests and the reset time, an epoch in seconds, of X-RateLimit-* or RateLimit-* headers
+func parseRateLimitHeaders(header http.Header) (remaining int, reset time.Time, ok bool) {
+	for _, prefix := range []string{xRateLimitPrefix, ""} {
+		remainingValue := header.Get(prefix + rateLimitRemainingHeader)
+		resetValue := header.Get(prefix + rateLimitResetHeader)
+		if remainingValue == "" || resetValue == "" {
+			continue
+		}
+		remainingRequests, remainingErr := strconv.Atoi(remainingValue)
+		resetSeconds, resetErr := strconv.ParseInt(resetValue, 10, 64)
+		if remainingErr != nil || resetErr != nil {
+			continue
+		}
+		return remainingRequests, time.Unix(resetSeconds, 0), true
+	}
+	return 0, time.Time{}, false
+}
@@ -0,0 +1,169 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+// useTestRateLimits Configure the limits, dropping the limiters created by the other tests
+func useTestRateLimits(t *testing.T, rate float64, maxInFlight int, hosts string) {
+	viper.Set(commonParams.RateLimitKey, rate)
+	viper.Set(commonParams.MaxInFlightKey, maxInFlight)
+	viper.Set(commonParams.RateLimitHostsKey, hosts)
+	hostLimiters = map[string]*hostLimiter{}
+	t.Cleanup(func() {
+		viper.Set(commonParams.RateLimitKey, 0)
+		viper.Set(commonParams.MaxInFlightKey, 0)
+		viper.Set(commonParams.RateLimitHostsKey, "")
+		hostLimiters = map[string]*hostLimiter{}
+	})
+}
+
+func TestParseHostLimits(t *testing.T) {
+	limits, err := parseHostLimits("api.github.com=5:2, GitLab.com=0.5")
+	assert.NilError(t, err)
+	assert.Equal(t, len(limits), 2)
+	assert.Equal(t, limits["api.github.com"], hostLimit{rate: 5, maxInFlight: 2})
+	assert.Equal(t, limits["gitlab.com"], hostLimit{rate: 0.5, maxInFlight: -1})
+
+	_, err = parseHostLimits("api.github.com")
+	assert.ErrorContains(t, err, "invalid rule api.github.com")
+	_, err = parseHostLimits("api.github.com=fast")
+	assert.ErrorContains(t, err, "invalid requests per second")
+}
+
+func TestGetHostLimit(t *testing.T) {
+	useTestRateLimits(t, 10, 4, "api.github.com=1,gitlab.com=2:1")
+
+	assert.Equal(t, getHostLimit("example.com"), hostLimit{rate: 10, maxInFlight: 4})
+	assert.Equal(t, getHostLimit("api.github.com"), hostLimit{rate: 1, maxInFlight: 4})
+	assert.Equal(t, getHostLimit("gitlab.com"), hostLimit{rate: 2, maxInFlight: 1})
+}
+
+func TestHostLimiterReserve(t *testing.T) {
+	limiter := newHostLimiter(hostLimit{rate: 2, burst: 2})
+	now := time.Now()
+
+	assert.Equal(t, limiter.reserve(now), time.Duration(0))
+	assert.Equal(t, limiter.reserve(now), time.Duration(0))
+	assert.Equal(t, limiter.reserve(now), 500*time.Millisecond)
+	assert.Equal(t, limiter.reserve(now), time.Second)
+	assert.Equal(t, limiter.reserve(now.Add(2*time.Second)), time.Duration(0))
+}
+
+func TestWaitRateLimitReset(t *testing.T) {
+	useTestRateLimits(t, 0, 0, "")
+	var waits []time.Duration
+	rateLimitSleep = func(wait time.Duration) { waits = append(waits, wait) }
+	t.Cleanup(func() { rateLimitSleep = time.Sleep })
+	reset := time.Now().Add(time.Minute)
+	getHostLimiter("api.github.com").pause(reset)
+
+	assert.NilError(t, waitRateLimitReset(&http.Client{}, "gitlab.com"))
+	assert.NilError(t, waitRateLimitReset(&http.Client{Timeout: time.Hour}, "api.github.com"))
+	assert.Equal(t, len(waits), 1)
+	assert.Assert(t, waits[0] > 50*time.Second && waits[0] <= time.Minute)
+	assert.Equal(t, getHostLimiter("api.github.com").reserve(time.Now()), time.Duration(0))
+
+	err := waitRateLimitReset(&http.Client{Timeout: time.Second}, "api.github.com")
+	assert.ErrorContains(t, err, "rate limit of api.github.com exhausted, it resets at "+reset.Format(time.RFC3339))
+	assert.Equal(t, len(waits), 1)
+}
+
+func TestGetWaitsForTheRateLimitOutOfTheTimeout(t *testing.T) {
+	useTestRateLimits(t, 0, 0, "")
+	var waits []time.Duration
+	rateLimitSleep = func(wait time.Duration) { waits = append(waits, wait) }
+	t.Cleanup(func() { rateLimitSleep = time.Sleep })
+	reset := time.Now().Add(time.Minute)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+			w.Header().Set("X-Ratelimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"login":"checkmarx"}`))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &rateLimitedTransport{base: http.DefaultTransport}, Timeout: time.Hour}
+
+	target := map[string]string{}
+	_, err := get(client, server.URL, &target, map[string]string{})
+	assert.NilError(t, err)
+	assert.Equal(t, target["login"], "checkmarx")
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(2))
+	assert.Equal(t, len(waits), 1)
+
+	atomic.StoreInt32(&calls, 0)
+	client.Timeout = time.Second
+	_, err = get(client, server.URL, &target, map[string]string{})
+	assert.ErrorContains(t, err, "it resets at")
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(0))
+}
+
+func TestRateLimitedTransportMaxInFlight(t *testing.T) {
+	useTestRateLimits(t, 0, 2, "")
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &rateLimitedTransport{base: http.DefaultTransport}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Assert(t, atomic.LoadInt32(&maxInFlight) <= 2)
+}
+
+func TestObserveRateLimit(t *testing.T) {
+	useTestRateLimits(t, 0, 0, "")
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	requestURL, _ := url.Parse("https://api.github.com/orgs/checkmarx")
+	resp := &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header: http.Header{
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+		},
+		Request: &http.Request{URL: requestURL},
+	}
+
+	assert.Assert(t, observeRateLimit(resp))
+	assert.Equal(t, getHostLimiter("api.github.com").pausedUntil, reset)
+
+	resp.StatusCode = http.StatusOK
+	resp.Header = http.Header{"Ratelimit-Remaining": {"10"}, "Ratelimit-Reset": {"0"}}
+	assert.Assert(t, !observeRateLimit(resp))
+}
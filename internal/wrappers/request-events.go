@@ -0,0 +1,74 @@
+package wrappers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+)
+
+// uploadProgressSteps The number of progress events of a large request body
+const uploadProgressSteps = 10
+
+func requestFields(req *http.Request, attempt int) logger.Fields {
+	return logger.Fields{
+		"method":  req.Method,
+		"url":     req.URL.String(),
+		"attempt": attempt + 1,
+	}
+}
+
+// logRequestFinished The outcome of an attempt with its duration
+func logRequestFinished(req *http.Request, attempt int, resp *http.Response, err error, duration time.Duration) {
+	fields := requestFields(req, attempt)
+	fields["durationMs"] = duration.Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		logger.Debug(fmt.Sprintf("Request failed in %s: %s", duration, err), fields)
+		return
+	}
+	fields["status"] = resp.StatusCode
+	logger.Debug(fmt.Sprintf("Request finished in %s with %s", duration, resp.Status), fields)
+}
+
+// newRequestBody The body of an attempt, reporting the upload progress of the bodies too large for the logs
+func newRequestBody(req *http.Request, body []byte) io.ReadCloser {
+	reader := bytes.NewReader(body)
+	if len(body) <= logger.ContentLengthLimit {
+		return ioutil.NopCloser(reader)
+	}
+	return &uploadProgressReader{reader: reader, url: req.URL.String(), total: int64(len(body))}
+}
+
+// uploadProgressReader Log an event each time another tenth of the body is sent
+type uploadProgressReader struct {
+	reader *bytes.Reader
+	url    string
+	total  int64
+	sent   int64
+	step   int64
+}
+
+func (r *uploadProgressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+	if step := r.sent * uploadProgressSteps / r.total; step > r.step {
+		r.step = step
+		percent := r.sent * 100 / r.total
+		logger.Debug(fmt.Sprintf("Uploaded %d%% (%d of %d bytes)", percent, r.sent, r.total), logger.Fields{
+			"url":     r.url,
+			"bytes":   r.sent,
+			"total":   r.total,
+			"percent": percent,
+		})
+	}
+	return n, err
+}
+
+func (r *uploadProgressReader) Close() error {
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+func TestRequestEvents(t *testing.T) {
+	correlationIDs := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationIDs = append(correlationIDs, r.Header.Get(logger.CorrelationIDHeader))
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "cx.log")
+	viper.Set(commonParams.LogFormatKey, logger.JSONFormat)
+	viper.Set(commonParams.LogLevelKey, "debug")
+	viper.Set(commonParams.LogFileKey, path)
+	defer func() {
+		viper.Set(commonParams.LogFormatKey, logger.TextFormat)
+		viper.Set(commonParams.LogLevelKey, "")
+		viper.Set(commonParams.LogFileKey, "")
+	}()
+
+	body := bytes.Repeat([]byte("x"), logger.ContentLengthLimit+1)
+	resp, err := SendHTTPRequestByFullURL(http.MethodPut, server.URL+"/upload", bytes.NewReader(body), false, NoTimeout, "", false)
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+
+	assert.DeepEqual(t, correlationIDs, []string{logger.CorrelationID()})
+	content, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), `"msg":"Uploaded 100% (1000001 of 1000001 bytes)"`))
+	assert.Assert(t, strings.Contains(string(content), `"status":201`))
+	assert.Assert(t, strings.Contains(string(content), `"correlationId":"`+logger.CorrelationID()+`"`))
+}
@@ -0,0 +1,88 @@
+package wrappers
+
+const ResultsComplianceTemplate = `{{define "ComplianceTemplate"}}
+<!DOCTYPE html>
+<html lang="en">
+
+<head>
+    <meta http-equiv="Content-type" content="text/html; charset=utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Checkmarx Compliance Report</title>
+    <style type="text/css">
+        * {
+            box-sizing: border-box;
+            margin: 0;
+            padding: 0;
+        }
+
+        body {
+            color: #565360;
+            font-family: Roboto, Arial, sans-serif;
+            font-size: 13px;
+        }
+
+        .cx-main {
+            margin: 2rem auto;
+            width: 90%;
+        }
+
+        .header-row {
+            display: flex;
+            justify-content: center;
+            margin-bottom: 2rem;
+        }
+
+        .header-row .data {
+            margin-right: 20px;
+        }
+
+        .element {
+            -webkit-box-shadow: 0 2px 4px rgba(0, 0, 0, 0.15);
+            background: #fff;
+            border: 1px solid #dad8dc;
+            border-radius: 4px;
+            box-shadow: 0 2px 4px rgba(0, 0, 0, 0.15);
+            padding: 1rem;
+        }
+
+        table {
+            border-collapse: collapse;
+            width: 100%;
+        }
+
+        th, td {
+            border-bottom: 1px solid #dad8dc;
+            padding: 6px;
+            text-align: left;
+        }
+
+        .Passed {
+            color: #0fcdc2;
+            font-weight: 700;
+        }
+
+        .Failed {
+            color: #f1605d;
+            font-weight: 700;
+        }
+    </style>
+</head>
+
+<body>
+    <div class="cx-main">
+        <div class="header-row">
+            <div class="data">Framework: {{.Framework}}</div>
+            <div class="data">Scan ID: {{.ScanID}}</div>
+        </div>
+        <div class="element">
+            <table>
+                <tr><th>Category</th><th>Name</th><th>High</th><th>Medium</th><th>Low</th><th>Info</th><th>Total</th><th>Status</th></tr>
+                {{range .Categories}}<tr><td>{{.Category}}</td><td>{{.Name}}</td><td>{{.High}}</td><td>{{.Medium}}</td><td>{{.Low}}</td><td>{{.Info}}</td><td>{{.Total}}</td><td class="{{.Status}}">{{.Status}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+    </div>
+</body>
+</html>
+{{end}}
+`
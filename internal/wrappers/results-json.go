@@ -39,6 +39,7 @@ type ScanResult struct {
 	ScanResultData       ScanResultData       `json:"data,omitempty"`
 	Comments             ResultComments       `json:"comments,omitempty"`
 	VulnerabilityDetails VulnerabilityDetails `json:"vulnerabilityDetails,omitempty"`
+	Owners               []string             `json:"owners,omitempty"`
 }
 
 type ResultComments struct {
@@ -66,19 +67,32 @@ type VulnerabilityCVSS struct {
 }
 
 type ScanResultNode struct {
-	ID          string `json:"id,omitempty"`
-	Line        uint   `json:"line"`
-	Name        string `json:"name,omitempty"`
-	Column      uint   `json:"column"`
-	Length      uint   `json:"length,omitempty"`
-	Method      string `json:"method,omitempty"`
-	NodeID      int    `json:"nodeID,omitempty"`
-	DomType     string `json:"domType,omitempty"`
-	FileName    string `json:"fileName,omitempty"`
-	FullName    string `json:"fullName,omitempty"`
-	TypeName    string `json:"typeName,omitempty"`
-	MethodLine  uint   `json:"methodLine,omitempty"`
-	Definitions string `json:"definitions,omitempty"`
+	ID          string             `json:"id,omitempty"`
+	Line        uint               `json:"line"`
+	Name        string             `json:"name,omitempty"`
+	Column      uint               `json:"column"`
+	Length      uint               `json:"length,omitempty"`
+	Method      string             `json:"method,omitempty"`
+	NodeID      int                `json:"nodeID,omitempty"`
+	DomType     string             `json:"domType,omitempty"`
+	FileName    string             `json:"fileName,omitempty"`
+	FullName    string             `json:"fullName,omitempty"`
+	TypeName    string             `json:"typeName,omitempty"`
+	MethodLine  uint               `json:"methodLine,omitempty"`
+	Definitions string             `json:"definitions,omitempty"`
+	Snippet     *ScanResultSnippet `json:"snippet,omitempty"`
+}
+
+// ScanResultSnippet The lines of the local checkout around a result location
+type ScanResultSnippet struct {
+	Lines []SourceLine `json:"lines,omitempty"`
+	// Drifted The local content doesn't match the scanned revision
+	Drifted bool `json:"drifted,omitempty"`
+}
+
+type SourceLine struct {
+	Number uint   `json:"number"`
+	Text   string `json:"text"`
 }
 
 type ScanResultPackageData struct {
@@ -101,10 +115,11 @@ type ScanResultData struct {
 	ScaPackageCollection *ScaPackageCollection    `json:"scaPackageData,omitempty"`
 	RecommendedVersion   interface{}              `json:"recommendedVersion,omitempty"`
 	// Added to support kics results
-	Line          uint   `json:"line,omitempty"`
-	Platform      string `json:"platform,omitempty"`
-	IssueType     string `json:"issueType,omitempty"`
-	ExpectedValue string `json:"expectedValue,omitempty"`
-	Value         string `json:"value,omitempty"`
-	Filename      string `json:"filename,omitempty"`
+	Line          uint               `json:"line,omitempty"`
+	Platform      string             `json:"platform,omitempty"`
+	IssueType     string             `json:"issueType,omitempty"`
+	ExpectedValue string             `json:"expectedValue,omitempty"`
+	Value         string             `json:"value,omitempty"`
+	Filename      string             `json:"filename,omitempty"`
+	Snippet       *ScanResultSnippet `json:"snippet,omitempty"`
 }
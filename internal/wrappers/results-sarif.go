@@ -59,6 +59,11 @@ type SarifScanResult struct {
 	Message             SarifMessage            `json:"message"`
 	PartialFingerprints *SarifResultFingerprint `json:"partialFingerprints,omitempty"`
 	Locations           []SarifLocation         `json:"locations,omitempty"`
+	Properties          *SarifResultProperties  `json:"properties,omitempty"`
+}
+
+type SarifResultProperties struct {
+	Owners []string `json:"owners,omitempty"`
 }
 
 type SarifLocation struct {
@@ -68,6 +73,17 @@ type SarifLocation struct {
 type SarifPhysicalLocation struct {
 	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
 	Region           *SarifRegion          `json:"region,omitempty"`
+	ContextRegion    *SarifContextRegion   `json:"contextRegion,omitempty"`
+}
+
+type SarifContextRegion struct {
+	StartLine uint          `json:"startLine"`
+	EndLine   uint          `json:"endLine"`
+	Snippet   *SarifSnippet `json:"snippet,omitempty"`
+}
+
+type SarifSnippet struct {
+	Text string `json:"text"`
 }
 
 type SarifRegion struct {
@@ -30,6 +30,29 @@ type ResultSummary struct {
 	BranchName      string
 	ScanInfoMessage string
 	EnginesEnabled  []string
+	Owners          []OwnerSummary
+	Snippets        []SnippetSummary
+}
+
+// SnippetSummary A location of an exploitable result with the lines of the local checkout around it
+type SnippetSummary struct {
+	Type     string
+	Severity string
+	Name     string
+	FileName string
+	Line     uint
+	Lines    []SourceLine
+	Drifted  bool
+}
+
+// OwnerSummary Counts of the results owned by a code owner
+type OwnerSummary struct {
+	Owner        string
+	TotalIssues  int
+	HighIssues   int
+	MediumIssues int
+	LowIssues    int
+	InfoIssues   int
 }
 
 type APISecResult struct {
@@ -383,6 +406,39 @@ const summaryTemplateHeader = `{{define "SummaryTemplate"}}
             padding: 16px 20px;
             width: 24.5%;
         }
+        .owners-row {
+            padding: 20px;
+            width: 100%;
+        }
+
+        .owners-row table {
+            border-collapse: collapse;
+            width: 100%;
+        }
+
+        .owners-row th, .owners-row td {
+            border-bottom: 1px solid #dad8dc;
+            padding: 6px;
+            text-align: left;
+        }
+        .snippets-row {
+            padding: 20px;
+            width: 100%;
+        }
+
+        .snippet {
+            margin-top: 12px;
+        }
+
+        .snippet pre {
+            background: #f5f5f7;
+            overflow-x: auto;
+            padding: 8px;
+        }
+
+        .snippet .drifted {
+            color: #f1605d;
+        }
         .cx-details { 
             color: black;
             align-items: center;
@@ -523,6 +579,29 @@ const nonAsyncSummary = `<div class="top-row">
  					<div class="total">{{.APISecurity.TotalRisksCount}}</div>
                 </div>
 		</div>
+        {{end}}
+        {{if .Owners}}
+        <hr>
+        <div class="owners-row">
+            <div class="total">Vulnerabilities per Owner</div>
+            <table>
+                <tr><th>Owner</th><th>High</th><th>Medium</th><th>Low</th><th>Info</th><th>Total</th></tr>
+                {{range .Owners}}<tr><td>{{html .Owner}}</td><td>{{.HighIssues}}</td><td>{{.MediumIssues}}</td><td>{{.LowIssues}}</td><td>{{.InfoIssues}}</td><td>{{.TotalIssues}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+        {{end}}
+        {{if .Snippets}}
+        <hr>
+        <div class="snippets-row">
+            <div class="total">Code Snippets</div>
+            {{range .Snippets}}<div class="snippet">
+                <div><b>{{html .Name}}</b> | {{.Severity}} | {{html .FileName}}:{{.Line}}{{if .Drifted}} | <span class="drifted">The local source differs from the scanned revision</span>{{end}}</div>
+                {{if .Lines}}<pre>{{range .Lines}}{{.Number}} | {{html .Text}}
+{{end}}</pre>{{end}}
+            </div>
+            {{end}}
+        </div>
         {{end}}`
 
 const asyncSummaryTemplate = `<div class="cx-info">
@@ -575,6 +654,25 @@ const SummaryMarkdownTemplate = `
 |:---------:|:---------:|
 | {{.APISecurity.APICount}} | {{.APISecurity.TotalRisksCount}} |
 {{end}}
+{{if .Owners}}
+### Vulnerabilities per Owner
+
+| Owner | 🔴 High | 🟡 Medium | ⚪ Low | ⚪ Info | Total |
+|:----------|:----------:|:------------:|:---------:|:----------:|:----------:|
+{{range .Owners}}| {{.Owner}} | {{.HighIssues}} | {{.MediumIssues}} | {{.LowIssues}} | {{.InfoIssues}} | {{.TotalIssues}} |
+{{end}}
+{{end}}
+{{if .Snippets}}
+### Code Snippets
+{{range .Snippets}}
+**{{.Name}}** | {{.Severity}} | ` + "`{{.FileName}}:{{.Line}}`" + `{{if .Drifted}} | ⚠️ The local source differs from the scanned revision{{end}}
+{{if .Lines}}
+` + "```" + `
+{{range .Lines}}{{.Number}} | {{.Text}}
+{{end}}` + "```" + `
+{{end}}
+{{end}}
+{{end}}
 `
 
 func SummaryTemplate(isScanPending bool) string {
@@ -0,0 +1,136 @@
+package wrappers
+
+// TrendChartSeries A line of the trend chart, with its SVG polyline points
+type TrendChartSeries struct {
+	Name   string
+	Color  string
+	Points string
+}
+
+// TrendChartBar A bar of the new vs fixed chart
+type TrendChartBar struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+	Color  string
+	Title  string
+}
+
+const ResultsTrendTemplate = `{{define "TrendTemplate"}}
+<!DOCTYPE html>
+<html lang="en">
+
+<head>
+    <meta http-equiv="Content-type" content="text/html; charset=utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Checkmarx Results Trend</title>
+    <style type="text/css">
+        * {
+            box-sizing: border-box;
+            margin: 0;
+            padding: 0;
+        }
+
+        body {
+            color: #565360;
+            font-family: Roboto, Arial, sans-serif;
+            font-size: 13px;
+        }
+
+        .cx-main {
+            margin: 2rem auto;
+            width: 90%;
+        }
+
+        .header-row {
+            display: flex;
+            justify-content: center;
+            margin-bottom: 2rem;
+        }
+
+        .header-row .data {
+            margin-right: 20px;
+        }
+
+        .element {
+            -webkit-box-shadow: 0 2px 4px rgba(0, 0, 0, 0.15);
+            background: #fff;
+            border: 1px solid #dad8dc;
+            border-radius: 4px;
+            box-shadow: 0 2px 4px rgba(0, 0, 0, 0.15);
+            margin-bottom: 2rem;
+            padding: 1rem;
+        }
+
+        .element .total {
+            font-size: 16px;
+            font-weight: 700;
+            margin-bottom: 1rem;
+        }
+
+        .legend {
+            display: inline-block;
+            margin-right: 1rem;
+        }
+
+        .legend-dot {
+            border-radius: 50%;
+            display: inline-block;
+            height: 10px;
+            margin-right: 4px;
+            width: 10px;
+        }
+
+        table {
+            border-collapse: collapse;
+            width: 100%;
+        }
+
+        th, td {
+            border-bottom: 1px solid #dad8dc;
+            padding: 6px;
+            text-align: left;
+        }
+    </style>
+</head>
+
+<body>
+    <div class="cx-main">
+        <div class="header-row">
+            <div class="data">Project ID: {{.ProjectID}}</div>
+            {{if .Branch}}<div class="data">Branch: {{.Branch}}</div>{{end}}
+            <div class="data">Since: {{.Since}}</div>
+            <div class="data">Scans: {{len .Scans}}</div>
+        </div>
+        <div class="element">
+            <div class="total">Vulnerabilities per Scan</div>
+            {{range .Series}}<div class="legend"><span class="legend-dot" style="background-color: {{.Color}}"></span>{{.Name}}</div>{{end}}
+            <svg viewBox="0 0 {{.Width}} {{.Height}}" width="100%" height="{{.Height}}">
+                <line x1="0" y1="{{.Height}}" x2="{{.Width}}" y2="{{.Height}}" stroke="#dad8dc"/>
+                {{range .Series}}<polyline fill="none" stroke="{{.Color}}" stroke-width="2" points="{{.Points}}"/>
+                {{end}}
+            </svg>
+        </div>
+        <div class="element">
+            <div class="total">New vs Fixed</div>
+            <div class="legend"><span class="legend-dot" style="background-color: #f1605d"></span>New</div>
+            <div class="legend"><span class="legend-dot" style="background-color: #0fcdc2"></span>Fixed</div>
+            <svg viewBox="0 0 {{.Width}} {{.Height}}" width="100%" height="{{.Height}}">
+                <line x1="0" y1="{{.Height}}" x2="{{.Width}}" y2="{{.Height}}" stroke="#dad8dc"/>
+                {{range .Bars}}<rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" fill="{{.Color}}"><title>{{.Title}}</title></rect>
+                {{end}}
+            </svg>
+        </div>
+        <div class="element">
+            <table>
+                <tr><th>Scan ID</th><th>Created at</th><th>Total</th><th>High</th><th>Medium</th><th>Low</th><th>Info</th><th>New</th><th>Fixed</th></tr>
+                {{range .Scans}}<tr><td>{{.ScanID}}</td><td>{{.CreatedAt.Format "2006-01-02 15:04"}}</td><td>{{.Total}}</td><td>{{.High}}</td><td>{{.Medium}}</td><td>{{.Low}}</td><td>{{.Info}}</td><td>{{.New}}</td><td>{{.Fixed}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+    </div>
+</body>
+</html>
+{{end}}
+`
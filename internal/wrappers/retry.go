@@ -0,0 +1,172 @@
+package wrappers
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	retryStatusSeparator      = ","
+	retryStatusRulesSeparator = "="
+	// maxBackoffShift Keep the exponential backoff from overflowing, the cap applies long before
+	maxBackoffShift = 30
+	maxHTTPStatus   = 599
+)
+
+// retrySleep Wait between attempts, replaced by the tests
+var retrySleep = time.Sleep
+
+// idempotentMethods The methods retried without --retry-post
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// retryPolicy When and how long to wait before repeating a request
+type retryPolicy struct {
+	retries   int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	retryPost bool
+	// statusRetries The retries of each retried status
+	statusRetries map[int]int
+}
+
+func getRetryPolicy() (*retryPolicy, error) {
+	policy := &retryPolicy{
+		retries:   int(viper.GetUint(commonParams.RetryFlag)),
+		baseDelay: time.Duration(viper.GetUint(commonParams.RetryDelayFlag)) * time.Second,
+		maxDelay:  time.Duration(viper.GetUint(commonParams.RetryMaxDelayFlag)) * time.Second,
+		retryPost: viper.GetBool(commonParams.RetryPostFlag),
+	}
+	if policy.maxDelay < policy.baseDelay {
+		policy.maxDelay = policy.baseDelay
+	}
+	rules := viper.GetString(commonParams.RetryStatusFlag)
+	if rules == "" {
+		rules = commonParams.RetryStatusDefault
+	}
+	statusRetries, err := parseRetryStatusRules(rules, policy.retries)
+	if err != nil {
+		return nil, err
+	}
+	policy.statusRetries = statusRetries
+	return policy, nil
+}
+
+// parseRetryStatusRules Parse rules such as "429=10,502,503" into the retries of each status
+func parseRetryStatusRules(rules string, defaultRetries int) (map[int]int, error) {
+	statusRetries := make(map[int]int)
+	for _, rule := range strings.Split(rules, retryStatusSeparator) {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		parts := strings.SplitN(rule, retryStatusRulesSeparator, commonParams.KeyValuePairSize)
+		status, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil || status < http.StatusContinue || status > maxHTTPStatus {
+			return nil, errors.Errorf("Invalid --%s rule %s: expected <status>[=<retries>]", commonParams.RetryStatusFlag, rule)
+		}
+		retries := defaultRetries
+		if len(parts) == commonParams.KeyValuePairSize {
+			retries, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil || retries < 0 {
+				return nil, errors.Errorf("Invalid --%s rule %s: expected <status>[=<retries>]", commonParams.RetryStatusFlag, rule)
+			}
+		}
+		statusRetries[status] = retries
+	}
+	return statusRetries, nil
+}
+
+// allowsStatusRetry Only idempotent requests are repeated on a retried status, unless POST requests were opted in
+// or the caller knows it is safe, the connection errors being retried whatever the method
+func (p *retryPolicy) allowsStatusRetry(req *http.Request, safe bool) bool {
+	return safe || idempotentMethods[req.Method] || (p.retryPost && req.Method == http.MethodPost)
+}
+
+// retriesFor The retries of a response status, false when the status isn't retried
+func (p *retryPolicy) retriesFor(status int) (int, bool) {
+	retries, ok := p.statusRetries[status]
+	return retries, ok
+}
+
+// backoff Exponential delay of the attempt, capped, with an equal jitter to spread the clients retrying together
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	if p.baseDelay <= 0 {
+		return 0
+	}
+	if attempt > maxBackoffShift {
+		attempt = maxBackoffShift
+	}
+	delay := p.baseDelay << uint(attempt)
+	if delay <= 0 || delay > p.maxDelay {
+		delay = p.maxDelay
+	}
+	half := delay / 2
+	//nolint:gosec // the jitter doesn't need a secure random
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// delay The Retry-After of the response when the server sent one, capped by --retry-max-delay, the backoff otherwise
+func (p *retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if retryAfter > p.maxDelay {
+				return p.maxDelay
+			}
+			return retryAfter
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// parseRetryAfter Read a Retry-After header holding either seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// discardResponse Release the connection of a response that will be retried
+func discardResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+}
+
+func logRetry(attempt int, reason string, wait time.Duration) {
+	logger.Debug(fmt.Sprintf("Request failed in attempt %d: %s, retrying in %s", attempt+1, reason, wait), logger.Fields{
+		"attempt": attempt + 1,
+		"reason":  reason,
+		"waitMs":  wait.Milliseconds(),
+	})
+}
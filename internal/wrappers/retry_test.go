@@ -0,0 +1,196 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+// useTestRetryPolicy Configure the retries and record the waits instead of sleeping
+func useTestRetryPolicy(t *testing.T, retries uint, rules string, retryPost bool) *[]time.Duration {
+	var waits []time.Duration
+	retrySleep = func(wait time.Duration) { waits = append(waits, wait) }
+	viper.Set(commonParams.RetryFlag, retries)
+	viper.Set(commonParams.RetryDelayFlag, 1)
+	viper.Set(commonParams.RetryMaxDelayFlag, 3)
+	viper.Set(commonParams.RetryStatusFlag, rules)
+	viper.Set(commonParams.RetryPostFlag, retryPost)
+	t.Cleanup(func() {
+		retrySleep = time.Sleep
+		viper.Set(commonParams.RetryFlag, commonParams.RetryDefault)
+		viper.Set(commonParams.RetryDelayFlag, commonParams.RetryDelayDefault)
+		viper.Set(commonParams.RetryMaxDelayFlag, commonParams.RetryMaxDelayDefault)
+		viper.Set(commonParams.RetryStatusFlag, commonParams.RetryStatusDefault)
+		viper.Set(commonParams.RetryPostFlag, false)
+	})
+	return &waits
+}
+
+// newStatusServer Answer the statuses in order, then 200
+func newStatusServer(t *testing.T, headers http.Header, statuses ...int) (server *httptest.Server, calls *int) {
+	calls = new(int)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		for name, values := range headers {
+			w.Header()[name] = values
+		}
+		if *calls <= len(statuses) {
+			w.WriteHeader(statuses[*calls-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+func TestRequestRetriesStatuses(t *testing.T) {
+	waits := useTestRetryPolicy(t, 3, commonParams.RetryStatusDefault, false)
+	server, calls := newStatusServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, *calls, 3)
+	assert.Equal(t, len(*waits), 2)
+	assert.Assert(t, (*waits)[0] >= 500*time.Millisecond && (*waits)[0] <= time.Second)
+	assert.Assert(t, (*waits)[1] >= time.Second && (*waits)[1] <= 2*time.Second)
+}
+
+func TestRequestReturnsLastStatusWhenRetriesExhausted(t *testing.T) {
+	waits := useTestRetryPolicy(t, 3, "503=1", false)
+	server, calls := newStatusServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	assert.Equal(t, *calls, 2)
+	assert.Equal(t, len(*waits), 1)
+}
+
+func TestRequestHonorsRetryAfter(t *testing.T) {
+	waits := useTestRetryPolicy(t, 3, commonParams.RetryStatusDefault, false)
+	server, calls := newStatusServer(t, http.Header{"Retry-After": {"2"}}, http.StatusTooManyRequests)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, *calls, 2)
+	assert.DeepEqual(t, *waits, []time.Duration{2 * time.Second})
+}
+
+func TestRequestCapsRetryAfter(t *testing.T) {
+	waits := useTestRetryPolicy(t, 3, commonParams.RetryStatusDefault, false)
+	server, _ := newStatusServer(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+	resp, err := request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.DeepEqual(t, *waits, []time.Duration{3 * time.Second})
+}
+
+func TestRequestRetriesConnectionErrorsOfAnyMethod(t *testing.T) {
+	waits := useTestRetryPolicy(t, 2, commonParams.RetryStatusDefault, false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+
+	_, err := request(http.DefaultClient, req, false)
+	assert.Assert(t, err != nil)
+	assert.Equal(t, len(*waits), 2)
+}
+
+func TestRequestRetriesPostOnlyWhenOptedIn(t *testing.T) {
+	useTestRetryPolicy(t, 3, commonParams.RetryStatusDefault, false)
+	server, calls := newStatusServer(t, nil, http.StatusServiceUnavailable)
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+
+	resp, err := request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	assert.Equal(t, *calls, 1)
+
+	useTestRetryPolicy(t, 3, commonParams.RetryStatusDefault, true)
+	server, calls = newStatusServer(t, nil, http.StatusServiceUnavailable)
+	req, _ = http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+
+	resp, err = request(server.Client(), req, false)
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, *calls, 2)
+}
+
+func TestRequestInvalidRetryStatus(t *testing.T) {
+	useTestRetryPolicy(t, 3, "429=many", false)
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+
+	_, err := request(http.DefaultClient, req, false)
+	assert.ErrorContains(t, err, "Invalid --retry-status rule 429=many")
+}
+
+func TestParseRetryStatusRules(t *testing.T) {
+	statusRetries, err := parseRetryStatusRules("429=10, 502,503=0", 3)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, statusRetries, map[int]int{429: 10, 502: 3, 503: 0})
+
+	_, err = parseRetryStatusRules("42", 3)
+	assert.ErrorContains(t, err, "Invalid --retry-status rule 42")
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	policy := &retryPolicy{baseDelay: time.Second, maxDelay: 3 * time.Second}
+	for attempt := 0; attempt < 100; attempt++ {
+		wait := policy.backoff(attempt)
+		assert.Assert(t, wait <= 3*time.Second, "attempt %d waits %s", attempt, wait)
+	}
+	assert.Assert(t, policy.backoff(10) >= 1500*time.Millisecond)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	wait, ok := parseRetryAfter("120", now)
+	assert.Assert(t, ok)
+	assert.Equal(t, wait, 2*time.Minute)
+
+	wait, ok = parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now)
+	assert.Assert(t, ok)
+	assert.Equal(t, wait, 30*time.Second)
+
+	wait, ok = parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)
+	assert.Assert(t, ok)
+	assert.Equal(t, wait, time.Duration(0))
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.Assert(t, !ok)
+}
+
+func TestRequestRetriesOnlyTheRepeatableGrants(t *testing.T) {
+	useTestRetryPolicy(t, 3, "503=2", false)
+	server, calls := newStatusServer(t, nil, http.StatusServiceUnavailable)
+	_, err := getNewTokenResponse(getCredentialsPayload("client", "secret"), server.URL)
+	assert.ErrorContains(t, err, "unexpected end of JSON input")
+	assert.Equal(t, *calls, 2)
+
+	server, calls = newStatusServer(t, nil, http.StatusServiceUnavailable)
+	_, err = getNewTokenResponse("grant_type=refresh_token&client_id=cx-login&refresh_token=rotated", server.URL)
+	assert.ErrorContains(t, err, "unexpected end of JSON input")
+	assert.Equal(t, *calls, 1)
+
+	server, calls = newStatusServer(t, nil, http.StatusServiceUnavailable)
+	assert.ErrorContains(t, postOAuthForm(server.URL, url.Values{"grant_type": {"authorization_code"}}, nil), "503")
+	assert.Equal(t, *calls, 1)
+}
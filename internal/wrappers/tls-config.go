@@ -0,0 +1,142 @@
+package wrappers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/checkmarx/ast-cli/internal/logger"
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	//nolint:staticcheck // the frozen pkcs12 package is the one able to read the client certificates exported by the PKIs
+	"golang.org/x/crypto/pkcs12"
+)
+
+const (
+	caCertsSeparator     = ","
+	pemBlockStart        = "-----BEGIN"
+	pemCertificateType   = "CERTIFICATE"
+	pkcs12LocalKeyHeader = "localKeyId"
+)
+
+// getTLSConfig The TLS configuration of the clients: the CA bundles appended to the system pool and the client certificate
+func getTLSConfig() (*tls.Config, error) {
+	//nolint:gosec // skipping the verification is the explicit choice of --insecure
+	config := &tls.Config{InsecureSkipVerify: viper.GetBool(commonParams.InsecureFlag)}
+	if caCerts := viper.GetString(commonParams.CACertKey); caCerts != "" {
+		pool, err := loadCACerts(strings.Split(caCerts, caCertsSeparator))
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+	certPath := viper.GetString(commonParams.ClientCertKey)
+	keyPath := viper.GetString(commonParams.ClientKeyKey)
+	if certPath == "" {
+		if keyPath != "" {
+			return nil, errors.Errorf("--%s requires --%s", commonParams.ClientKeyFlag, commonParams.ClientCertFlag)
+		}
+		return config, nil
+	}
+	certificate, err := loadClientCertificate(certPath, keyPath, viper.GetString(commonParams.ClientCertPasswordKey))
+	if err != nil {
+		return nil, err
+	}
+	config.Certificates = []tls.Certificate{certificate}
+	return config, nil
+}
+
+// loadCACerts Append the PEM bundles to the system pool, or to an empty pool where the system one is unavailable
+func loadCACerts(paths []string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		logger.PrintIfVerbose("System certificate pool unavailable, using the CA bundles only")
+		pool = x509.NewCertPool()
+	}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil, errors.Wrapf(readErr, "Failed to read the CA bundle %s", path)
+		}
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, errors.Errorf("No PEM certificate found in the CA bundle %s", path)
+		}
+		logger.PrintIfVerbose(fmt.Sprintf("Using the CA bundle %s", path))
+	}
+	return pool, nil
+}
+
+// loadClientCertificate Read a PEM certificate with its key, in the same file or in keyPath, or a PKCS#12 archive holding both
+func loadClientCertificate(certPath, keyPath, password string) (tls.Certificate, error) {
+	content, err := os.ReadFile(certPath)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "Failed to read the client certificate %s", certPath)
+	}
+	var certPEM, keyPEM []byte
+	if bytes.Contains(content, []byte(pemBlockStart)) {
+		certPEM, keyPEM = content, content
+		if keyPath != "" {
+			keyPEM, err = os.ReadFile(keyPath)
+			if err != nil {
+				return tls.Certificate{}, errors.Wrapf(err, "Failed to read the client key %s", keyPath)
+			}
+		}
+	} else {
+		certPEM, keyPEM, err = pkcs12ToPEM(content, password)
+		if err != nil {
+			return tls.Certificate{}, errors.Wrapf(err, "Failed to decode the PKCS#12 client certificate %s", certPath)
+		}
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "Failed to load the client certificate %s", certPath)
+	}
+	logger.PrintIfVerbose(fmt.Sprintf("Using the client certificate %s", certPath))
+	return certificate, nil
+}
+
+// pkcs12ToPEM Convert the archive to PEM, the certificate of the key first as tls.X509KeyPair expects the leaf first
+func pkcs12ToPEM(content []byte, password string) (certPEM, keyPEM []byte, err error) {
+	blocks, err := pkcs12.ToPEM(content, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyID := ""
+	for _, block := range blocks {
+		if block.Type != pemCertificateType {
+			keyID = block.Headers[pkcs12LocalKeyHeader]
+			keyPEM = append(keyPEM, pem.EncodeToMemory(block)...)
+		}
+	}
+	var chainPEM []byte
+	for _, block := range blocks {
+		if block.Type != pemCertificateType {
+			continue
+		}
+		if keyID != "" && block.Headers[pkcs12LocalKeyHeader] == keyID {
+			certPEM = append(pem.EncodeToMemory(block), certPEM...)
+		} else {
+			chainPEM = append(chainPEM, pem.EncodeToMemory(block)...)
+		}
+	}
+	return append(certPEM, chainPEM...), keyPEM, nil
+}
+
+// failingTransport Fail every request of a client whose TLS configuration can't be loaded
+type failingTransport struct {
+	err error
+}
+
+func (t *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
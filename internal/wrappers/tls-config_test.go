@@ -0,0 +1,124 @@
+//go:build !integration
+
+package wrappers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	commonParams "github.com/checkmarx/ast-cli/internal/params"
+	"github.com/spf13/viper"
+	"gotest.tools/assert"
+)
+
+// writeTestCertificate Write a self-signed certificate and its key as PEM files, returning their paths
+func writeTestCertificate(t *testing.T, dir, name string) (certPath, keyPath string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NilError(t, err)
+	certPath = filepath.Join(dir, name+".crt")
+	keyPath = filepath.Join(dir, name+".key")
+	assert.NilError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NilError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certPath, keyPath
+}
+
+func useTestTLSConfig(t *testing.T, caCert, clientCert, clientKey string) {
+	viper.Set(commonParams.CACertKey, caCert)
+	viper.Set(commonParams.ClientCertKey, clientCert)
+	viper.Set(commonParams.ClientKeyKey, clientKey)
+	t.Cleanup(func() {
+		viper.Set(commonParams.CACertKey, "")
+		viper.Set(commonParams.ClientCertKey, "")
+		viper.Set(commonParams.ClientKeyKey, "")
+	})
+}
+
+func TestGetTLSConfigDefault(t *testing.T) {
+	useTestTLSConfig(t, "", "", "")
+	config, err := getTLSConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, config.RootCAs == nil)
+	assert.Equal(t, len(config.Certificates), 0)
+}
+
+func TestGetTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := writeTestCertificate(t, dir, "client")
+
+	useTestTLSConfig(t, keyPath, "", "")
+	_, err := getTLSConfig()
+	assert.ErrorContains(t, err, "No PEM certificate found in the CA bundle")
+
+	useTestTLSConfig(t, filepath.Join(dir, "missing.crt"), "", "")
+	_, err = getTLSConfig()
+	assert.ErrorContains(t, err, "Failed to read the CA bundle")
+
+	useTestTLSConfig(t, "", "", keyPath)
+	_, err = getTLSConfig()
+	assert.ErrorContains(t, err, "--client-key requires --client-cert")
+
+	useTestTLSConfig(t, "", certPath, "")
+	_, err = getTLSConfig()
+	assert.ErrorContains(t, err, "Failed to load the client certificate")
+}
+
+func TestGetClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, clientKey := writeTestCertificate(t, dir, "client")
+	clientCA := x509.NewCertPool()
+	content, err := os.ReadFile(clientCert)
+	assert.NilError(t, err)
+	clientCA.AppendCertsFromPEM(content)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCA, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	serverCA := filepath.Join(dir, "server.crt")
+	assert.NilError(t, os.WriteFile(serverCA, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	useTestTLSConfig(t, serverCA, "", "")
+	_, err = GetClient(5).Get(server.URL)
+	assert.Assert(t, err != nil, "the server requires a client certificate")
+
+	useTestTLSConfig(t, serverCA, clientCert, clientKey)
+	resp, err := GetClient(5).Get(server.URL)
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+}
+
+func TestGetClientInvalidTLSConfig(t *testing.T) {
+	useTestTLSConfig(t, filepath.Join(t.TempDir(), "missing.crt"), "", "")
+	_, err := GetClient(5).Get("https://localhost")
+	assert.ErrorContains(t, err, "Failed to read the CA bundle")
+}
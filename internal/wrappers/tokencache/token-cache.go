@@ -0,0 +1,279 @@
+package tokencache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	FileName        = "tokens.cache"
+	filePermissions = 0600
+	lockSuffix      = ".lock"
+	lockRetryDelay  = 50 * time.Millisecond
+	lockTimeout     = 5 * time.Second
+	// staleLockAge A lock older than this was left by a killed process
+	staleLockAge = 30 * time.Second
+	keyInfo      = "ast-cli token cache"
+	keySize      = 32
+	expClaimKey  = "exp"
+)
+
+// Key Identify the tokens of a profile, tenant and client
+type Key struct {
+	Profile  string
+	Tenant   string
+	ClientID string
+	AuthURI  string
+}
+
+func (k Key) id() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{k.Profile, k.Tenant, k.ClientID, k.AuthURI}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+type entry struct {
+	Nonce     []byte    `json:"nonce"`
+	Token     []byte    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Cache Access tokens shared by the CLI invocations. Every token is encrypted with a key derived
+// from the secret of the credentials it was issued for, so reading it requires the same secret.
+type Cache struct {
+	path string
+}
+
+func New(path string) *Cache {
+	return &Cache{path: path}
+}
+
+// Get Return the token of the key when it is still valid at the given time
+func (c *Cache) Get(key Key, secret string, validAt time.Time) (string, error) {
+	unlock, err := c.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		return "", err
+	}
+	cached, ok := entries[key.id()]
+	if !ok || !validAt.Before(cached.ExpiresAt) {
+		return "", nil
+	}
+	aead, err := newAEAD(key, secret)
+	if err != nil {
+		return "", err
+	}
+	token, err := aead.Open(nil, cached.Nonce, cached.Token, []byte(key.id()))
+	if err != nil {
+		// Encrypted with other credentials of the same client
+		return "", nil
+	}
+	return string(token), nil
+}
+
+// Put Store the token of the key, dropping the expired ones
+func (c *Cache) Put(key Key, secret, token string, expiresAt time.Time) error {
+	aead, err := newAEAD(key, secret)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Wrap(err, "Failed to generate the token nonce")
+	}
+
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		entries = make(map[string]entry)
+	}
+	now := time.Now()
+	for id, cached := range entries {
+		if !now.Before(cached.ExpiresAt) {
+			delete(entries, id)
+		}
+	}
+	entries[key.id()] = entry{
+		Nonce:     nonce,
+		Token:     aead.Seal(nil, nonce, []byte(token), []byte(key.id())),
+		ExpiresAt: expiresAt,
+	}
+	return c.write(entries)
+}
+
+// Delete Remove the token of the key
+func (c *Cache) Delete(key Key) error {
+	unlock, err := c.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := c.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key.id()]; !ok {
+		return nil
+	}
+	delete(entries, key.id())
+	return c.write(entries)
+}
+
+func (c *Cache) read() (map[string]entry, error) {
+	entries := make(map[string]entry)
+	content, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the token cache %s", c.path)
+	}
+	if err = json.Unmarshal(content, &entries); err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse the token cache %s", c.path)
+	}
+	return entries, nil
+}
+
+// write Replace the cache file at once, so that a reader never sees a partial file
+func (c *Cache) write(entries map[string]entry) error {
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), os.ModePerm); err != nil {
+		return errors.Wrapf(err, "Failed to create the token cache directory of %s", c.path)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to write the token cache %s", c.path)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), filePermissions)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	return errors.Wrapf(err, "Failed to write the token cache %s", c.path)
+}
+
+// lock Take the lock file of the cache, shared by all the processes
+func (c *Cache) lock() (func(), error) {
+	lockPath := c.path + lockSuffix
+	if err := os.MkdirAll(filepath.Dir(lockPath), os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create the token cache directory of %s", c.path)
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, filePermissions)
+		if err == nil {
+			_ = file.Close()
+			return func() {
+				_ = os.Remove(lockPath)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrapf(err, "Failed to lock the token cache %s", c.path)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("Timed out waiting for the token cache lock %s", lockPath)
+		}
+		time.Sleep(lockRetryDelay)
+	}
+}
+
+// LoadOrCreateSecret Read the random secret of the file, creating it on first use
+func LoadOrCreateSecret(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err == nil && len(content) > 0 {
+		return string(content), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrapf(err, "Failed to read the secret %s", path)
+	}
+	encoded, err := NewSecret()
+	if err != nil {
+		return "", err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return "", errors.Wrapf(err, "Failed to create the directory of %s", path)
+	}
+	if err = os.WriteFile(path, []byte(encoded), filePermissions); err != nil {
+		return "", errors.Wrapf(err, "Failed to write the secret %s", path)
+	}
+	return encoded, nil
+}
+
+// NewSecret Generate a random secret
+func NewSecret() (string, error) {
+	secret := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return "", errors.Wrap(err, "Failed to generate the secret")
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func newAEAD(key Key, secret string) (cipher.AEAD, error) {
+	derived := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(secret), []byte(key.id()), []byte(keyInfo)), derived); err != nil {
+		return nil, errors.Wrap(err, "Failed to derive the token cache key")
+	}
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ExpiresAt Read the expiry of a JWT from its exp claim
+func ExpiresAt(token string) (time.Time, bool) {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}, false
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch exp := claims[expClaimKey].(type) {
+	case float64:
+		return time.Unix(int64(exp), 0), true
+	case json.Number:
+		seconds, numberErr := exp.Int64()
+		return time.Unix(seconds, 0), numberErr == nil
+	}
+	return time.Time{}, false
+}
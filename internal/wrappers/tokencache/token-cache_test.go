@@ -0,0 +1,137 @@
+//go:build !integration
+
+package tokencache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"gotest.tools/assert"
+)
+
+func newTestCache(t *testing.T) *Cache {
+	return New(filepath.Join(t.TempDir(), FileName))
+}
+
+func TestCacheGetPut(t *testing.T) {
+	cache := newTestCache(t)
+	key := Key{Profile: "default", Tenant: "tenant", ClientID: "client", AuthURI: "https://iam"}
+	expiresAt := time.Now().Add(time.Hour)
+
+	token, err := cache.Get(key, "secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "")
+
+	assert.NilError(t, cache.Put(key, "secret", "access-token", expiresAt))
+	token, err = cache.Get(key, "secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "access-token")
+
+	content, err := os.ReadFile(cache.path)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(content), "access-token"))
+	info, err := os.Stat(cache.path)
+	assert.NilError(t, err)
+	if os.PathSeparator == '/' {
+		assert.Equal(t, info.Mode().Perm(), os.FileMode(filePermissions))
+	}
+	_, err = os.Stat(cache.path + lockSuffix)
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+func TestCacheGetOtherCredentials(t *testing.T) {
+	cache := newTestCache(t)
+	key := Key{Profile: "default", Tenant: "tenant", ClientID: "client"}
+	assert.NilError(t, cache.Put(key, "secret", "access-token", time.Now().Add(time.Hour)))
+
+	token, err := cache.Get(key, "other-secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "")
+
+	token, err = cache.Get(Key{Profile: "other", Tenant: "tenant", ClientID: "client"}, "secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "")
+}
+
+func TestCacheGetExpired(t *testing.T) {
+	cache := newTestCache(t)
+	key := Key{ClientID: "client"}
+	expiresAt := time.Now().Add(time.Minute)
+	assert.NilError(t, cache.Put(key, "secret", "access-token", expiresAt))
+
+	token, err := cache.Get(key, "secret", expiresAt)
+	assert.NilError(t, err)
+	assert.Equal(t, token, "")
+
+	assert.NilError(t, cache.Put(Key{ClientID: "other"}, "secret", "other-token", time.Now().Add(time.Hour)))
+	entries, err := cache.read()
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+}
+
+func TestCacheStaleLock(t *testing.T) {
+	cache := newTestCache(t)
+	lockPath := cache.path + lockSuffix
+	assert.NilError(t, os.WriteFile(lockPath, nil, filePermissions))
+	stale := time.Now().Add(-2 * staleLockAge)
+	assert.NilError(t, os.Chtimes(lockPath, stale, stale))
+
+	assert.NilError(t, cache.Put(Key{ClientID: "client"}, "secret", "access-token", time.Now().Add(time.Hour)))
+}
+
+func TestCacheInvalidFile(t *testing.T) {
+	cache := newTestCache(t)
+	assert.NilError(t, os.WriteFile(cache.path, []byte("{"), filePermissions))
+
+	_, err := cache.Get(Key{ClientID: "client"}, "secret", time.Now())
+	assert.ErrorContains(t, err, "Failed to parse the token cache")
+
+	assert.NilError(t, cache.Put(Key{ClientID: "client"}, "secret", "access-token", time.Now().Add(time.Hour)))
+	token, err := cache.Get(Key{ClientID: "client"}, "secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "access-token")
+}
+
+func TestExpiresAt(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": exp}).SignedString([]byte("key"))
+	assert.NilError(t, err)
+	expiresAt, ok := ExpiresAt(token)
+	assert.Assert(t, ok)
+	assert.Equal(t, expiresAt.Unix(), exp)
+
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"}).SignedString([]byte("key"))
+	assert.NilError(t, err)
+	_, ok = ExpiresAt(token)
+	assert.Assert(t, !ok)
+
+	_, ok = ExpiresAt("not-a-token")
+	assert.Assert(t, !ok)
+}
+
+func TestCacheDelete(t *testing.T) {
+	cache := newTestCache(t)
+	key := Key{ClientID: "client"}
+	assert.NilError(t, cache.Delete(key))
+	assert.NilError(t, cache.Put(key, "secret", "access-token", time.Now().Add(time.Hour)))
+	assert.NilError(t, cache.Delete(key))
+
+	token, err := cache.Get(key, "secret", time.Now())
+	assert.NilError(t, err)
+	assert.Equal(t, token, "")
+}
+
+func TestLoadOrCreateSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "login.key")
+	secret, err := LoadOrCreateSecret(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(secret), 2*keySize)
+
+	again, err := LoadOrCreateSecret(path)
+	assert.NilError(t, err)
+	assert.Equal(t, again, secret)
+}